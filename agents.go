@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/roe-ai/roe-golang/generated"
 )
@@ -12,37 +14,72 @@ const maxBatchSize = 1000
 
 // RunOptions customizes a single agent run request. Options apply per call,
 // unlike Config.ExtraHeaders which applies to every request from the client.
+// RunOptions is a RequestOption and can be mixed with the generic options.
 //
 //	client.Agents.Run(agentID, 0, inputs, nil, roe.RunOptions{SkipCache: true})
 type RunOptions struct {
 	// SkipCache bypasses the job-result cache for this run, forcing a fresh
 	// execution. The fresh result still refreshes the cache afterwards.
 	SkipCache bool
-}
 
-// resolveRunOptions collapses the variadic options; when several are passed,
-// the last one wins.
-func resolveRunOptions(opts []RunOptions) RunOptions {
+	// IdempotencyKey is sent as the Idempotency-Key header so the server can
+	// deduplicate submissions. When empty, a random key is generated per call;
+	// either way the key is reused by every retry of that call. Supply your own
+	// (e.g. derived from a work-item ID) to resubmit safely after a restart.
+	// RunMany sends one request per chunk of inputs, so it suffixes the key
+	// with "-<chunk index>" when the inputs span more than one chunk.
+	//
+	// When the server recognises a key it replays the stored response, and the
+	// SDK returns it exactly like the original: the same job ID(s), so waiting
+	// on the returned Job observes the job created by the first submission. A
+	// 409 Conflict (key reused with different inputs, or the original request
+	// still in flight) is returned as *ConflictError and is not retried.
+	IdempotencyKey string
+}
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+func (o RunOptions) applyRequestOption(ro *requestOptions) { ro.run = &o }
+
+// resolveRunOptions picks the RunOptions out of a call's options; when several
+// are passed, the last one wins. It also fills in an idempotency key when none
+// is set.
+func resolveRunOptions(opts []RequestOption) RunOptions {
 	var ro RunOptions
-	for _, opt := range opts {
-		ro = opt
+	if run := newRequestOptions(opts).run; run != nil {
+		ro = *run
+	}
+	if ro.IdempotencyKey == "" {
+		ro.IdempotencyKey = randomToken("roe-idem-")
 	}
 	return ro
 }
 
 func (o RunOptions) extraHeaders() http.Header {
-	if !o.SkipCache {
-		return nil
-	}
 	h := http.Header{}
-	h.Set("X-Skip-Cache", "true")
+	if o.SkipCache {
+		h.Set("X-Skip-Cache", "true")
+	}
+	if o.IdempotencyKey != "" {
+		h.Set(idempotencyKeyHeader, o.IdempotencyKey)
+	}
 	return h
 }
 
+// chunkHeaders returns the headers for chunk index of a RunMany submission
+// split into total chunks.
+func (o RunOptions) chunkHeaders(index, total int) http.Header {
+	if total > 1 && o.IdempotencyKey != "" {
+		o.IdempotencyKey = fmt.Sprintf("%s-%d", o.IdempotencyKey, index)
+	}
+	return o.extraHeaders()
+}
+
 // AgentsAPI manages agent operations.
 type AgentsAPI struct {
 	cfg        Config
 	httpClient *httpClient
+	poller     *jobPoller
 	Versions   *AgentVersionsAPI
 	Jobs       *AgentJobsAPI
 }
@@ -51,16 +88,21 @@ func newAgentsAPI(cfg Config, httpClient *httpClient) *AgentsAPI {
 	api := &AgentsAPI{cfg: cfg, httpClient: httpClient}
 	api.Versions = &AgentVersionsAPI{agentsAPI: api}
 	api.Jobs = &AgentJobsAPI{agentsAPI: api}
+	if cfg.SharedJobPolling {
+		api.poller = newJobPoller(api, cfg.SharedPollInterval)
+	}
 	return api
 }
 
 // List returns paginated agents.
-func (a *AgentsAPI) List(page, pageSize int) (PaginatedResponse[BaseAgent], error) {
-	return a.ListWithContext(context.Background(), page, pageSize)
+func (a *AgentsAPI) List(page, pageSize int, opts ...RequestOption) (PaginatedResponse[BaseAgent], error) {
+	return a.ListWithContext(context.Background(), page, pageSize, opts...)
 }
 
 // ListWithContext returns paginated agents with a caller-supplied context.
-func (a *AgentsAPI) ListWithContext(ctx context.Context, page, pageSize int) (PaginatedResponse[BaseAgent], error) {
+func (a *AgentsAPI) ListWithContext(ctx context.Context, page, pageSize int, opts ...RequestOption) (_ PaginatedResponse[BaseAgent], err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "AgentsAPI.List")
+	defer endSpan(span, &err)
 	params := map[string]string{
 		"organization_id": a.cfg.OrganizationID,
 	}
@@ -71,7 +113,7 @@ func (a *AgentsAPI) ListWithContext(ctx context.Context, page, pageSize int) (Pa
 		params["page_size"] = fmt.Sprintf("%d", pageSize)
 	}
 	var resp PaginatedResponse[BaseAgent]
-	if err := a.httpClient.getWithContext(ctx, "/v1/agents/", params, &resp); err != nil {
+	if err := a.httpClient.getWithContext(ctx, "/v1/agents/", params, &resp, opts...); err != nil {
 		return PaginatedResponse[BaseAgent]{}, err
 	}
 	for i := range resp.Results {
@@ -81,17 +123,19 @@ func (a *AgentsAPI) ListWithContext(ctx context.Context, page, pageSize int) (Pa
 }
 
 // Retrieve fetches an agent.
-func (a *AgentsAPI) Retrieve(agentID string) (BaseAgent, error) {
-	return a.RetrieveWithContext(context.Background(), agentID)
+func (a *AgentsAPI) Retrieve(agentID string, opts ...RequestOption) (BaseAgent, error) {
+	return a.RetrieveWithContext(context.Background(), agentID, opts...)
 }
 
 // RetrieveWithContext fetches an agent with a caller-supplied context.
-func (a *AgentsAPI) RetrieveWithContext(ctx context.Context, agentID string) (BaseAgent, error) {
+func (a *AgentsAPI) RetrieveWithContext(ctx context.Context, agentID string, opts ...RequestOption) (_ BaseAgent, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "AgentsAPI.Retrieve", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	if agentID == "" {
 		return BaseAgent{}, fmt.Errorf("agentID cannot be empty")
 	}
 	var resp BaseAgent
-	if err := a.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/%s/", agentID), nil, &resp); err != nil {
+	if err := a.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/%s/", agentID), nil, &resp, opts...); err != nil {
 		return BaseAgent{}, fmt.Errorf("retrieve agent %s: %w", agentID, err)
 	}
 	resp.setAgentsAPI(a)
@@ -99,12 +143,14 @@ func (a *AgentsAPI) RetrieveWithContext(ctx context.Context, agentID string) (Ba
 }
 
 // Create creates a new agent.
-func (a *AgentsAPI) Create(name, engineClassID string, inputDefs []map[string]any, engineConfig map[string]any, versionName, description string) (BaseAgent, error) {
-	return a.CreateWithContext(context.Background(), name, engineClassID, inputDefs, engineConfig, versionName, description)
+func (a *AgentsAPI) Create(name, engineClassID string, inputDefs []map[string]any, engineConfig map[string]any, versionName, description string, opts ...RequestOption) (BaseAgent, error) {
+	return a.CreateWithContext(context.Background(), name, engineClassID, inputDefs, engineConfig, versionName, description, opts...)
 }
 
 // CreateWithContext creates a new agent with a caller-supplied context.
-func (a *AgentsAPI) CreateWithContext(ctx context.Context, name, engineClassID string, inputDefs []map[string]any, engineConfig map[string]any, versionName, description string) (BaseAgent, error) {
+func (a *AgentsAPI) CreateWithContext(ctx context.Context, name, engineClassID string, inputDefs []map[string]any, engineConfig map[string]any, versionName, description string, opts ...RequestOption) (_ BaseAgent, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "AgentsAPI.Create")
+	defer endSpan(span, &err)
 	payload := map[string]any{
 		"name":              name,
 		"engine_class_id":   engineClassID,
@@ -119,7 +165,7 @@ func (a *AgentsAPI) CreateWithContext(ctx context.Context, name, engineClassID s
 		payload["description"] = description
 	}
 	var resp BaseAgent
-	if err := a.httpClient.postJSONWithContext(ctx, "/v1/agents/", payload, nil, &resp); err != nil {
+	if err := a.httpClient.postJSONWithContext(ctx, "/v1/agents/", payload, nil, &resp, opts...); err != nil {
 		return BaseAgent{}, err
 	}
 	resp.setAgentsAPI(a)
@@ -127,15 +173,17 @@ func (a *AgentsAPI) CreateWithContext(ctx context.Context, name, engineClassID s
 }
 
 // Update updates mutable fields on an agent.
-func (a *AgentsAPI) Update(agentID string, name string, disableCache, cacheFailedJobs *bool) (BaseAgent, error) {
-	return a.UpdateWithContext(context.Background(), agentID, name, disableCache, cacheFailedJobs)
+func (a *AgentsAPI) Update(agentID string, name string, disableCache, cacheFailedJobs *bool, opts ...RequestOption) (BaseAgent, error) {
+	return a.UpdateWithContext(context.Background(), agentID, name, disableCache, cacheFailedJobs, opts...)
 }
 
 // UpdateWithContext updates mutable fields on an agent with a caller-supplied context.
-func (a *AgentsAPI) UpdateWithContext(ctx context.Context, agentID string, name string, disableCache, cacheFailedJobs *bool) (BaseAgent, error) {
+func (a *AgentsAPI) UpdateWithContext(ctx context.Context, agentID string, name string, disableCache, cacheFailedJobs *bool, opts ...RequestOption) (_ BaseAgent, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "AgentsAPI.Update", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	payload := agentUpdatePayload(name, disableCache, cacheFailedJobs)
 	var resp BaseAgent
-	if err := a.httpClient.patchJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/", agentID), payload, nil, &resp); err != nil {
+	if err := a.httpClient.patchJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/", agentID), payload, nil, &resp, opts...); err != nil {
 		return BaseAgent{}, err
 	}
 	resp.setAgentsAPI(a)
@@ -143,15 +191,17 @@ func (a *AgentsAPI) UpdateWithContext(ctx context.Context, agentID string, name
 }
 
 // Replace replaces an agent.
-func (a *AgentsAPI) Replace(agentID string, name string, disableCache, cacheFailedJobs *bool) (BaseAgent, error) {
-	return a.ReplaceWithContext(context.Background(), agentID, name, disableCache, cacheFailedJobs)
+func (a *AgentsAPI) Replace(agentID string, name string, disableCache, cacheFailedJobs *bool, opts ...RequestOption) (BaseAgent, error) {
+	return a.ReplaceWithContext(context.Background(), agentID, name, disableCache, cacheFailedJobs, opts...)
 }
 
 // ReplaceWithContext replaces an agent with a caller-supplied context.
-func (a *AgentsAPI) ReplaceWithContext(ctx context.Context, agentID string, name string, disableCache, cacheFailedJobs *bool) (BaseAgent, error) {
+func (a *AgentsAPI) ReplaceWithContext(ctx context.Context, agentID string, name string, disableCache, cacheFailedJobs *bool, opts ...RequestOption) (_ BaseAgent, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "AgentsAPI.Replace", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	payload := agentReplacePayload(name, disableCache, cacheFailedJobs)
 	var resp BaseAgent
-	if err := a.httpClient.putJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/", agentID), payload, nil, &resp); err != nil {
+	if err := a.httpClient.putJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/", agentID), payload, nil, &resp, opts...); err != nil {
 		return BaseAgent{}, err
 	}
 	resp.setAgentsAPI(a)
@@ -186,32 +236,36 @@ func agentReplacePayload(name string, disableCache, cacheFailedJobs *bool) map[s
 }
 
 // Delete removes an agent.
-func (a *AgentsAPI) Delete(agentID string) error {
-	return a.DeleteWithContext(context.Background(), agentID)
+func (a *AgentsAPI) Delete(agentID string, opts ...RequestOption) error {
+	return a.DeleteWithContext(context.Background(), agentID, opts...)
 }
 
 // DeleteWithContext removes an agent with a caller-supplied context.
-func (a *AgentsAPI) DeleteWithContext(ctx context.Context, agentID string) error {
+func (a *AgentsAPI) DeleteWithContext(ctx context.Context, agentID string, opts ...RequestOption) (err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "AgentsAPI.Delete", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	if agentID == "" {
 		return fmt.Errorf("agentID cannot be empty")
 	}
-	if err := a.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/agents/%s/", agentID), nil); err != nil {
+	if err := a.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/agents/%s/", agentID), nil, opts...); err != nil {
 		return fmt.Errorf("delete agent %s: %w", agentID, err)
 	}
 	return nil
 }
 
 // Duplicate clones an agent.
-func (a *AgentsAPI) Duplicate(agentID string) (BaseAgent, error) {
-	return a.DuplicateWithContext(context.Background(), agentID)
+func (a *AgentsAPI) Duplicate(agentID string, opts ...RequestOption) (BaseAgent, error) {
+	return a.DuplicateWithContext(context.Background(), agentID, opts...)
 }
 
 // DuplicateWithContext clones an agent with a caller-supplied context.
-func (a *AgentsAPI) DuplicateWithContext(ctx context.Context, agentID string) (BaseAgent, error) {
+func (a *AgentsAPI) DuplicateWithContext(ctx context.Context, agentID string, opts ...RequestOption) (_ BaseAgent, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "AgentsAPI.Duplicate", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	var resp struct {
 		BaseAgent BaseAgent `json:"base_agent"`
 	}
-	if err := a.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/duplicate/", agentID), nil, nil, &resp); err != nil {
+	if err := a.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/duplicate/", agentID), nil, nil, &resp, opts...); err != nil {
 		return BaseAgent{}, err
 	}
 	resp.BaseAgent.setAgentsAPI(a)
@@ -219,38 +273,44 @@ func (a *AgentsAPI) DuplicateWithContext(ctx context.Context, agentID string) (B
 }
 
 // Run starts an async job for the given agent.
-func (a *AgentsAPI) Run(agentID string, timeoutSeconds int, inputs map[string]any, metadata map[string]any, opts ...RunOptions) (*Job, error) {
+func (a *AgentsAPI) Run(agentID string, timeoutSeconds int, inputs map[string]any, metadata map[string]any, opts ...RequestOption) (*Job, error) {
 	return a.RunWithContext(context.Background(), agentID, timeoutSeconds, inputs, metadata, opts...)
 }
 
 // RunWithContext starts an async job with a caller-supplied context.
-func (a *AgentsAPI) RunWithContext(ctx context.Context, agentID string, timeoutSeconds int, inputs map[string]any, metadata map[string]any, opts ...RunOptions) (*Job, error) {
+func (a *AgentsAPI) RunWithContext(ctx context.Context, agentID string, timeoutSeconds int, inputs map[string]any, metadata map[string]any, opts ...RequestOption) (_ *Job, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "AgentsAPI.Run", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	if agentID == "" {
 		return nil, fmt.Errorf("agentID cannot be empty")
 	}
 	var jobID string
-	if err := a.httpClient.postDynamicInputsHeadersWithContext(ctx, fmt.Sprintf("/v1/agents/run/%s/async/", agentID), inputs, nil, &jobID, metadata, resolveRunOptions(opts).extraHeaders()); err != nil {
+	if err := a.httpClient.postDynamicInputsHeadersWithContext(ctx, fmt.Sprintf("/v1/agents/run/%s/async/", agentID), inputs, nil, &jobID, metadata, resolveRunOptions(opts).extraHeaders(), opts...); err != nil {
 		return nil, fmt.Errorf("run agent %s: %w", agentID, err)
 	}
+	span.SetAttributes(SpanAttribute{Key: AttrJobID, Value: jobID})
 	return newJob(a, jobID, timeoutSeconds), nil
 }
 
 // RunMany submits batch jobs.
-func (a *AgentsAPI) RunMany(agentID string, batchInputs []map[string]any, timeoutSeconds int, metadata map[string]any, opts ...RunOptions) (*JobBatch, error) {
+func (a *AgentsAPI) RunMany(agentID string, batchInputs []map[string]any, timeoutSeconds int, metadata map[string]any, opts ...RequestOption) (*JobBatch, error) {
 	return a.RunManyWithContext(context.Background(), agentID, batchInputs, timeoutSeconds, metadata, opts...)
 }
 
 // RunManyWithContext submits batch jobs with a caller-supplied context.
-func (a *AgentsAPI) RunManyWithContext(ctx context.Context, agentID string, batchInputs []map[string]any, timeoutSeconds int, metadata map[string]any, opts ...RunOptions) (*JobBatch, error) {
+func (a *AgentsAPI) RunManyWithContext(ctx context.Context, agentID string, batchInputs []map[string]any, timeoutSeconds int, metadata map[string]any, opts ...RequestOption) (_ *JobBatch, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "AgentsAPI.RunMany", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	if agentID == "" {
 		return nil, fmt.Errorf("agentID cannot be empty")
 	}
 	if len(batchInputs) == 0 {
 		return nil, fmt.Errorf("batchInputs cannot be empty")
 	}
-	extraHeaders := resolveRunOptions(opts).extraHeaders()
+	runOpts := resolveRunOptions(opts)
+	chunks := chunkAny(batchInputs, maxBatchSize)
 	jobIDs := []string{}
-	for _, chunk := range chunkAny(batchInputs, maxBatchSize) {
+	for i, chunk := range chunks {
 		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
@@ -259,38 +319,43 @@ func (a *AgentsAPI) RunManyWithContext(ctx context.Context, agentID string, batc
 		if metadata != nil {
 			payload["metadata"] = metadata
 		}
-		if err := a.httpClient.postJSONHeadersWithContext(ctx, fmt.Sprintf("/v1/agents/run/%s/async/many/", agentID), payload, nil, &ids, extraHeaders); err != nil {
+		if err := a.httpClient.postJSONHeadersWithContext(ctx, fmt.Sprintf("/v1/agents/run/%s/async/many/", agentID), payload, nil, &ids, runOpts.chunkHeaders(i, len(chunks)), opts...); err != nil {
 			return nil, err
 		}
 		jobIDs = append(jobIDs, ids...)
 	}
+	span.SetAttributes(SpanAttribute{Key: AttrJobCount, Value: len(jobIDs)})
 	return newJobBatch(a, jobIDs, timeoutSeconds), nil
 }
 
 // RunSync runs synchronously and returns outputs.
-func (a *AgentsAPI) RunSync(agentID string, inputs map[string]any, metadata map[string]any, opts ...RunOptions) ([]AgentDatum, error) {
+func (a *AgentsAPI) RunSync(agentID string, inputs map[string]any, metadata map[string]any, opts ...RequestOption) ([]AgentDatum, error) {
 	return a.RunSyncWithContext(context.Background(), agentID, inputs, metadata, opts...)
 }
 
 // RunSyncWithContext runs synchronously with a caller-supplied context.
-func (a *AgentsAPI) RunSyncWithContext(ctx context.Context, agentID string, inputs map[string]any, metadata map[string]any, opts ...RunOptions) ([]AgentDatum, error) {
+func (a *AgentsAPI) RunSyncWithContext(ctx context.Context, agentID string, inputs map[string]any, metadata map[string]any, opts ...RequestOption) (_ []AgentDatum, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "AgentsAPI.RunSync", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	if agentID == "" {
 		return nil, fmt.Errorf("agentID cannot be empty")
 	}
 	var resp []AgentDatum
-	if err := a.httpClient.postDynamicInputsHeadersWithContext(ctx, fmt.Sprintf("/v1/agents/run/%s/", agentID), inputs, nil, &resp, metadata, resolveRunOptions(opts).extraHeaders()); err != nil {
+	if err := a.httpClient.postDynamicInputsHeadersWithContext(ctx, fmt.Sprintf("/v1/agents/run/%s/", agentID), inputs, nil, &resp, metadata, resolveRunOptions(opts).extraHeaders(), opts...); err != nil {
 		return nil, fmt.Errorf("run agent %s sync: %w", agentID, err)
 	}
 	return resp, nil
 }
 
 // RunVersion runs a specific version asynchronously.
-func (a *AgentsAPI) RunVersion(agentID, versionID string, timeoutSeconds int, inputs map[string]any, metadata map[string]any, opts ...RunOptions) (*Job, error) {
+func (a *AgentsAPI) RunVersion(agentID, versionID string, timeoutSeconds int, inputs map[string]any, metadata map[string]any, opts ...RequestOption) (*Job, error) {
 	return a.RunVersionWithContext(context.Background(), agentID, versionID, timeoutSeconds, inputs, metadata, opts...)
 }
 
 // RunVersionWithContext runs a specific version asynchronously with a caller-supplied context.
-func (a *AgentsAPI) RunVersionWithContext(ctx context.Context, agentID, versionID string, timeoutSeconds int, inputs map[string]any, metadata map[string]any, opts ...RunOptions) (*Job, error) {
+func (a *AgentsAPI) RunVersionWithContext(ctx context.Context, agentID, versionID string, timeoutSeconds int, inputs map[string]any, metadata map[string]any, opts ...RequestOption) (_ *Job, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "AgentsAPI.RunVersion", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	if agentID == "" {
 		return nil, fmt.Errorf("agentID cannot be empty")
 	}
@@ -299,19 +364,22 @@ func (a *AgentsAPI) RunVersionWithContext(ctx context.Context, agentID, versionI
 	}
 	var jobID string
 	url := fmt.Sprintf("/v1/agents/run/%s/versions/%s/async/", agentID, versionID)
-	if err := a.httpClient.postDynamicInputsHeadersWithContext(ctx, url, inputs, nil, &jobID, metadata, resolveRunOptions(opts).extraHeaders()); err != nil {
+	if err := a.httpClient.postDynamicInputsHeadersWithContext(ctx, url, inputs, nil, &jobID, metadata, resolveRunOptions(opts).extraHeaders(), opts...); err != nil {
 		return nil, fmt.Errorf("run agent %s version %s: %w", agentID, versionID, err)
 	}
+	span.SetAttributes(SpanAttribute{Key: AttrJobID, Value: jobID})
 	return newJob(a, jobID, timeoutSeconds), nil
 }
 
 // RunVersionSync runs a specific version synchronously.
-func (a *AgentsAPI) RunVersionSync(agentID, versionID string, inputs map[string]any, metadata map[string]any, opts ...RunOptions) ([]AgentDatum, error) {
+func (a *AgentsAPI) RunVersionSync(agentID, versionID string, inputs map[string]any, metadata map[string]any, opts ...RequestOption) ([]AgentDatum, error) {
 	return a.RunVersionSyncWithContext(context.Background(), agentID, versionID, inputs, metadata, opts...)
 }
 
 // RunVersionSyncWithContext runs a specific version synchronously with a caller-supplied context.
-func (a *AgentsAPI) RunVersionSyncWithContext(ctx context.Context, agentID, versionID string, inputs map[string]any, metadata map[string]any, opts ...RunOptions) ([]AgentDatum, error) {
+func (a *AgentsAPI) RunVersionSyncWithContext(ctx context.Context, agentID, versionID string, inputs map[string]any, metadata map[string]any, opts ...RequestOption) (_ []AgentDatum, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "AgentsAPI.RunVersionSync", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	if agentID == "" {
 		return nil, fmt.Errorf("agentID cannot be empty")
 	}
@@ -320,7 +388,7 @@ func (a *AgentsAPI) RunVersionSyncWithContext(ctx context.Context, agentID, vers
 	}
 	var resp []AgentDatum
 	url := fmt.Sprintf("/v1/agents/run/%s/versions/%s/", agentID, versionID)
-	if err := a.httpClient.postDynamicInputsHeadersWithContext(ctx, url, inputs, nil, &resp, metadata, resolveRunOptions(opts).extraHeaders()); err != nil {
+	if err := a.httpClient.postDynamicInputsHeadersWithContext(ctx, url, inputs, nil, &resp, metadata, resolveRunOptions(opts).extraHeaders(), opts...); err != nil {
 		return nil, fmt.Errorf("run agent %s version %s sync: %w", agentID, versionID, err)
 	}
 	return resp, nil
@@ -337,17 +405,19 @@ type ListVersionsParams struct {
 	GetSupportsEval *bool
 }
 
-func (v *AgentVersionsAPI) List(agentID string) ([]AgentVersion, error) {
-	return v.ListWithContext(context.Background(), agentID)
+func (v *AgentVersionsAPI) List(agentID string, opts ...RequestOption) ([]AgentVersion, error) {
+	return v.ListWithContext(context.Background(), agentID, opts...)
 }
 
-func (v *AgentVersionsAPI) ListWithContext(ctx context.Context, agentID string) ([]AgentVersion, error) {
+func (v *AgentVersionsAPI) ListWithContext(ctx context.Context, agentID string, opts ...RequestOption) (_ []AgentVersion, err error) {
+	ctx, span := v.agentsAPI.httpClient.startSpan(ctx, "AgentVersionsAPI.List", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	if agentID == "" {
 		return nil, fmt.Errorf("agentID cannot be empty")
 	}
 	// Note: The versions endpoint returns a raw array, not a paginated response
 	var versions []AgentVersion
-	if err := v.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/", agentID), nil, &versions); err != nil {
+	if err := v.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/", agentID), nil, &versions, opts...); err != nil {
 		return nil, fmt.Errorf("list agent versions: %w", err)
 	}
 	for i := range versions {
@@ -356,11 +426,13 @@ func (v *AgentVersionsAPI) ListWithContext(ctx context.Context, agentID string)
 	return versions, nil
 }
 
-func (v *AgentVersionsAPI) ListPaginated(agentID string, params *ListVersionsParams) (PaginatedResponse[AgentVersion], error) {
-	return v.ListPaginatedWithContext(context.Background(), agentID, params)
+func (v *AgentVersionsAPI) ListPaginated(agentID string, params *ListVersionsParams, opts ...RequestOption) (PaginatedResponse[AgentVersion], error) {
+	return v.ListPaginatedWithContext(context.Background(), agentID, params, opts...)
 }
 
-func (v *AgentVersionsAPI) ListPaginatedWithContext(ctx context.Context, agentID string, params *ListVersionsParams) (PaginatedResponse[AgentVersion], error) {
+func (v *AgentVersionsAPI) ListPaginatedWithContext(ctx context.Context, agentID string, params *ListVersionsParams, opts ...RequestOption) (_ PaginatedResponse[AgentVersion], err error) {
+	ctx, span := v.agentsAPI.httpClient.startSpan(ctx, "AgentVersionsAPI.ListPaginated", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	if params != nil {
 		if params.Page > 0 {
@@ -374,7 +446,7 @@ func (v *AgentVersionsAPI) ListPaginatedWithContext(ctx context.Context, agentID
 		}
 	}
 	var resp PaginatedResponse[AgentVersion]
-	if err := v.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/", agentID), query, &resp); err != nil {
+	if err := v.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/", agentID), query, &resp, opts...); err != nil {
 		return PaginatedResponse[AgentVersion]{}, err
 	}
 	for i := range resp.Results {
@@ -383,53 +455,59 @@ func (v *AgentVersionsAPI) ListPaginatedWithContext(ctx context.Context, agentID
 	return resp, nil
 }
 
-func (v *AgentVersionsAPI) Retrieve(agentID, versionID string, getSupportsEval *bool) (AgentVersion, error) {
-	return v.RetrieveWithContext(context.Background(), agentID, versionID, getSupportsEval)
+func (v *AgentVersionsAPI) Retrieve(agentID, versionID string, getSupportsEval *bool, opts ...RequestOption) (AgentVersion, error) {
+	return v.RetrieveWithContext(context.Background(), agentID, versionID, getSupportsEval, opts...)
 }
 
-func (v *AgentVersionsAPI) RetrieveWithContext(ctx context.Context, agentID, versionID string, getSupportsEval *bool) (AgentVersion, error) {
+func (v *AgentVersionsAPI) RetrieveWithContext(ctx context.Context, agentID, versionID string, getSupportsEval *bool, opts ...RequestOption) (_ AgentVersion, err error) {
+	ctx, span := v.agentsAPI.httpClient.startSpan(ctx, "AgentVersionsAPI.Retrieve", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	params := map[string]string{}
 	if getSupportsEval != nil {
 		params["get_supports_eval"] = fmt.Sprintf("%t", *getSupportsEval)
 	}
 	var resp AgentVersion
-	if err := v.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/%s/", agentID, versionID), params, &resp); err != nil {
+	if err := v.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/%s/", agentID, versionID), params, &resp, opts...); err != nil {
 		return AgentVersion{}, err
 	}
 	resp.setAgentsAPI(v.agentsAPI)
 	return resp, nil
 }
 
-func (v *AgentVersionsAPI) RetrieveCurrent(agentID string) (AgentVersion, error) {
-	return v.RetrieveCurrentWithContext(context.Background(), agentID)
+func (v *AgentVersionsAPI) RetrieveCurrent(agentID string, opts ...RequestOption) (AgentVersion, error) {
+	return v.RetrieveCurrentWithContext(context.Background(), agentID, opts...)
 }
 
-func (v *AgentVersionsAPI) RetrieveCurrentWithContext(ctx context.Context, agentID string) (AgentVersion, error) {
-	return v.RetrieveCurrentWithEvalWithContext(ctx, agentID, nil)
+func (v *AgentVersionsAPI) RetrieveCurrentWithContext(ctx context.Context, agentID string, opts ...RequestOption) (AgentVersion, error) {
+	return v.RetrieveCurrentWithEvalWithContext(ctx, agentID, nil, opts...)
 }
 
-func (v *AgentVersionsAPI) RetrieveCurrentWithEval(agentID string, getSupportsEval *bool) (AgentVersion, error) {
-	return v.RetrieveCurrentWithEvalWithContext(context.Background(), agentID, getSupportsEval)
+func (v *AgentVersionsAPI) RetrieveCurrentWithEval(agentID string, getSupportsEval *bool, opts ...RequestOption) (AgentVersion, error) {
+	return v.RetrieveCurrentWithEvalWithContext(context.Background(), agentID, getSupportsEval, opts...)
 }
 
-func (v *AgentVersionsAPI) RetrieveCurrentWithEvalWithContext(ctx context.Context, agentID string, getSupportsEval *bool) (AgentVersion, error) {
+func (v *AgentVersionsAPI) RetrieveCurrentWithEvalWithContext(ctx context.Context, agentID string, getSupportsEval *bool, opts ...RequestOption) (_ AgentVersion, err error) {
+	ctx, span := v.agentsAPI.httpClient.startSpan(ctx, "AgentVersionsAPI.RetrieveCurrentWithEval", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	params := map[string]string{}
 	if getSupportsEval != nil {
 		params["get_supports_eval"] = fmt.Sprintf("%t", *getSupportsEval)
 	}
 	var resp AgentVersion
-	if err := v.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/current/", agentID), params, &resp); err != nil {
+	if err := v.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/current/", agentID), params, &resp, opts...); err != nil {
 		return AgentVersion{}, err
 	}
 	resp.setAgentsAPI(v.agentsAPI)
 	return resp, nil
 }
 
-func (v *AgentVersionsAPI) Create(agentID string, inputDefs []map[string]any, engineConfig map[string]any, versionName, description string) (AgentVersion, error) {
-	return v.CreateWithContext(context.Background(), agentID, inputDefs, engineConfig, versionName, description)
+func (v *AgentVersionsAPI) Create(agentID string, inputDefs []map[string]any, engineConfig map[string]any, versionName, description string, opts ...RequestOption) (AgentVersion, error) {
+	return v.CreateWithContext(context.Background(), agentID, inputDefs, engineConfig, versionName, description, opts...)
 }
 
-func (v *AgentVersionsAPI) CreateWithContext(ctx context.Context, agentID string, inputDefs []map[string]any, engineConfig map[string]any, versionName, description string) (AgentVersion, error) {
+func (v *AgentVersionsAPI) CreateWithContext(ctx context.Context, agentID string, inputDefs []map[string]any, engineConfig map[string]any, versionName, description string, opts ...RequestOption) (_ AgentVersion, err error) {
+	ctx, span := v.agentsAPI.httpClient.startSpan(ctx, "AgentVersionsAPI.Create", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	payload := map[string]any{
 		"input_definitions": inputDefs,
 		"engine_config":     engineConfig,
@@ -443,28 +521,32 @@ func (v *AgentVersionsAPI) CreateWithContext(ctx context.Context, agentID string
 	var respID struct {
 		ID string `json:"id"`
 	}
-	if err := v.agentsAPI.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/", agentID), payload, nil, &respID); err != nil {
+	if err := v.agentsAPI.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/", agentID), payload, nil, &respID, opts...); err != nil {
 		return AgentVersion{}, err
 	}
-	return v.RetrieveWithContext(ctx, agentID, respID.ID, nil)
+	return v.RetrieveWithContext(ctx, agentID, respID.ID, nil, opts...)
 }
 
-func (v *AgentVersionsAPI) Update(agentID, versionID, versionName, description string) error {
-	return v.UpdateWithContext(context.Background(), agentID, versionID, versionName, description)
+func (v *AgentVersionsAPI) Update(agentID, versionID, versionName, description string, opts ...RequestOption) error {
+	return v.UpdateWithContext(context.Background(), agentID, versionID, versionName, description, opts...)
 }
 
-func (v *AgentVersionsAPI) UpdateWithContext(ctx context.Context, agentID, versionID, versionName, description string) error {
+func (v *AgentVersionsAPI) UpdateWithContext(ctx context.Context, agentID, versionID, versionName, description string, opts ...RequestOption) (err error) {
+	ctx, span := v.agentsAPI.httpClient.startSpan(ctx, "AgentVersionsAPI.Update", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	payload := agentVersionUpdatePayload(versionName, description)
-	return v.agentsAPI.httpClient.patchJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/%s/", agentID, versionID), payload, nil, nil)
+	return v.agentsAPI.httpClient.patchJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/%s/", agentID, versionID), payload, nil, nil, opts...)
 }
 
-func (v *AgentVersionsAPI) Replace(agentID, versionID, versionName, description string) error {
-	return v.ReplaceWithContext(context.Background(), agentID, versionID, versionName, description)
+func (v *AgentVersionsAPI) Replace(agentID, versionID, versionName, description string, opts ...RequestOption) error {
+	return v.ReplaceWithContext(context.Background(), agentID, versionID, versionName, description, opts...)
 }
 
-func (v *AgentVersionsAPI) ReplaceWithContext(ctx context.Context, agentID, versionID, versionName, description string) error {
+func (v *AgentVersionsAPI) ReplaceWithContext(ctx context.Context, agentID, versionID, versionName, description string, opts ...RequestOption) (err error) {
+	ctx, span := v.agentsAPI.httpClient.startSpan(ctx, "AgentVersionsAPI.Replace", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	payload := agentVersionReplacePayload(versionName, description)
-	return v.agentsAPI.httpClient.putJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/%s/", agentID, versionID), payload, nil, nil)
+	return v.agentsAPI.httpClient.putJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/%s/", agentID, versionID), payload, nil, nil, opts...)
 }
 
 func agentVersionUpdatePayload(versionName, description string) map[string]any {
@@ -486,12 +568,14 @@ func agentVersionReplacePayload(versionName, description string) map[string]any
 	return payload
 }
 
-func (v *AgentVersionsAPI) Delete(agentID, versionID string) error {
-	return v.DeleteWithContext(context.Background(), agentID, versionID)
+func (v *AgentVersionsAPI) Delete(agentID, versionID string, opts ...RequestOption) error {
+	return v.DeleteWithContext(context.Background(), agentID, versionID, opts...)
 }
 
-func (v *AgentVersionsAPI) DeleteWithContext(ctx context.Context, agentID, versionID string) error {
-	return v.agentsAPI.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/%s/", agentID, versionID), nil)
+func (v *AgentVersionsAPI) DeleteWithContext(ctx context.Context, agentID, versionID string, opts ...RequestOption) (err error) {
+	ctx, span := v.agentsAPI.httpClient.startSpan(ctx, "AgentVersionsAPI.Delete", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
+	return v.agentsAPI.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/agents/%s/versions/%s/", agentID, versionID), nil, opts...)
 }
 
 // AgentJobsAPI handles job operations.
@@ -499,35 +583,41 @@ type AgentJobsAPI struct {
 	agentsAPI *AgentsAPI
 }
 
-func (j *AgentJobsAPI) RetrieveStatus(jobID string) (AgentJobStatus, error) {
-	return j.RetrieveStatusWithContext(context.Background(), jobID)
+func (j *AgentJobsAPI) RetrieveStatus(jobID string, opts ...RequestOption) (AgentJobStatus, error) {
+	return j.RetrieveStatusWithContext(context.Background(), jobID, opts...)
 }
 
-func (j *AgentJobsAPI) RetrieveStatusWithContext(ctx context.Context, jobID string) (AgentJobStatus, error) {
+func (j *AgentJobsAPI) RetrieveStatusWithContext(ctx context.Context, jobID string, opts ...RequestOption) (_ AgentJobStatus, err error) {
+	ctx, span := j.agentsAPI.httpClient.startSpan(ctx, "AgentJobsAPI.RetrieveStatus", SpanAttribute{Key: AttrJobID, Value: jobID})
+	defer endSpan(span, &err)
 	var resp AgentJobStatus
-	if err := j.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/jobs/%s/status/", jobID), nil, &resp); err != nil {
+	if err := j.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/jobs/%s/status/", jobID), nil, &resp, opts...); err != nil {
 		return AgentJobStatus{}, err
 	}
 	return resp, nil
 }
 
-func (j *AgentJobsAPI) RetrieveResult(jobID string) (AgentJobResult, error) {
-	return j.RetrieveResultWithContext(context.Background(), jobID)
+func (j *AgentJobsAPI) RetrieveResult(jobID string, opts ...RequestOption) (AgentJobResult, error) {
+	return j.RetrieveResultWithContext(context.Background(), jobID, opts...)
 }
 
-func (j *AgentJobsAPI) RetrieveResultWithContext(ctx context.Context, jobID string) (AgentJobResult, error) {
+func (j *AgentJobsAPI) RetrieveResultWithContext(ctx context.Context, jobID string, opts ...RequestOption) (_ AgentJobResult, err error) {
+	ctx, span := j.agentsAPI.httpClient.startSpan(ctx, "AgentJobsAPI.RetrieveResult", SpanAttribute{Key: AttrJobID, Value: jobID})
+	defer endSpan(span, &err)
 	var resp AgentJobResult
-	if err := j.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/jobs/%s/result/", jobID), nil, &resp); err != nil {
+	if err := j.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/jobs/%s/result/", jobID), nil, &resp, opts...); err != nil {
 		return AgentJobResult{}, err
 	}
 	return resp, nil
 }
 
-func (j *AgentJobsAPI) RetrieveStatusMany(jobIDs []string) ([]AgentJobStatusBatch, error) {
-	return j.RetrieveStatusManyWithContext(context.Background(), jobIDs)
+func (j *AgentJobsAPI) RetrieveStatusMany(jobIDs []string, opts ...RequestOption) ([]AgentJobStatusBatch, error) {
+	return j.RetrieveStatusManyWithContext(context.Background(), jobIDs, opts...)
 }
 
-func (j *AgentJobsAPI) RetrieveStatusManyWithContext(ctx context.Context, jobIDs []string) ([]AgentJobStatusBatch, error) {
+func (j *AgentJobsAPI) RetrieveStatusManyWithContext(ctx context.Context, jobIDs []string, opts ...RequestOption) (_ []AgentJobStatusBatch, err error) {
+	ctx, span := j.agentsAPI.httpClient.startSpan(ctx, "AgentJobsAPI.RetrieveStatusMany", SpanAttribute{Key: AttrJobCount, Value: len(jobIDs)})
+	defer endSpan(span, &err)
 	if len(jobIDs) == 0 {
 		return nil, nil
 	}
@@ -544,7 +634,7 @@ func (j *AgentJobsAPI) RetrieveStatusManyWithContext(ctx context.Context, jobIDs
 	for _, chunk := range chunkStrings(jobIDs, maxBatchSize) {
 		payload := map[string]any{"job_ids": chunk}
 		var resp []AgentJobStatusBatch
-		if err := j.agentsAPI.httpClient.postJSONWithContext(ctx, "/v1/agents/jobs/statuses/", payload, nil, &resp); err != nil {
+		if err := j.agentsAPI.httpClient.postJSONWithContext(ctx, "/v1/agents/jobs/statuses/", payload, nil, &resp, opts...); err != nil {
 			return nil, fmt.Errorf("retrieve job statuses: %w", err)
 		}
 		for _, st := range resp {
@@ -569,11 +659,13 @@ func (j *AgentJobsAPI) RetrieveStatusManyWithContext(ctx context.Context, jobIDs
 	return results, nil
 }
 
-func (j *AgentJobsAPI) RetrieveResultMany(jobIDs []string) ([]AgentJobResultBatch, error) {
-	return j.RetrieveResultManyWithContext(context.Background(), jobIDs)
+func (j *AgentJobsAPI) RetrieveResultMany(jobIDs []string, opts ...RequestOption) ([]AgentJobResultBatch, error) {
+	return j.RetrieveResultManyWithContext(context.Background(), jobIDs, opts...)
 }
 
-func (j *AgentJobsAPI) RetrieveResultManyWithContext(ctx context.Context, jobIDs []string) ([]AgentJobResultBatch, error) {
+func (j *AgentJobsAPI) RetrieveResultManyWithContext(ctx context.Context, jobIDs []string, opts ...RequestOption) (_ []AgentJobResultBatch, err error) {
+	ctx, span := j.agentsAPI.httpClient.startSpan(ctx, "AgentJobsAPI.RetrieveResultMany", SpanAttribute{Key: AttrJobCount, Value: len(jobIDs)})
+	defer endSpan(span, &err)
 	if len(jobIDs) == 0 {
 		return nil, nil
 	}
@@ -590,7 +682,7 @@ func (j *AgentJobsAPI) RetrieveResultManyWithContext(ctx context.Context, jobIDs
 	for _, chunk := range chunkStrings(jobIDs, maxBatchSize) {
 		payload := map[string]any{"job_ids": chunk}
 		var resp []AgentJobResultBatch
-		if err := j.agentsAPI.httpClient.postJSONWithContext(ctx, "/v1/agents/jobs/results/", payload, nil, &resp); err != nil {
+		if err := j.agentsAPI.httpClient.postJSONWithContext(ctx, "/v1/agents/jobs/results/", payload, nil, &resp, opts...); err != nil {
 			return nil, fmt.Errorf("retrieve job results: %w", err)
 		}
 		for _, st := range resp {
@@ -617,12 +709,14 @@ func (j *AgentJobsAPI) RetrieveResultManyWithContext(ctx context.Context, jobIDs
 
 // ListJobs returns an agent's jobs (paginated) with filter and sort options.
 // Pass 0 to omit page/pageSize and "" to omit any string filter.
-func (j *AgentJobsAPI) ListJobs(agentID string, page, pageSize int, statusCode, versionName, metadata, createdFrom, createdTo, search, ordering string) (PaginatedResponse[generated.ListAgentJob], error) {
-	return j.ListJobsWithContext(context.Background(), agentID, page, pageSize, statusCode, versionName, metadata, createdFrom, createdTo, search, ordering)
+func (j *AgentJobsAPI) ListJobs(agentID string, page, pageSize int, statusCode, versionName, metadata, createdFrom, createdTo, search, ordering string, opts ...RequestOption) (PaginatedResponse[generated.ListAgentJob], error) {
+	return j.ListJobsWithContext(context.Background(), agentID, page, pageSize, statusCode, versionName, metadata, createdFrom, createdTo, search, ordering, opts...)
 }
 
 // ListJobsWithContext returns an agent's jobs with a caller-supplied context.
-func (j *AgentJobsAPI) ListJobsWithContext(ctx context.Context, agentID string, page, pageSize int, statusCode, versionName, metadata, createdFrom, createdTo, search, ordering string) (PaginatedResponse[generated.ListAgentJob], error) {
+func (j *AgentJobsAPI) ListJobsWithContext(ctx context.Context, agentID string, page, pageSize int, statusCode, versionName, metadata, createdFrom, createdTo, search, ordering string, opts ...RequestOption) (_ PaginatedResponse[generated.ListAgentJob], err error) {
+	ctx, span := j.agentsAPI.httpClient.startSpan(ctx, "AgentJobsAPI.ListJobs", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	if agentID == "" {
 		return PaginatedResponse[generated.ListAgentJob]{}, fmt.Errorf("agentID cannot be empty")
 	}
@@ -649,31 +743,138 @@ func (j *AgentJobsAPI) ListJobsWithContext(ctx context.Context, agentID string,
 		}
 	}
 	var resp PaginatedResponse[generated.ListAgentJob]
-	if err := j.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/%s/jobs/", agentID), params, &resp); err != nil {
+	if err := j.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/%s/jobs/", agentID), params, &resp, opts...); err != nil {
 		return PaginatedResponse[generated.ListAgentJob]{}, err
 	}
 	return resp, nil
 }
 
-func (j *AgentJobsAPI) DownloadReference(jobID, resourceID string, asAttachment bool) ([]byte, error) {
-	return j.DownloadReferenceWithContext(context.Background(), jobID, resourceID, asAttachment)
+// Attach returns a Job handle for an existing job, for example one whose ID
+// arrived in a webhook or was saved by another process, so it can be waited
+// on like a job returned by Run. No request is made.
+func (j *AgentJobsAPI) Attach(jobID string) *Job {
+	return newJob(j.agentsAPI, jobID, 0)
+}
+
+// AttachBatch returns a JobBatch handle for existing jobs. Wait returns their
+// results in the order of jobIDs. No request is made.
+func (j *AgentJobsAPI) AttachBatch(jobIDs []string) *JobBatch {
+	return newJobBatch(j.agentsAPI, append([]string(nil), jobIDs...), 0)
+}
+
+// JobFilter selects an agent's jobs for AttachMatching. Zero fields do not
+// filter.
+type JobFilter struct {
+	// Statuses matches jobs in any of the listed statuses.
+	Statuses    []JobStatus
+	VersionName string
+	Metadata    string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Search      string
+}
+
+// AttachMatching lists the agent's jobs that match filter, across all pages,
+// and returns them as a JobBatch ordered by creation time, so in-flight work
+// can be waited on from a new process:
+//
+//	batch, err := client.Agents.Jobs.AttachMatching(agentID, roe.JobFilter{
+//		Statuses:    []roe.JobStatus{roe.JobPending, roe.JobStarted, roe.JobRetry},
+//		CreatedFrom: time.Now().Add(-24 * time.Hour),
+//	})
+func (j *AgentJobsAPI) AttachMatching(agentID string, filter JobFilter, opts ...RequestOption) (*JobBatch, error) {
+	return j.AttachMatchingWithContext(context.Background(), agentID, filter, opts...)
+}
+
+// AttachMatchingWithContext is AttachMatching with a caller-supplied context.
+func (j *AgentJobsAPI) AttachMatchingWithContext(ctx context.Context, agentID string, filter JobFilter, opts ...RequestOption) (_ *JobBatch, err error) {
+	ctx, span := j.agentsAPI.httpClient.startSpan(ctx, "AgentJobsAPI.AttachMatching", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	createdFrom, createdTo := formatTime(filter.CreatedFrom), formatTime(filter.CreatedTo)
+	// status_code takes a single status, so each status is listed separately.
+	statusCodes := []string{""}
+	if len(filter.Statuses) > 0 {
+		statusCodes = statusCodes[:0]
+		for _, status := range filter.Statuses {
+			statusCodes = append(statusCodes, fmt.Sprint(int(status)))
+		}
+	}
+
+	type listedJob struct {
+		id        string
+		createdAt time.Time
+	}
+	var jobs []listedJob
+	seen := map[string]bool{}
+	for _, statusCode := range statusCodes {
+		// Page by a created_at cursor rather than by page number: jobs that
+		// leave the status while it is listed would shift later pages and
+		// hide jobs. Jobs at the cursor time are listed again and skipped,
+		// as are jobs already listed under another status.
+		cursor, page := createdFrom, 1
+		for {
+			resp, err := j.ListJobsWithContext(ctx, agentID, page, 100, statusCode, filter.VersionName, filter.Metadata, cursor, createdTo, filter.Search, "created_at", opts...)
+			if err != nil {
+				return nil, fmt.Errorf("attach jobs for agent %s: %w", agentID, err)
+			}
+			for _, job := range resp.Results {
+				if job.Id == nil || seen[job.Id.String()] {
+					continue
+				}
+				seen[job.Id.String()] = true
+				jobs = append(jobs, listedJob{id: job.Id.String(), createdAt: job.CreatedAt})
+			}
+			if !resp.HasNext() || len(resp.Results) == 0 {
+				break
+			}
+			// A full page created at the cursor time cannot move the
+			// cursor; step through those pages by number instead.
+			if next := formatTime(resp.Results[len(resp.Results)-1].CreatedAt); next != cursor {
+				cursor, page = next, 1
+			} else {
+				page++
+			}
+		}
+	}
+
+	sort.SliceStable(jobs, func(a, b int) bool { return jobs[a].createdAt.Before(jobs[b].createdAt) })
+	jobIDs := make([]string, len(jobs))
+	for i, job := range jobs {
+		jobIDs[i] = job.id
+	}
+	span.SetAttributes(SpanAttribute{Key: AttrJobCount, Value: len(jobIDs)})
+	return newJobBatch(j.agentsAPI, jobIDs, 0), nil
+}
+
+func (j *AgentJobsAPI) DownloadReference(jobID, resourceID string, asAttachment bool, opts ...RequestOption) ([]byte, error) {
+	return j.DownloadReferenceWithContext(context.Background(), jobID, resourceID, asAttachment, opts...)
 }
 
-func (j *AgentJobsAPI) DownloadReferenceWithContext(ctx context.Context, jobID, resourceID string, asAttachment bool) ([]byte, error) {
+func (j *AgentJobsAPI) DownloadReferenceWithContext(ctx context.Context, jobID, resourceID string, asAttachment bool, opts ...RequestOption) (_ []byte, err error) {
+	ctx, span := j.agentsAPI.httpClient.startSpan(ctx, "AgentJobsAPI.DownloadReference", SpanAttribute{Key: AttrJobID, Value: jobID})
+	defer endSpan(span, &err)
 	params := map[string]string{}
 	if asAttachment {
 		params["download"] = "true"
 	}
-	return j.agentsAPI.httpClient.getBytesWithContext(ctx, fmt.Sprintf("/v1/agents/jobs/%s/references/%s/", jobID, resourceID), params)
+	return j.agentsAPI.httpClient.getBytesWithContext(ctx, fmt.Sprintf("/v1/agents/jobs/%s/references/%s/", jobID, resourceID), params, opts...)
 }
 
 // RetrieveArtifact fetches a tool result artifact's result field by key.
-func (j *AgentJobsAPI) RetrieveArtifact(jobID, artifactKey string) (AgentJobArtifactResult, error) {
-	return j.RetrieveArtifactWithContext(context.Background(), jobID, artifactKey)
+func (j *AgentJobsAPI) RetrieveArtifact(jobID, artifactKey string, opts ...RequestOption) (AgentJobArtifactResult, error) {
+	return j.RetrieveArtifactWithContext(context.Background(), jobID, artifactKey, opts...)
 }
 
 // RetrieveArtifactWithContext fetches an artifact result with a caller-supplied context.
-func (j *AgentJobsAPI) RetrieveArtifactWithContext(ctx context.Context, jobID, artifactKey string) (AgentJobArtifactResult, error) {
+func (j *AgentJobsAPI) RetrieveArtifactWithContext(ctx context.Context, jobID, artifactKey string, opts ...RequestOption) (_ AgentJobArtifactResult, err error) {
+	ctx, span := j.agentsAPI.httpClient.startSpan(ctx, "AgentJobsAPI.RetrieveArtifact", SpanAttribute{Key: AttrJobID, Value: jobID})
+	defer endSpan(span, &err)
 	if jobID == "" {
 		return AgentJobArtifactResult{}, fmt.Errorf("jobID cannot be empty")
 	}
@@ -682,35 +883,39 @@ func (j *AgentJobsAPI) RetrieveArtifactWithContext(ctx context.Context, jobID, a
 	}
 	params := map[string]string{"artifact_key": artifactKey}
 	var resp AgentJobArtifactResult
-	if err := j.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/jobs/%s/artifacts/result/", jobID), params, &resp); err != nil {
+	if err := j.agentsAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/agents/jobs/%s/artifacts/result/", jobID), params, &resp, opts...); err != nil {
 		return AgentJobArtifactResult{}, err
 	}
 	return resp, nil
 }
 
-func (j *AgentJobsAPI) DeleteData(jobID string) (JobDataDeleteResponse, error) {
-	return j.DeleteDataWithContext(context.Background(), jobID)
+func (j *AgentJobsAPI) DeleteData(jobID string, opts ...RequestOption) (JobDataDeleteResponse, error) {
+	return j.DeleteDataWithContext(context.Background(), jobID, opts...)
 }
 
-func (j *AgentJobsAPI) DeleteDataWithContext(ctx context.Context, jobID string) (JobDataDeleteResponse, error) {
+func (j *AgentJobsAPI) DeleteDataWithContext(ctx context.Context, jobID string, opts ...RequestOption) (_ JobDataDeleteResponse, err error) {
+	ctx, span := j.agentsAPI.httpClient.startSpan(ctx, "AgentJobsAPI.DeleteData", SpanAttribute{Key: AttrJobID, Value: jobID})
+	defer endSpan(span, &err)
 	var resp JobDataDeleteResponse
-	if err := j.agentsAPI.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/agents/jobs/%s/delete-data/", jobID), nil, nil, &resp); err != nil {
+	if err := j.agentsAPI.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/agents/jobs/%s/delete-data/", jobID), nil, nil, &resp, opts...); err != nil {
 		return JobDataDeleteResponse{}, err
 	}
 	return resp, nil
 }
 
 // Cancel cancels a running job.
-func (j *AgentJobsAPI) Cancel(jobID string) error {
-	return j.CancelWithContext(context.Background(), jobID)
+func (j *AgentJobsAPI) Cancel(jobID string, opts ...RequestOption) error {
+	return j.CancelWithContext(context.Background(), jobID, opts...)
 }
 
 // CancelWithContext cancels a running job with a caller-supplied context.
-func (j *AgentJobsAPI) CancelWithContext(ctx context.Context, jobID string) error {
+func (j *AgentJobsAPI) CancelWithContext(ctx context.Context, jobID string, opts ...RequestOption) (err error) {
+	ctx, span := j.agentsAPI.httpClient.startSpan(ctx, "AgentJobsAPI.Cancel", SpanAttribute{Key: AttrJobID, Value: jobID})
+	defer endSpan(span, &err)
 	if jobID == "" {
 		return fmt.Errorf("jobID cannot be empty")
 	}
-	return j.agentsAPI.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/agents/jobs/%s/cancel/", jobID), nil, nil, nil)
+	return j.agentsAPI.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/agents/jobs/%s/cancel/", jobID), nil, nil, nil, opts...)
 }
 
 // ResendWebhook re-sends the completion webhook for a finished job. The job is
@@ -722,7 +927,9 @@ func (j *AgentJobsAPI) ResendWebhook(jobID string, webhookID ...string) (AgentJo
 
 // ResendWebhookWithContext re-sends a finished job's completion webhook with a
 // caller-supplied context.
-func (j *AgentJobsAPI) ResendWebhookWithContext(ctx context.Context, jobID string, webhookID ...string) (AgentJobWebhookResendResponse, error) {
+func (j *AgentJobsAPI) ResendWebhookWithContext(ctx context.Context, jobID string, webhookID ...string) (_ AgentJobWebhookResendResponse, err error) {
+	ctx, span := j.agentsAPI.httpClient.startSpan(ctx, "AgentJobsAPI.ResendWebhook", SpanAttribute{Key: AttrJobID, Value: jobID})
+	defer endSpan(span, &err)
 	if jobID == "" {
 		return AgentJobWebhookResendResponse{}, fmt.Errorf("jobID cannot be empty")
 	}
@@ -745,17 +952,19 @@ func (j *AgentJobsAPI) ResendWebhookWithContext(ctx context.Context, jobID strin
 
 // CancelAll cancels all running jobs for an agent and returns the structured
 // cancellation summary.
-func (j *AgentJobsAPI) CancelAll(agentID string) (AgentJobCancelAllResponse, error) {
-	return j.CancelAllWithContext(context.Background(), agentID)
+func (j *AgentJobsAPI) CancelAll(agentID string, opts ...RequestOption) (AgentJobCancelAllResponse, error) {
+	return j.CancelAllWithContext(context.Background(), agentID, opts...)
 }
 
 // CancelAllWithContext cancels all running jobs for an agent with a caller-supplied context.
-func (j *AgentJobsAPI) CancelAllWithContext(ctx context.Context, agentID string) (AgentJobCancelAllResponse, error) {
+func (j *AgentJobsAPI) CancelAllWithContext(ctx context.Context, agentID string, opts ...RequestOption) (_ AgentJobCancelAllResponse, err error) {
+	ctx, span := j.agentsAPI.httpClient.startSpan(ctx, "AgentJobsAPI.CancelAll", SpanAttribute{Key: AttrAgentID, Value: agentID})
+	defer endSpan(span, &err)
 	if agentID == "" {
 		return AgentJobCancelAllResponse{}, fmt.Errorf("agentID cannot be empty")
 	}
 	var resp AgentJobCancelAllResponse
-	if err := j.agentsAPI.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/jobs/cancel-all/", agentID), nil, nil, &resp); err != nil {
+	if err := j.agentsAPI.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/agents/%s/jobs/cancel-all/", agentID), nil, nil, &resp, opts...); err != nil {
 		return AgentJobCancelAllResponse{}, err
 	}
 	return resp, nil
@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -489,6 +491,94 @@ func TestAgentsAPIRunVersionSyncWithSkipCacheSendsHeader(t *testing.T) {
 	}
 }
 
+func TestAgentsAPIRunReusesIdempotencyKeyAcrossRetries(t *testing.T) {
+	var keys []string
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 && r.URL.Path == "/v1/agents/run/flaky/async/" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`"job-1"`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithConfig(Config{
+		APIKey:               "k",
+		OrganizationID:       "org",
+		BaseURL:              server.URL,
+		Timeout:              time.Second,
+		MaxRetries:           1,
+		RetryInitialInterval: 5 * time.Millisecond,
+		RetryMaxInterval:     5 * time.Millisecond,
+		RetryMultiplier:      1,
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Agents.Run("flaky", 0, map[string]any{"text": "hi"}, nil); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(keys))
+	}
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Fatalf("expected the same non-empty idempotency key on each attempt, got %q", keys)
+	}
+
+	first := keys[0]
+	keys = nil
+	if _, err := client.Agents.Run("agent-id", 0, map[string]any{"text": "hi"}, nil); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(keys) != 1 || keys[0] == "" || keys[0] == first {
+		t.Fatalf("expected a fresh idempotency key per call, got %q after %q", keys, first)
+	}
+}
+
+func TestAgentsAPIRunManyUsesCallerIdempotencyKeyPerChunk(t *testing.T) {
+	var keys []string
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		var payload struct {
+			Inputs []map[string]any `json:"inputs"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		ids := make([]string, len(payload.Inputs))
+		for i := range ids {
+			ids[i] = "job"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ids)
+	}))
+	defer server.Close()
+
+	client := newAgentsTestClient(t, server.URL)
+	defer client.Close()
+
+	if _, err := client.Agents.RunMany("agent-id", []map[string]any{{"text": "hi"}}, 0, nil, RunOptions{IdempotencyKey: "work-42"}); err != nil {
+		t.Fatalf("run many: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "work-42" {
+		t.Fatalf("expected single chunk to use the key as-is, got %q", keys)
+	}
+
+	keys = nil
+	inputs := make([]map[string]any, maxBatchSize+1)
+	for i := range inputs {
+		inputs[i] = map[string]any{"text": "hi"}
+	}
+	if _, err := client.Agents.RunMany("agent-id", inputs, 0, nil, RunOptions{IdempotencyKey: "work-42"}); err != nil {
+		t.Fatalf("run many: %v", err)
+	}
+	if strings.Join(keys, ",") != "work-42-0,work-42-1" {
+		t.Fatalf("expected per-chunk keys, got %q", keys)
+	}
+}
+
 func newAgentsTestClient(t *testing.T, baseURL string) *RoeClient {
 	t.Helper()
 	client, err := NewClientWithConfig(Config{
@@ -514,3 +604,91 @@ func assertJSONBody(t *testing.T, r *http.Request, want map[string]any) {
 		t.Fatalf("unexpected body: got %#v, want %#v", got, want)
 	}
 }
+
+func TestAgentJobsAPIAttachMatchingSurvivesStatusChangesWhileListing(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	type fakeJob struct {
+		id     string
+		status JobStatus
+	}
+	// Job i is created i minutes after base.
+	jobs := []*fakeJob{
+		{"00000000-0000-0000-0000-000000000000", JobPending},
+		{"00000000-0000-0000-0000-000000000001", JobStarted},
+		{"00000000-0000-0000-0000-000000000002", JobStarted},
+		{"00000000-0000-0000-0000-000000000003", JobStarted},
+		{"00000000-0000-0000-0000-000000000004", JobStarted},
+		{"00000000-0000-0000-0000-000000000005", JobStarted},
+	}
+	requests := 0
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("ordering") != "created_at" {
+			t.Errorf("unexpected ordering in %s", r.URL.RawQuery)
+		}
+		from, err := time.Parse(time.RFC3339Nano, query.Get("created_from"))
+		if err != nil {
+			t.Errorf("unexpected created_from in %s", r.URL.RawQuery)
+			return
+		}
+		requests++
+		// Pages hold two jobs, whatever page_size asks for.
+		var matching []map[string]any
+		for i, job := range jobs {
+			created := base.Add(time.Duration(i) * time.Minute)
+			if fmt.Sprint(int(job.status)) == query.Get("status_code") && !created.Before(from) {
+				matching = append(matching, map[string]any{"id": job.id, "created_at": created, "status_code": int(job.status)})
+			}
+		}
+		page, _ := strconv.Atoi(query.Get("page"))
+		lo, hi := min((page-1)*2, len(matching)), min(page*2, len(matching))
+		body := map[string]any{"count": len(matching), "results": matching[lo:hi]}
+		if hi < len(matching) {
+			body["next"] = "more"
+		}
+		switch requests {
+		case 1:
+			// The pending job starts once the pending listing is done, so it
+			// shows up again under STARTED.
+			jobs[0].status = JobStarted
+		case 2:
+			// A listed job finishes before the next page is fetched.
+			jobs[1].status = JobSuccess
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithConfig(Config{APIKey: "k", OrganizationID: "org", BaseURL: server.URL, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	batch, err := client.Agents.Jobs.AttachMatching("agent-1", JobFilter{
+		Statuses:    []JobStatus{JobPending, JobStarted},
+		CreatedFrom: base,
+	})
+	if err != nil {
+		t.Fatalf("attach matching: %v", err)
+	}
+	var ids []string
+	for _, job := range batch.Jobs() {
+		ids = append(ids, job.ID())
+	}
+	var want []string
+	for _, job := range jobs {
+		want = append(want, job.id)
+	}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("expected every in-flight job once, in creation order:\n%v\ngot\n%v", want, ids)
+	}
+
+	if job := client.Agents.Jobs.Attach("job-1"); job.ID() != "job-1" || job.Timeout() != 7200*time.Second {
+		t.Fatalf("unexpected attached job %+v", job)
+	}
+	if got := client.Agents.Jobs.AttachBatch([]string{"job-1", "job-2"}).Jobs(); len(got) != 2 || got[1].ID() != "job-2" {
+		t.Fatalf("unexpected attached batch %+v", got)
+	}
+}
@@ -1,7 +1,9 @@
 package roe
 
 import (
+	"context"
 	_ "embed"
+	"fmt"
 	"net/http"
 	"strings"
 )
@@ -13,22 +15,54 @@ var userAgent = "roe-golang/" + strings.TrimSpace(versionString)
 
 // Auth handles header generation.
 type Auth struct {
-	cfg Config
+	credentials CredentialsProvider
 }
 
 func newAuth(cfg Config) Auth {
-	return Auth{cfg: cfg}
+	credentials := cfg.Credentials
+	if credentials == nil {
+		credentials = StaticCredentials(cfg.APIKey)
+	}
+	return Auth{credentials: credentials}
 }
 
-// Headers returns default headers including auth.
+// Headers returns default headers including auth. Authorization is omitted
+// when the credentials provider fails.
 func (a Auth) Headers() http.Header {
+	h, _, _ := a.headers(context.Background())
+	return h
+}
+
+// headers returns the default headers and the API key they carry.
+func (a Auth) headers(ctx context.Context) (http.Header, string, error) {
 	h := http.Header{}
+	h.Set("User-Agent", userAgent)
+	key, err := a.apiKey(ctx)
+	if err != nil {
+		return h, "", err
+	}
+	h.Set("Authorization", "Bearer "+key)
+	return h, key, nil
+}
+
+func (a Auth) apiKey(ctx context.Context) (string, error) {
+	key, err := a.credentials.APIKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("resolve api key: %w", err)
+	}
 	// Strip "Bearer " prefix if user accidentally included it
-	key := a.cfg.APIKey
 	if strings.HasPrefix(strings.ToLower(key), "bearer ") {
 		key = strings.TrimSpace(key[7:])
 	}
-	h.Set("Authorization", "Bearer "+key)
-	h.Set("User-Agent", userAgent)
-	return h
+	return key, nil
+}
+
+// refresh asks the provider for a new key after sent was rejected and reports
+// whether a different key is now available.
+func (a Auth) refresh(ctx context.Context, sent string) bool {
+	if err := a.credentials.Refresh(ctx); err != nil {
+		return false
+	}
+	key, err := a.apiKey(ctx)
+	return err == nil && key != sent
 }
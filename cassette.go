@@ -0,0 +1,418 @@
+package roe
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// CassetteMode selects whether a Cassette talks to the network.
+type CassetteMode int
+
+const (
+	// CassetteReplay serves recorded responses and never touches the network.
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord forwards requests and records every exchange.
+	CassetteRecord
+)
+
+// ErrCassetteMiss is matched by errors returned when a replayed request has
+// no unused recorded interaction.
+var ErrCassetteMiss = errors.New("no recorded interaction matches request")
+
+const (
+	cassetteVersion = 1
+	scrubbed        = "[scrubbed]"
+)
+
+var defaultScrubHeaders = []string{"Authorization", "X-API-Key", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+// CassetteOptions configures NewCassette.
+type CassetteOptions struct {
+	Mode CassetteMode
+	// Path is the cassette file. Replay mode reads it in NewCassette; record
+	// mode writes it on Save.
+	Path string
+	// Transport sends recorded requests. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// ScrubHeaders are replaced with "[scrubbed]" in the cassette, on top of
+	// Authorization, X-API-Key, Cookie, Set-Cookie and Proxy-Authorization.
+	ScrubHeaders []string
+	// Secrets are literal strings (API keys, organization IDs, ...) replaced
+	// with "[scrubbed]" wherever they appear in URLs, headers or bodies.
+	Secrets []string
+}
+
+// Cassette is an http.RoundTripper that records API traffic to a file and
+// replays it, for deterministic tests without the live API:
+//
+//	cassette, err := roe.NewCassette(roe.CassetteOptions{Mode: roe.CassetteReplay, Path: "testdata/run.json"})
+//	client, err := roe.NewClientWithParams(roe.ConfigParams{Transport: cassette, ...})
+//
+// Requests are matched on method, path, query and a normalized body: JSON is
+// compared structurally and multipart forms part by part, so random
+// boundaries do not matter. Identical requests (e.g. status polls) are served
+// in recorded order, each interaction at most once.
+type Cassette struct {
+	mode      CassetteMode
+	path      string
+	transport http.RoundTripper
+	scrub     map[string]struct{}
+	secrets   []string
+
+	mu           sync.Mutex
+	interactions []*cassetteInteraction
+}
+
+type cassetteFile struct {
+	Version      int                    `json:"version"`
+	Interactions []*cassetteInteraction `json:"interactions"`
+}
+
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+
+	key  string
+	used bool
+}
+
+type cassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"headers,omitempty"`
+	cassetteBody
+}
+
+type cassetteResponse struct {
+	StatusCode int         `json:"status"`
+	Header     http.Header `json:"headers,omitempty"`
+	cassetteBody
+}
+
+// cassetteBody keeps text bodies readable and base64-encodes binary ones.
+type cassetteBody struct {
+	Body     string `json:"body,omitempty"`
+	Encoding string `json:"body_encoding,omitempty"`
+}
+
+func newCassetteBody(b []byte) cassetteBody {
+	if utf8.Valid(b) {
+		return cassetteBody{Body: string(b)}
+	}
+	return cassetteBody{Body: base64.StdEncoding.EncodeToString(b), Encoding: "base64"}
+}
+
+func (b cassetteBody) bytes() ([]byte, error) {
+	if b.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(b.Body)
+	}
+	return []byte(b.Body), nil
+}
+
+// NewCassette returns a recording or replaying transport. In replay mode the
+// cassette file must exist.
+func NewCassette(opts CassetteOptions) (*Cassette, error) {
+	if opts.Path == "" {
+		return nil, errors.New("roe: cassette path is required")
+	}
+	c := &Cassette{
+		mode:      opts.Mode,
+		path:      opts.Path,
+		transport: opts.Transport,
+		scrub:     map[string]struct{}{},
+	}
+	if c.transport == nil {
+		c.transport = http.DefaultTransport
+	}
+	for _, h := range append(append([]string{}, defaultScrubHeaders...), opts.ScrubHeaders...) {
+		c.scrub[http.CanonicalHeaderKey(h)] = struct{}{}
+	}
+	for _, s := range opts.Secrets {
+		if s != "" {
+			c.secrets = append(c.secrets, s)
+		}
+	}
+
+	if c.mode != CassetteReplay {
+		return c, nil
+	}
+	data, err := os.ReadFile(opts.Path)
+	if err != nil {
+		return nil, fmt.Errorf("roe: read cassette: %w", err)
+	}
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("roe: decode cassette %s: %w", opts.Path, err)
+	}
+	for _, in := range file.Interactions {
+		body, err := in.Request.bytes()
+		if err != nil {
+			return nil, fmt.Errorf("roe: decode cassette %s: %w", opts.Path, err)
+		}
+		u, err := url.Parse(in.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("roe: decode cassette %s: %w", opts.Path, err)
+		}
+		in.key = c.matchKey(in.Request.Method, u, in.Request.Header.Get("Content-Type"), body)
+	}
+	c.interactions = file.Interactions
+	return c, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if c.mode == CassetteRecord {
+		return c.record(req, body)
+	}
+	return c.replay(req, body)
+}
+
+func (c *Cassette) replay(req *http.Request, body []byte) (*http.Response, error) {
+	key := c.matchKey(req.Method, req.URL, req.Header.Get("Content-Type"), body)
+
+	c.mu.Lock()
+	var match *cassetteInteraction
+	for _, in := range c.interactions {
+		if !in.used && in.key == key {
+			in.used = true
+			match = in
+			break
+		}
+	}
+	c.mu.Unlock()
+
+	if match == nil {
+		return nil, fmt.Errorf("roe: cassette %s: %s %s: %w", c.path, req.Method, c.scrubString(req.URL.RequestURI()), ErrCassetteMiss)
+	}
+	respBody, err := match.Response.bytes()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.Response.StatusCode, http.StatusText(match.Response.StatusCode)),
+		StatusCode:    match.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cloneHeaders(match.Response.Header),
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+func (c *Cassette) record(req *http.Request, body []byte) (*http.Response, error) {
+	// RoundTrip must not modify req, so the buffered body goes out on a clone.
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.ContentLength = int64(len(body))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	resp, err := c.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	resp.Request = req
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := &cassetteInteraction{
+		Request: cassetteRequest{
+			Method:       req.Method,
+			URL:          c.scrubString(req.URL.String()),
+			Header:       c.scrubHeaders(req.Header),
+			cassetteBody: newCassetteBody(c.scrubBytes(body)),
+		},
+		Response: cassetteResponse{
+			StatusCode:   resp.StatusCode,
+			Header:       c.scrubHeaders(resp.Header),
+			cassetteBody: newCassetteBody(c.scrubBytes(respBody)),
+		},
+	}
+	c.mu.Lock()
+	c.interactions = append(c.interactions, in)
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// Save writes the recorded interactions to the cassette file. It is a no-op
+// in replay mode.
+func (c *Cassette) Save() error {
+	if c.mode != CassetteRecord {
+		return nil
+	}
+	c.mu.Lock()
+	data, err := json.MarshalIndent(cassetteFile{Version: cassetteVersion, Interactions: c.interactions}, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o644)
+}
+
+// Unused returns the recorded interactions that were never replayed, as
+// "METHOD /path?query" strings, so tests can assert a flow replayed fully.
+func (c *Cassette) Unused() []string {
+	if c.mode != CassetteReplay {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []string
+	for _, in := range c.interactions {
+		if !in.used {
+			out = append(out, in.Request.Method+" "+requestURI(in.Request.URL))
+		}
+	}
+	return out
+}
+
+func requestURI(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.RequestURI()
+}
+
+// readRequestBody drains and closes req.Body, as RoundTrip must, leaving the
+// rest of req untouched.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func (c *Cassette) scrubHeaders(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for k, values := range h {
+		if _, ok := c.scrub[http.CanonicalHeaderKey(k)]; ok {
+			out[k] = []string{scrubbed}
+			continue
+		}
+		cp := make([]string, len(values))
+		for i, v := range values {
+			cp[i] = c.scrubString(v)
+		}
+		out[k] = cp
+	}
+	return out
+}
+
+func (c *Cassette) scrubString(s string) string {
+	for _, secret := range c.secrets {
+		s = strings.ReplaceAll(s, secret, scrubbed)
+		if escaped := url.QueryEscape(secret); escaped != secret {
+			s = strings.ReplaceAll(s, escaped, scrubbed)
+		}
+	}
+	return s
+}
+
+func (c *Cassette) scrubBytes(b []byte) []byte {
+	for _, secret := range c.secrets {
+		b = bytes.ReplaceAll(b, []byte(secret), []byte(scrubbed))
+	}
+	return b
+}
+
+// matchKey identifies a request independently of host, header values, query
+// order, JSON key order and multipart boundaries. Secrets are scrubbed first
+// so live requests match recorded ones.
+func (c *Cassette) matchKey(method string, u *url.URL, contentType string, body []byte) string {
+	var b strings.Builder
+	b.WriteString(strings.ToUpper(method))
+	b.WriteByte(' ')
+	b.WriteString(c.scrubString(u.EscapedPath()))
+	b.WriteByte('?')
+	b.WriteString(c.scrubString(u.Query().Encode()))
+	b.WriteByte('\n')
+	b.WriteString(normalizeBody(contentType, c.scrubBytes(body)))
+	return b.String()
+}
+
+func normalizeBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "multipart/form-data" && params["boundary"] != "":
+		if normalized, err := normalizeMultipart(body, params["boundary"]); err == nil {
+			return normalized
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			return values.Encode()
+		}
+	case strings.HasSuffix(mediaType, "json"):
+		var v any
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err == nil {
+			if normalized, err := json.Marshal(v); err == nil {
+				return string(normalized)
+			}
+		}
+	}
+	return string(body)
+}
+
+// normalizeMultipart renders the parts sorted by field and file name, without
+// the boundary.
+func normalizeMultipart(body []byte, boundary string) (string, error) {
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	var parts []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, fmt.Sprintf("%s|%s|%s|%s", part.FormName(), part.FileName(), part.Header.Get("Content-Type"), content))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "\n"), nil
+}
@@ -0,0 +1,177 @@
+package roe
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newCassetteTestClient(t *testing.T, baseURL, apiKey string, transport http.RoundTripper) *RoeClient {
+	t.Helper()
+	client, err := NewClientWithConfig(Config{
+		APIKey:         apiKey,
+		OrganizationID: "org-secret",
+		BaseURL:        baseURL,
+		Timeout:        time.Second,
+		Transport:      transport,
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func runCassetteFlow(t *testing.T, client *RoeClient) AgentJobResult {
+	t.Helper()
+	job, err := client.Agents.Run("agent-1", 0, map[string]any{
+		"text":     "hello",
+		"document": FileUpload{Reader: strings.NewReader("file body"), Filename: "doc.txt", MimeType: "text/plain"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	result, err := job.Wait(time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	return result
+}
+
+func TestCassetteRecordsAndReplaysJobFlow(t *testing.T) {
+	statusCalls := 0
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/agents/run/agent-1/async/":
+			if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+				t.Errorf("expected a multipart run, got %q", r.Header.Get("Content-Type"))
+			}
+			_, _ = w.Write([]byte(`"job-1"`))
+		case "/v1/agents/jobs/job-1/status/":
+			statusCalls++
+			if statusCalls == 1 {
+				_, _ = w.Write([]byte(`{"status": 1}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status": 3}`))
+		case "/v1/agents/jobs/job-1/result/":
+			_, _ = w.Write([]byte(`{"agent_id": "agent-1", "outputs": [{"key": "answer", "value": "42"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "flow.json")
+	recorder, err := NewCassette(CassetteOptions{Mode: CassetteRecord, Path: path, Secrets: []string{"org-secret"}})
+	if err != nil {
+		t.Fatalf("new recorder: %v", err)
+	}
+	recorded := runCassetteFlow(t, newCassetteTestClient(t, server.URL, "secret-key", recorder))
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cassette: %v", err)
+	}
+	for _, secret := range []string{"secret-key", "org-secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("expected %q to be scrubbed from the cassette:\n%s", secret, data)
+		}
+	}
+
+	player, err := NewCassette(CassetteOptions{Mode: CassetteReplay, Path: path, Secrets: []string{"org-secret"}})
+	if err != nil {
+		t.Fatalf("new player: %v", err)
+	}
+	client := newCassetteTestClient(t, "http://cassette.invalid", "other-key", player)
+	replayed := runCassetteFlow(t, client)
+
+	if len(replayed.Outputs) != 1 || len(recorded.Outputs) != 1 || replayed.Outputs[0].Value != recorded.Outputs[0].Value {
+		t.Fatalf("expected the replayed result to match the recording, got %+v vs %+v", replayed, recorded)
+	}
+	if unused := player.Unused(); len(unused) != 0 {
+		t.Fatalf("expected every interaction to be replayed, left %v", unused)
+	}
+
+	_, err = client.Agents.Jobs.RetrieveStatus("job-1")
+	if !errors.Is(err, ErrCassetteMiss) {
+		t.Fatalf("expected ErrCassetteMiss once the polls are used up, got %v", err)
+	}
+}
+
+func TestCassetteMatchKeyIgnoresBoundaryAndOrdering(t *testing.T) {
+	c := &Cassette{}
+	a := "--aaa\r\nContent-Disposition: form-data; name=\"b\"\r\n\r\n2\r\n" +
+		"--aaa\r\nContent-Disposition: form-data; name=\"a\"\r\n\r\n1\r\n--aaa--\r\n"
+	b := "--bbb\r\nContent-Disposition: form-data; name=\"a\"\r\n\r\n1\r\n" +
+		"--bbb\r\nContent-Disposition: form-data; name=\"b\"\r\n\r\n2\r\n--bbb--\r\n"
+	u1, u2 := mustParseURL(t, "http://x/v1/p/?b=2&a=1"), mustParseURL(t, "http://y/v1/p/?a=1&b=2")
+
+	if c.matchKey("POST", u1, "multipart/form-data; boundary=aaa", []byte(a)) !=
+		c.matchKey("POST", u2, "multipart/form-data; boundary=bbb", []byte(b)) {
+		t.Fatal("expected multipart bodies with different boundaries to match")
+	}
+	if c.matchKey("POST", u1, "application/json", []byte(`{"x":1,"y":[1,2]}`)) !=
+		c.matchKey("POST", u2, "application/json; charset=utf-8", []byte(`{ "y": [1, 2], "x": 1 }`)) {
+		t.Fatal("expected equivalent JSON bodies to match")
+	}
+	if c.matchKey("POST", u1, "application/json", []byte(`{"x":1}`)) ==
+		c.matchKey("POST", u1, "application/json", []byte(`{"x":2}`)) {
+		t.Fatal("expected different JSON bodies not to match")
+	}
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse %q: %v", raw, err)
+	}
+	return u
+}
+
+func TestCassetteRecordLeavesCallerRequestUntouched(t *testing.T) {
+	var received string
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	cassette, err := NewCassette(CassetteOptions{Mode: CassetteRecord, Path: filepath.Join(t.TempDir(), "c.json")})
+	if err != nil {
+		t.Fatalf("new cassette: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/v1/things/", io.NopCloser(strings.NewReader(`{"a":1}`)))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	origBody := req.Body
+	resp, err := cassette.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	resp.Body.Close()
+
+	if received != `{"a":1}` {
+		t.Fatalf("expected the body to reach the server, got %q", received)
+	}
+	if req.Body != origBody || req.ContentLength != 0 || req.GetBody != nil {
+		t.Fatalf("expected the caller's request to be left untouched, got body %v length %d", req.Body, req.ContentLength)
+	}
+	if resp.Request != req {
+		t.Fatalf("expected the response to reference the caller's request")
+	}
+}
@@ -0,0 +1,187 @@
+package roe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const checkpointVersion = 1
+
+// JobCheckpoint is the serializable state of a Job. Restore it with ResumeJob.
+type JobCheckpoint struct {
+	Version        int    `json:"version"`
+	JobID          string `json:"job_id"`
+	TimeoutSeconds int    `json:"timeout_seconds"`
+}
+
+// JobBatchCheckpoint is the serializable state of a JobBatch, so a process
+// can be restarted in the middle of a wait and pick it up with
+// ResumeJobBatch. JobIDs[i] is the job for input i.
+type JobBatchCheckpoint struct {
+	Version        int                       `json:"version"`
+	JobIDs         []string                  `json:"job_ids"`
+	TimeoutSeconds int                       `json:"timeout_seconds"`
+	Statuses       map[string]AgentJobStatus `json:"statuses,omitempty"`
+	Completed      map[string]AgentJobResult `json:"completed,omitempty"`
+	SavedAt        time.Time                 `json:"saved_at"`
+}
+
+// Checkpoint returns the job's serializable state.
+func (j *Job) Checkpoint() JobCheckpoint {
+	return JobCheckpoint{Version: checkpointVersion, JobID: j.jobID, TimeoutSeconds: int(j.timeout / time.Second)}
+}
+
+// ResumeJob restores a Job from a checkpoint.
+func ResumeJob(client *RoeClient, checkpoint JobCheckpoint) (*Job, error) {
+	if checkpoint.Version != checkpointVersion {
+		return nil, fmt.Errorf("unsupported job checkpoint version %d", checkpoint.Version)
+	}
+	if checkpoint.JobID == "" {
+		return nil, errors.New("job checkpoint has no job ID")
+	}
+	return newJob(client.Agents, checkpoint.JobID, checkpoint.TimeoutSeconds), nil
+}
+
+// Checkpoint returns the batch's serializable state: its job IDs, timeout,
+// last known statuses and the results fetched so far.
+func (b *JobBatch) Checkpoint() JobBatchCheckpoint {
+	checkpoint := JobBatchCheckpoint{
+		Version:        checkpointVersion,
+		JobIDs:         append([]string(nil), b.jobIDs...),
+		TimeoutSeconds: int(b.timeout / time.Second),
+		Statuses:       make(map[string]AgentJobStatus, len(b.statuses)),
+		Completed:      make(map[string]AgentJobResult, len(b.completed)),
+		SavedAt:        time.Now().UTC(),
+	}
+	for id, status := range b.statuses {
+		checkpoint.Statuses[id] = status
+	}
+	for id, result := range b.completed {
+		checkpoint.Completed[id] = result
+	}
+	return checkpoint
+}
+
+// ResumeJobBatch restores a JobBatch from a checkpoint. Waiting on it only
+// polls the jobs that had not finished, and results already in the
+// checkpoint are returned without being fetched again.
+func ResumeJobBatch(client *RoeClient, checkpoint JobBatchCheckpoint) (*JobBatch, error) {
+	if checkpoint.Version != checkpointVersion {
+		return nil, fmt.Errorf("unsupported job batch checkpoint version %d", checkpoint.Version)
+	}
+	if len(checkpoint.JobIDs) == 0 {
+		return nil, errors.New("job batch checkpoint has no job IDs")
+	}
+	batch := newJobBatch(client.Agents, append([]string(nil), checkpoint.JobIDs...), checkpoint.TimeoutSeconds)
+	for id, status := range checkpoint.Statuses {
+		batch.statuses[id] = status
+	}
+	for id, result := range checkpoint.Completed {
+		batch.completed[id] = result
+	}
+	return batch, nil
+}
+
+// WriteFile saves the checkpoint as JSON. The file is replaced atomically,
+// so a crash while saving leaves the previous checkpoint intact.
+func (c JobBatchCheckpoint) WriteFile(path string) error {
+	return writeCheckpointFile(path, c)
+}
+
+// WriteFile saves the checkpoint as JSON, replacing the file atomically.
+func (c JobCheckpoint) WriteFile(path string) error {
+	return writeCheckpointFile(path, c)
+}
+
+// ReadJobBatchCheckpoint loads a checkpoint saved by
+// JobBatchCheckpoint.WriteFile.
+func ReadJobBatchCheckpoint(path string) (JobBatchCheckpoint, error) {
+	var checkpoint JobBatchCheckpoint
+	return checkpoint, readCheckpointFile(path, &checkpoint)
+}
+
+// ReadJobCheckpoint loads a checkpoint saved by JobCheckpoint.WriteFile.
+func ReadJobCheckpoint(path string) (JobCheckpoint, error) {
+	var checkpoint JobCheckpoint
+	return checkpoint, readCheckpointFile(path, &checkpoint)
+}
+
+func writeCheckpointFile(path string, checkpoint any) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("encode checkpoint: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	return nil
+}
+
+func readCheckpointFile(path string, checkpoint any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return fmt.Errorf("decode checkpoint %s: %w", path, err)
+	}
+	return nil
+}
+
+// WithCheckpoints makes Wait and Results pass the batch's checkpoint to save
+// while they poll: at most once per interval (after every poll round when
+// zero) and once more when they return. A save error stops the wait, which
+// then reports it in a BatchIncompleteError.
+func (b *JobBatch) WithCheckpoints(save func(JobBatchCheckpoint) error, interval time.Duration) *JobBatch {
+	b.checkpoint = save
+	b.checkpointEvery = interval
+	return b
+}
+
+// WithCheckpointFile is WithCheckpoints saving to path with WriteFile.
+//
+//	batch.WithCheckpointFile("batch.json", time.Minute)
+//	// after a restart:
+//	checkpoint, _ := roe.ReadJobBatchCheckpoint("batch.json")
+//	batch, _ := roe.ResumeJobBatch(client, checkpoint)
+func (b *JobBatch) WithCheckpointFile(path string, interval time.Duration) *JobBatch {
+	return b.WithCheckpoints(func(checkpoint JobBatchCheckpoint) error {
+		return checkpoint.WriteFile(path)
+	}, interval)
+}
+
+// saveCheckpoint saves the batch when checkpoints are configured and the
+// interval has passed, or always when final is set.
+func (b *JobBatch) saveCheckpoint(final bool) error {
+	if b.checkpoint == nil {
+		return nil
+	}
+	if !final && time.Since(b.checkpointedAt) < b.checkpointEvery {
+		return nil
+	}
+	b.checkpointedAt = time.Now()
+	if err := b.checkpoint(b.Checkpoint()); err != nil {
+		return fmt.Errorf("save job batch checkpoint: %w", err)
+	}
+	return nil
+}
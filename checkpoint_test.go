@@ -0,0 +1,105 @@
+package roe
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestJobBatchCheckpointResumesAfterRestart(t *testing.T) {
+	var mu sync.Mutex
+	job2Done := false
+	var resultRequests [][]string
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			JobIDs []string `json:"job_ids"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.Header().Set("Content-Type", "application/json")
+		mu.Lock()
+		defer mu.Unlock()
+
+		if strings.HasSuffix(r.URL.Path, "/statuses/") {
+			statuses := make([]AgentJobStatusBatch, 0, len(payload.JobIDs))
+			for _, id := range payload.JobIDs {
+				status := JobSuccess
+				if id == "job-2" && !job2Done {
+					status = JobStarted
+				}
+				statuses = append(statuses, AgentJobStatusBatch{ID: id, Status: &status})
+			}
+			_ = json.NewEncoder(w).Encode(statuses)
+			return
+		}
+		resultRequests = append(resultRequests, payload.JobIDs)
+		agentID, versionID := "agent", "v1"
+		results := make([]AgentJobResultBatch, 0, len(payload.JobIDs))
+		for _, id := range payload.JobIDs {
+			results = append(results, AgentJobResultBatch{
+				ID:             id,
+				AgentID:        &agentID,
+				AgentVersionID: &versionID,
+				Result:         []any{map[string]any{"key": "out", "value": id, "description": "", "data_type": "text/plain"}},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	newClient := func() *RoeClient {
+		client, err := NewClientWithConfig(Config{APIKey: "k", OrganizationID: "org", BaseURL: server.URL, Timeout: time.Second})
+		if err != nil {
+			t.Fatalf("new client: %v", err)
+		}
+		return client
+	}
+	path := filepath.Join(t.TempDir(), "batch.json")
+
+	first := newClient()
+	batch := newJobBatch(first.Agents, []string{"job-1", "job-2"}, 600).WithCheckpointFile(path, 0)
+	if _, err := batch.Wait(time.Millisecond, 30*time.Millisecond); err == nil {
+		t.Fatal("expected the first wait to time out")
+	}
+	first.Close()
+
+	checkpoint, err := ReadJobBatchCheckpoint(path)
+	if err != nil {
+		t.Fatalf("read checkpoint: %v", err)
+	}
+	if checkpoint.TimeoutSeconds != 600 || len(checkpoint.JobIDs) != 2 || checkpoint.JobIDs[1] != "job-2" {
+		t.Fatalf("unexpected checkpoint %+v", checkpoint)
+	}
+	if _, ok := checkpoint.Completed["job-1"]; !ok || checkpoint.Statuses["job-2"].Status != JobStarted {
+		t.Fatalf("expected job-1 completed and job-2 started, got %+v", checkpoint)
+	}
+
+	mu.Lock()
+	job2Done = true
+	resultRequests = nil
+	mu.Unlock()
+
+	second := newClient()
+	defer second.Close()
+	resumed, err := ResumeJobBatch(second, checkpoint)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	results, err := resumed.Wait(time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatalf("resumed wait: %v", err)
+	}
+	if len(results) != 2 || results[0].Outputs[0].Value != "job-1" || results[1].Outputs[0].Value != "job-2" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if len(resultRequests) != 1 || len(resultRequests[0]) != 1 || resultRequests[0][0] != "job-2" {
+		t.Fatalf("expected only job-2 to be fetched after resuming, got %v", resultRequests)
+	}
+
+	if _, err := ResumeJobBatch(second, JobBatchCheckpoint{Version: 99, JobIDs: []string{"job-1"}}); err == nil {
+		t.Fatal("expected an unknown checkpoint version to be rejected")
+	}
+}
@@ -0,0 +1,278 @@
+package roe
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of one circuit in a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails requests fast with a *CircuitOpenError.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitOpenError is returned instead of sending a request while its circuit
+// is open. errors.Is(err, ErrCircuitOpen) reports true for it.
+type CircuitOpenError struct {
+	BaseURL string
+	Group   EndpointClass
+	// RetryAt is when the circuit will next let a probe through.
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s for %s %s requests (retry after %s)", ErrCircuitOpen, e.BaseURL, e.Group, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is matches ErrCircuitOpen.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitStateChange describes a transition reported to OnStateChange.
+type CircuitStateChange struct {
+	BaseURL string
+	Group   EndpointClass
+	From    CircuitState
+	To      CircuitState
+	// Failures is the consecutive failure count at the time of the change.
+	Failures int
+}
+
+// CircuitBreakerSettings configures NewCircuitBreaker.
+type CircuitBreakerSettings struct {
+	// FailureThreshold is the number of consecutive server errors or transport
+	// errors that opens a circuit. Defaults to 5.
+	FailureThreshold int
+	// OpenTimeout is how long a circuit stays open before probing. Defaults
+	// to 30s.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is how many probes may be in flight while half-open; that
+	// many must succeed to close the circuit. Defaults to 1.
+	HalfOpenProbes int
+	// OnStateChange, when set, is called after every transition. It is also
+	// logged through Config.Logger.
+	OnStateChange func(CircuitStateChange)
+}
+
+// CircuitBreaker is an optional breaker that stops a RoeClient from hammering
+// a degraded API. Each base URL and EndpointClass gets its own circuit, so a
+// failing tables backend does not block job polling. Only 5xx responses and
+// transport errors count as failures; a breaker may be shared by clients.
+type CircuitBreaker struct {
+	settings CircuitBreakerSettings
+	now      func() time.Time
+
+	mu       sync.Mutex
+	circuits map[circuitKey]*circuit
+}
+
+type circuitKey struct {
+	baseURL string
+	group   EndpointClass
+}
+
+type circuit struct {
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	probes    int // in flight while half-open
+	successes int // probe successes while half-open
+	gen       int // bumped on every transition
+}
+
+// NewCircuitBreaker builds a breaker; zero settings use the defaults.
+func NewCircuitBreaker(settings CircuitBreakerSettings) *CircuitBreaker {
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = 5
+	}
+	if settings.OpenTimeout <= 0 {
+		settings.OpenTimeout = 30 * time.Second
+	}
+	if settings.HalfOpenProbes <= 0 {
+		settings.HalfOpenProbes = 1
+	}
+	return &CircuitBreaker{
+		settings: settings,
+		now:      time.Now,
+		circuits: map[circuitKey]*circuit{},
+	}
+}
+
+// State returns the current state of the circuit for baseURL and group.
+func (b *CircuitBreaker) State(baseURL string, group EndpointClass) CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.circuits[circuitKey{baseURL, group}]; ok {
+		if c.state == CircuitOpen && !b.now().Before(c.openedAt.Add(b.settings.OpenTimeout)) {
+			return CircuitHalfOpen
+		}
+		return c.state
+	}
+	return CircuitClosed
+}
+
+type circuitOutcome int
+
+const (
+	circuitSuccess circuitOutcome = iota
+	circuitFailure
+	// circuitIgnored releases a probe slot without counting either way, e.g.
+	// when the caller's context was cancelled.
+	circuitIgnored
+)
+
+// circuitTicket is handed out by allow and settled once with the outcome.
+type circuitTicket struct {
+	breaker *CircuitBreaker
+	key     circuitKey
+	probe   bool
+	gen     int
+}
+
+// allow admits a request or returns a *CircuitOpenError. The returned change
+// is non-nil when admitting it moved the circuit to half-open.
+func (b *CircuitBreaker) allow(baseURL string, group EndpointClass) (*circuitTicket, *CircuitStateChange, error) {
+	if b == nil {
+		return nil, nil, nil
+	}
+	key := circuitKey{baseURL, group}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+
+	var change *CircuitStateChange
+	if c.state == CircuitOpen {
+		retryAt := c.openedAt.Add(b.settings.OpenTimeout)
+		if b.now().Before(retryAt) {
+			return nil, nil, &CircuitOpenError{BaseURL: baseURL, Group: group, RetryAt: retryAt}
+		}
+		change = b.transition(key, c, CircuitHalfOpen)
+	}
+	if c.state == CircuitHalfOpen {
+		if c.probes >= b.settings.HalfOpenProbes {
+			return nil, change, &CircuitOpenError{BaseURL: baseURL, Group: group, RetryAt: b.now()}
+		}
+		c.probes++
+		return &circuitTicket{breaker: b, key: key, probe: true, gen: c.gen}, change, nil
+	}
+	return &circuitTicket{breaker: b, key: key, gen: c.gen}, change, nil
+}
+
+// done records the outcome of an admitted request and returns the resulting
+// transition, if any.
+func (t *circuitTicket) done(outcome circuitOutcome) *CircuitStateChange {
+	if t == nil {
+		return nil
+	}
+	b := t.breaker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuits[t.key]
+	// Outcomes only count towards the period that admitted the request: a
+	// slow request admitted while closed must not reopen a half-open circuit
+	// or reset its failures, and a probe from an earlier half-open period
+	// must not close it.
+	if t.gen != c.gen {
+		return nil
+	}
+	if t.probe {
+		c.probes--
+	}
+
+	switch outcome {
+	case circuitSuccess:
+		c.failures = 0
+		if t.probe {
+			c.successes++
+			if c.successes >= b.settings.HalfOpenProbes {
+				return b.transition(t.key, c, CircuitClosed)
+			}
+		}
+	case circuitFailure:
+		c.failures++
+		if c.state == CircuitHalfOpen || (c.state == CircuitClosed && c.failures >= b.settings.FailureThreshold) {
+			return b.transition(t.key, c, CircuitOpen)
+		}
+	}
+	return nil
+}
+
+func (b *CircuitBreaker) transition(key circuitKey, c *circuit, to CircuitState) *CircuitStateChange {
+	change := &CircuitStateChange{BaseURL: key.baseURL, Group: key.group, From: c.state, To: to, Failures: c.failures}
+	c.state = to
+	c.probes, c.successes = 0, 0
+	c.gen++
+	switch to {
+	case CircuitOpen:
+		c.openedAt = b.now()
+	case CircuitClosed:
+		c.failures = 0
+	}
+	return change
+}
+
+// circuitOutcomeFor classifies an attempt: 5xx responses and transport errors
+// count against the circuit, cancellations by the caller do not.
+func circuitOutcomeFor(ctx context.Context, status int, err error) circuitOutcome {
+	if err != nil {
+		if ctx.Err() != nil {
+			return circuitIgnored
+		}
+		return circuitFailure
+	}
+	if status >= 500 {
+		return circuitFailure
+	}
+	return circuitSuccess
+}
+
+// reportCircuit logs a transition and forwards it to OnStateChange.
+func (c *httpClient) reportCircuit(ctx context.Context, change *CircuitStateChange) {
+	if change == nil {
+		return
+	}
+	if c.logger != nil {
+		c.logger.Printf("circuit for %s %s requests: %s -> %s (consecutive failures: %d)",
+			change.BaseURL, change.Group, change.From, change.To, change.Failures)
+	}
+	c.slog(ctx, slog.LevelWarn, "roe circuit state changed",
+		slog.String("base_url", change.BaseURL),
+		slog.String("endpoint", string(change.Group)),
+		slog.String("from", change.From.String()),
+		slog.String("to", change.To.String()),
+		slog.Int("failures", change.Failures),
+	)
+	if cb := c.cfg.CircuitBreaker.settings.OnStateChange; cb != nil {
+		cb(*change)
+	}
+}
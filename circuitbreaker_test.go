@@ -0,0 +1,176 @@
+package roe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerStateMachine(t *testing.T) {
+	now := time.Unix(0, 0)
+	breaker := NewCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 2, OpenTimeout: time.Second})
+	breaker.now = func() time.Time { return now }
+	const base = "https://api.example.com"
+
+	for i := 0; i < 2; i++ {
+		ticket, _, err := breaker.allow(base, EndpointRuns)
+		if err != nil {
+			t.Fatalf("expected closed circuit to admit request %d, got %v", i, err)
+		}
+		change := ticket.done(circuitFailure)
+		if (i == 1) != (change != nil) {
+			t.Fatalf("expected circuit to open exactly on the threshold, got %+v at failure %d", change, i+1)
+		}
+	}
+	if got := breaker.State(base, EndpointRuns); got != CircuitOpen {
+		t.Fatalf("expected open circuit, got %s", got)
+	}
+
+	_, _, err := breaker.allow(base, EndpointRuns)
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected CircuitOpenError, got %v", err)
+	}
+	if openErr.Group != EndpointRuns || !openErr.RetryAt.Equal(now.Add(time.Second)) {
+		t.Fatalf("unexpected error details: %+v", openErr)
+	}
+	if _, _, err := breaker.allow(base, EndpointStatus); err != nil {
+		t.Fatalf("expected other endpoint groups to stay closed, got %v", err)
+	}
+	if _, _, err := breaker.allow("https://other.example.com", EndpointRuns); err != nil {
+		t.Fatalf("expected other base URLs to stay closed, got %v", err)
+	}
+
+	now = now.Add(time.Second)
+	probe, change, err := breaker.allow(base, EndpointRuns)
+	if err != nil || change == nil || change.To != CircuitHalfOpen {
+		t.Fatalf("expected a half-open probe, got change=%+v err=%v", change, err)
+	}
+	if _, _, err := breaker.allow(base, EndpointRuns); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected concurrent requests to be rejected while probing, got %v", err)
+	}
+	if change := probe.done(circuitFailure); change == nil || change.To != CircuitOpen {
+		t.Fatalf("expected a failed probe to reopen the circuit, got %+v", change)
+	}
+
+	now = now.Add(time.Second)
+	probe, _, err = breaker.allow(base, EndpointRuns)
+	if err != nil {
+		t.Fatalf("expected a second probe, got %v", err)
+	}
+	if change := probe.done(circuitSuccess); change == nil || change.From != CircuitHalfOpen || change.To != CircuitClosed {
+		t.Fatalf("expected a successful probe to close the circuit, got %+v", change)
+	}
+	if got := breaker.State(base, EndpointRuns); got != CircuitClosed {
+		t.Fatalf("expected closed circuit, got %s", got)
+	}
+}
+
+func TestCircuitBreakerIgnoresOutcomesFromEarlierPeriods(t *testing.T) {
+	now := time.Unix(0, 0)
+	breaker := NewCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 1, OpenTimeout: time.Second})
+	breaker.now = func() time.Time { return now }
+	const base = "https://api.example.com"
+
+	slowFailure, _, _ := breaker.allow(base, EndpointRuns)
+	slowSuccess, _, _ := breaker.allow(base, EndpointRuns)
+	opener, _, _ := breaker.allow(base, EndpointRuns)
+	if change := opener.done(circuitFailure); change == nil || change.To != CircuitOpen {
+		t.Fatalf("expected the circuit to open, got %+v", change)
+	}
+
+	now = now.Add(time.Second)
+	probe, change, err := breaker.allow(base, EndpointRuns)
+	if err != nil || change == nil || change.To != CircuitHalfOpen {
+		t.Fatalf("expected a half-open probe, got change=%+v err=%v", change, err)
+	}
+	// Requests admitted while the circuit was closed finish during the probe.
+	if change := slowFailure.done(circuitFailure); change != nil {
+		t.Fatalf("expected a stale failure to be ignored, got %+v", change)
+	}
+	if change := slowSuccess.done(circuitSuccess); change != nil {
+		t.Fatalf("expected a stale success to be ignored, got %+v", change)
+	}
+	if got := breaker.State(base, EndpointRuns); got != CircuitHalfOpen {
+		t.Fatalf("expected the circuit to stay half-open, got %s", got)
+	}
+	if change := probe.done(circuitSuccess); change == nil || change.To != CircuitClosed {
+		t.Fatalf("expected the real probe to close the circuit, got %+v", change)
+	}
+}
+
+func TestCircuitBreakerIgnoresCancelledAttempts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := circuitOutcomeFor(ctx, 0, context.Canceled); got != circuitIgnored {
+		t.Fatalf("expected cancelled attempt to be ignored, got %v", got)
+	}
+	if got := circuitOutcomeFor(context.Background(), 0, errors.New("connection reset")); got != circuitFailure {
+		t.Fatalf("expected transport error to count, got %v", got)
+	}
+	if got := circuitOutcomeFor(context.Background(), http.StatusNotFound, nil); got != circuitSuccess {
+		t.Fatalf("expected 4xx to count as reachable, got %v", got)
+	}
+}
+
+type recordingLogger struct{ lines []string }
+
+func (l *recordingLogger) Printf(format string, v ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestHTTPClientCircuitBreakerFailsFast(t *testing.T) {
+	hits := map[string]int{}
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		if strings.HasPrefix(r.URL.Path, "/v1/tables/") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var changes []CircuitStateChange
+	logger := &recordingLogger{}
+	cfg := Config{
+		APIKey:               "k",
+		OrganizationID:       "org",
+		BaseURL:              server.URL,
+		Timeout:              time.Second,
+		MaxRetries:           5,
+		RetryInitialInterval: time.Millisecond,
+		RetryMaxInterval:     time.Millisecond,
+		Logger:               logger,
+		CircuitBreaker: NewCircuitBreaker(CircuitBreakerSettings{
+			FailureThreshold: 2,
+			OpenTimeout:      time.Minute,
+			OnStateChange:    func(change CircuitStateChange) { changes = append(changes, change) },
+		}),
+	}
+	client := newHTTPClient(cfg, newAuth(cfg))
+	defer client.close()
+
+	err := client.get("/v1/tables/t1/rows/", nil, nil)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if hits["/v1/tables/t1/rows/"] != 2 {
+		t.Fatalf("expected the breaker to stop retries after 2 failures, got %d", hits["/v1/tables/t1/rows/"])
+	}
+	if len(changes) != 1 || changes[0].To != CircuitOpen || changes[0].Group != EndpointTables || changes[0].BaseURL != server.URL {
+		t.Fatalf("unexpected state changes: %+v", changes)
+	}
+	if len(logger.lines) != 1 || !strings.Contains(logger.lines[0], "closed -> open") {
+		t.Fatalf("expected the transition to be logged, got %q", logger.lines)
+	}
+
+	if err := client.get("/v1/agents/jobs/job-1/status/", nil, nil); err != nil {
+		t.Fatalf("expected status polling to be unaffected, got %v", err)
+	}
+}
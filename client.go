@@ -13,6 +13,7 @@ type RoeClient struct {
 	Config Config
 	auth   Auth
 	http   *httpClient
+	scoped bool
 	*generatedAPIs
 
 	Agents        *AgentsAPI
@@ -42,7 +43,10 @@ func NewClientWithParams(params ConfigParams) (*RoeClient, error) {
 // NewClientWithConfig builds a RoeClient from a fully parsed Config.
 func NewClientWithConfig(cfg Config) (*RoeClient, error) {
 	auth := newAuth(cfg)
-	httpClient := newHTTPClient(cfg, auth)
+	return newRoeClient(cfg, newHTTPClient(cfg, auth)), nil
+}
+
+func newRoeClient(cfg Config, httpClient *httpClient) *RoeClient {
 	agentsAPI := newAgentsAPI(cfg, httpClient)
 	policiesAPI := newPoliciesAPI(cfg, httpClient)
 	usersAPI := newUsersAPI(cfg, httpClient)
@@ -51,26 +55,59 @@ func NewClientWithConfig(cfg Config) (*RoeClient, error) {
 
 	return &RoeClient{
 		Config:        cfg,
-		auth:          auth,
+		auth:          httpClient.auth,
 		http:          httpClient,
 		generatedAPIs: generatedAPIs,
 		Agents:        agentsAPI,
 		Policies:      policiesAPI,
 		Users:         usersAPI,
 		KnowledgeBase: knowledgeBaseAPI,
-	}, nil
+	}
+}
+
+// WithOrganization returns a client scoped to another organization. It shares
+// this client's connection pool, retry policy, rate limiter, circuit breaker,
+// tracer, metrics and hooks; only the organization ID differs. Scoped clients
+// are cheap, so one per request or tenant is fine.
+//
+//	acme := client.WithOrganization(acmeOrgID).WithCredentials(acmeKeys)
+func (c *RoeClient) WithOrganization(organizationID string) *RoeClient {
+	cfg := c.Config
+	cfg.OrganizationID = organizationID
+	return c.scope(cfg, c.auth)
 }
 
-// Close releases HTTP resources.
+// WithCredentials returns a client that sends keys from provider and shares
+// everything else with this client, as WithOrganization does.
+func (c *RoeClient) WithCredentials(provider CredentialsProvider) *RoeClient {
+	cfg := c.Config
+	cfg.Credentials = provider
+	return c.scope(cfg, newAuth(cfg))
+}
+
+func (c *RoeClient) scope(cfg Config, auth Auth) *RoeClient {
+	httpClient := *c.http
+	httpClient.cfg.OrganizationID = cfg.OrganizationID
+	httpClient.cfg.Credentials = cfg.Credentials
+	httpClient.auth = auth
+	scoped := newRoeClient(cfg, &httpClient)
+	scoped.scoped = true
+	return scoped
+}
+
+// Close releases HTTP resources. On a client returned by WithOrganization or
+// WithCredentials it does nothing: the shared pool belongs to the client it
+// was derived from.
 func (c *RoeClient) Close() {
-	if c == nil || c.http == nil {
+	if c == nil || c.http == nil || c.scoped {
 		return
 	}
 	c.http.close()
 }
 
 // Raw returns the generated OpenAPI client configured with the same base URL,
-// auth headers, and underlying http.Client as the ergonomic SDK surface.
+// auth headers, and underlying http.Client as the ergonomic SDK surface,
+// including any Config.Transport, Config.HTTPClient and Config.Middleware.
 func (c *RoeClient) Raw(opts ...generated.ClientOption) (*generated.ClientWithResponses, error) {
 	if c == nil || c.http == nil || c.http.client == nil {
 		return nil, fmt.Errorf("roe client is not initialized")
@@ -79,7 +116,11 @@ func (c *RoeClient) Raw(opts ...generated.ClientOption) (*generated.ClientWithRe
 	options := []generated.ClientOption{
 		generated.WithHTTPClient(c.http.client),
 		generated.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
-			for key, values := range c.auth.Headers() {
+			authHeaders, _, err := c.auth.headers(ctx)
+			if err != nil {
+				return err
+			}
+			for key, values := range authHeaders {
 				for _, value := range values {
 					req.Header.Add(key, value)
 				}
@@ -0,0 +1,86 @@
+package roe
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingMetrics struct {
+	mu       sync.Mutex
+	requests int
+}
+
+func (m *countingMetrics) ObserveRequest(RequestMetric) {
+	m.mu.Lock()
+	m.requests++
+	m.mu.Unlock()
+}
+func (m *countingMetrics) ObserveRetry(RetryMetric)     {}
+func (m *countingMetrics) ObserveJobWait(JobWaitMetric) {}
+
+func TestWithOrganizationScopesOrgAndSharesResources(t *testing.T) {
+	type seenRequest struct{ org, auth string }
+	var mu sync.Mutex
+	var seen []seenRequest
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, seenRequest{r.URL.Query().Get("organization_id"), r.Header.Get("Authorization")})
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"count":0,"results":[]}`))
+	}))
+	defer server.Close()
+
+	metrics := &countingMetrics{}
+	limiter := NewRateLimiter(map[EndpointClass]RateLimit{EndpointOther: {RequestsPerSecond: 1000, Burst: 10}})
+	client, err := NewClientWithConfig(Config{
+		APIKey:         "root-key",
+		OrganizationID: "org-root",
+		BaseURL:        server.URL,
+		Timeout:        time.Second,
+		Metrics:        metrics,
+		RateLimiter:    limiter,
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	acme := client.WithOrganization("org-acme")
+	other := acme.WithCredentials(StaticCredentials("other-key"))
+	for _, c := range []*RoeClient{client, acme, other} {
+		if _, err := c.Agents.List(0, 0); err != nil {
+			t.Fatalf("list agents: %v", err)
+		}
+	}
+
+	want := []seenRequest{
+		{"org-root", "Bearer root-key"},
+		{"org-acme", "Bearer root-key"},
+		{"org-acme", "Bearer other-key"},
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("request %d: expected %+v, got %+v", i, want[i], seen[i])
+		}
+	}
+	if client.Config.OrganizationID != "org-root" || acme.Config.OrganizationID != "org-acme" {
+		t.Fatal("expected scoping to leave the parent config untouched")
+	}
+	if acme.http.client != client.http.client || other.http.client != client.http.client {
+		t.Fatal("expected scoped clients to share the HTTP client and pool")
+	}
+	if metrics.requests != 3 {
+		t.Fatalf("expected shared metrics to see 3 requests, got %d", metrics.requests)
+	}
+	if limiter.Stats()[EndpointOther].Allowed != 3 {
+		t.Fatalf("expected the shared limiter to count 3 requests, got %+v", limiter.Stats()[EndpointOther])
+	}
+
+	acme.Close()
+	if _, err := client.Agents.List(0, 0); err != nil {
+		t.Fatalf("expected the parent to keep working after a scoped Close: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package roe
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
@@ -21,9 +22,18 @@ type RequestHook func(*http.Request)
 // ResponseHook allows callers to inspect responses (raw bytes included).
 type ResponseHook func(*http.Response, []byte)
 
+// Middleware wraps the transport used for every HTTP attempt. The first
+// middleware in a chain is the outermost one, so it sees each request first.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
 // Config holds SDK configuration.
 type Config struct {
-	APIKey         string
+	APIKey string
+	// Credentials, when set, supplies the API key for every request instead
+	// of APIKey, so the key can rotate while the client is running. See
+	// StaticCredentials, EnvCredentials, FileCredentials and
+	// NewCachedCredentials.
+	Credentials    CredentialsProvider
 	OrganizationID string
 	BaseURL        string
 	Timeout        time.Duration
@@ -42,21 +52,84 @@ type Config struct {
 	RetryMaxInterval     time.Duration
 	RetryMultiplier      float64
 	RetryJitter          float64
+	// RetryPolicy decides which failed attempts are retried and how long to
+	// wait. When nil, DefaultRetryPolicy is built from the Retry* settings
+	// above. MaxRetries still caps the number of retries.
+	RetryPolicy RetryPolicy
+	// RateLimiter, when set, throttles requests client-side per endpoint class
+	// and slows down after 429s. Share one limiter between clients to give
+	// them a common budget.
+	RateLimiter *RateLimiter
+	// CircuitBreaker, when set, fails requests fast with ErrCircuitOpen after
+	// repeated server or transport errors, probing until the API recovers.
+	CircuitBreaker *CircuitBreaker
+	// Tracer, when set, opens a span for every public SDK method and a child
+	// span for every HTTP attempt, and injects trace headers into requests.
+	Tracer Tracer
+	// Metrics, when set, receives per-attempt request, retry and job wait
+	// measurements. See PrometheusMetrics for a built-in implementation.
+	Metrics Metrics
+	// PollSchedule paces the status polls of Job and JobBatch waits called
+	// with a zero interval. When nil, DefaultPollSchedule polls quickly at
+	// first and backs off to every 30s.
+	PollSchedule PollSchedule
+	// SharedJobPolling makes Job.Wait on this client wait through one shared
+	// poller instead of polling each job on its own: every SharedPollInterval
+	// (default 1s) it fetches the status of all waiting jobs together through
+	// the batch status endpoint, and the results of finished ones through the
+	// batch result endpoint, in chunks of up to 1000 jobs. The wait's interval
+	// and the job's PollSchedule are then ignored. The poller sends its
+	// requests on its own context, cancelled once no job is waiting, so the
+	// trace context and request ID of a waiting call are not forwarded.
+	// JobBatch waits and Job.Watch are unaffected.
+	SharedJobPolling   bool
+	SharedPollInterval time.Duration
 
 	MaxIdleConns        int
 	MaxIdleConnsPerHost int
 	IdleConnTimeout     time.Duration
 
-	Logger        Logger
+	// HTTPClient, when set, is used as the base client (transport, redirect
+	// policy, cookie jar). It is copied, never mutated; a zero Timeout falls
+	// back to Config.Timeout.
+	HTTPClient *http.Client
+	// Transport, when set, replaces the SDK-built transport (and takes
+	// precedence over HTTPClient.Transport). ProxyURL and the idle-connection
+	// settings only apply to the SDK-built transport.
+	Transport http.RoundTripper
+	// Middleware wraps the base transport. Retries, auth and request IDs are
+	// applied above it, so every attempt passes through the chain.
+	Middleware []Middleware
+
+	Logger Logger
+	// SlogLogger, when set, receives structured records: requests and
+	// responses (with bodies) at debug, retries at warn and final failures at
+	// error, plus any attributes attached with WithLogAttrs. Unlike Logger it
+	// does not require Debug; filter with the handler's level instead.
+	SlogLogger    *slog.Logger
 	RedactHeaders []string
 
 	BeforeRequest []RequestHook
 	AfterResponse []ResponseHook
+
+	// sources records where LoadConfigWithParams found each setting, for
+	// Describe.
+	sources configSources
 }
 
 // ConfigParams provides optional overrides for building a Config.
 type ConfigParams struct {
+	// Profile selects a profile from the config file, overriding ROE_PROFILE.
+	Profile string
+	// ConfigFile overrides ROE_CONFIG_FILE and the default
+	// ~/.config/roe/config.yaml.
+	ConfigFile string
+	// NoProfile skips the config file and ROE_PROFILE entirely, for callers
+	// such as tests that must not pick up the user's settings.
+	NoProfile bool
+
 	APIKey          string
+	Credentials     CredentialsProvider
 	OrganizationID  string
 	BaseURL         string
 	Timeout         time.Duration
@@ -73,12 +146,25 @@ type ConfigParams struct {
 	RetryMaxInterval     time.Duration
 	RetryMultiplier      float64
 	RetryJitter          float64
+	RetryPolicy          RetryPolicy
+	RateLimiter          *RateLimiter
+	CircuitBreaker       *CircuitBreaker
+	Tracer               Tracer
+	Metrics              Metrics
+	PollSchedule         PollSchedule
+	SharedJobPolling     bool
+	SharedPollInterval   time.Duration
 
 	MaxIdleConns        int
 	MaxIdleConnsPerHost int
 	IdleConnTimeout     time.Duration
 
+	HTTPClient *http.Client
+	Transport  http.RoundTripper
+	Middleware []Middleware
+
 	Logger        Logger
+	SlogLogger    *slog.Logger
 	RedactHeaders []string
 
 	BeforeRequest []RequestHook
@@ -106,7 +192,12 @@ const (
 //	ROE_DEBUG, ROE_PROXY, ROE_EXTRA_HEADERS, ROE_REQUEST_ID, ROE_AUTO_REQUEST_ID,
 //	ROE_REQUEST_ID_HEADER, ROE_RETRY_INITIAL_MS, ROE_RETRY_MAX_MS,
 //	ROE_RETRY_MULTIPLIER, ROE_RETRY_JITTER, ROE_MAX_IDLE_CONNS,
-//	ROE_MAX_IDLE_CONNS_PER_HOST, ROE_IDLE_CONN_TIMEOUT.
+//	ROE_MAX_IDLE_CONNS_PER_HOST, ROE_IDLE_CONN_TIMEOUT, ROE_CONFIG_FILE,
+//	ROE_PROFILE.
+//
+// Settings not given as parameters or environment variables are taken from
+// the selected profile of the config file (ROE_CONFIG_FILE, default
+// ~/.config/roe/config.yaml), then from the SDK defaults.
 func LoadConfig(apiKey, orgID, baseURL string, timeoutSeconds float64, maxRetries int) (Config, error) {
 	return LoadConfigWithParams(ConfigParams{
 		APIKey:         apiKey,
@@ -118,11 +209,46 @@ func LoadConfig(apiKey, orgID, baseURL string, timeoutSeconds float64, maxRetrie
 }
 
 // LoadConfigWithParams is an extended constructor that accepts structured options.
+// Each setting is resolved from params, then the environment, then the config
+// file profile, then the default; Config.Describe reports which one won.
 func LoadConfigWithParams(params ConfigParams) (Config, error) {
+	profile, err := loadProfile(params)
+	if err != nil {
+		return Config{}, err
+	}
+	var prof profileConfig
+	profSource := ""
+	sources := configSources{}
+	if profile != nil {
+		prof = profile.profileConfig
+		profSource = profile.source()
+		sources["profile"] = fmt.Sprintf("%s from %s (%s)", profile.name, profile.path, profile.chosen)
+	}
+
 	envIdleTimeout, err := parseEnvDuration("ROE_IDLE_CONN_TIMEOUT", time.Second)
 	if err != nil {
 		return Config{}, err
 	}
+	envTimeout, err := parseEnvDuration("ROE_TIMEOUT", time.Second)
+	if err != nil && params.Timeout <= 0 && params.TimeoutSeconds <= 0 {
+		return Config{}, err
+	}
+	envRetryInitial, err := parseEnvDuration("ROE_RETRY_INITIAL_MS", time.Millisecond)
+	if err != nil {
+		return Config{}, err
+	}
+	envRetryMax, err := parseEnvDuration("ROE_RETRY_MAX_MS", time.Millisecond)
+	if err != nil {
+		return Config{}, err
+	}
+	envRetryMultiplier, envRetryMultiplierSet, err := parseEnvFloat("ROE_RETRY_MULTIPLIER")
+	if err != nil {
+		return Config{}, err
+	}
+	envRetryJitter, envRetryJitterSet, err := parseEnvFloat("ROE_RETRY_JITTER")
+	if err != nil {
+		return Config{}, err
+	}
 
 	envMaxRetries, envMaxRetriesSet, err := parseEnvInt("ROE_MAX_RETRIES")
 	if err != nil {
@@ -136,86 +262,123 @@ func LoadConfigWithParams(params ConfigParams) (Config, error) {
 	if err != nil {
 		return Config{}, err
 	}
-
-	maxRetries := defaultMaxRetries
-	if envMaxRetriesSet {
-		maxRetries = envMaxRetries
-	}
-	if params.MaxRetries != 0 {
-		maxRetries = params.MaxRetries
-	}
-
-	maxIdleConns := defaultMaxIdleConns
-	if envMaxIdleConnsSet {
-		maxIdleConns = envMaxIdleConns
+	envDebug, envDebugSet, err := parseEnvBool("ROE_DEBUG")
+	if err != nil && params.Debug == nil {
+		return Config{}, err
 	}
-	if params.MaxIdleConns != 0 {
-		maxIdleConns = params.MaxIdleConns
+	envAutoRequestID, envAutoRequestIDSet, err := parseEnvBool("ROE_AUTO_REQUEST_ID")
+	if err != nil && params.AutoRequestID == nil {
+		return Config{}, err
 	}
 
-	maxIdlePerHost := defaultMaxIdlePerHost
-	if envMaxIdlePerHostSet {
-		maxIdlePerHost = envMaxIdlePerHost
-	}
-	if params.MaxIdleConnsPerHost != 0 {
-		maxIdlePerHost = params.MaxIdleConnsPerHost
+	str := func(field, paramVal, env, profVal, def string) string {
+		envVal := os.Getenv(env)
+		return pick(sources, field,
+			candidate[string]{paramVal, paramVal != "", sourceParam},
+			candidate[string]{envVal, envVal != "", envSource(env)},
+			candidate[string]{profVal, profVal != "", profSource},
+			candidate[string]{def, def != "", sourceDefault},
+		)
+	}
+	duration := func(field string, paramVal time.Duration, env string, envVal, profVal, def time.Duration) time.Duration {
+		return pick(sources, field,
+			candidate[time.Duration]{paramVal, paramVal > 0, sourceParam},
+			candidate[time.Duration]{envVal, envVal > 0, envSource(env)},
+			candidate[time.Duration]{profVal, profVal > 0, profSource},
+			candidate[time.Duration]{def, true, sourceDefault},
+		)
+	}
+	count := func(field string, paramVal int, env string, envVal int, envSet bool, profVal *int, def int) int {
+		return pick(sources, field,
+			candidate[int]{paramVal, paramVal != 0, sourceParam},
+			candidate[int]{envVal, envSet, envSource(env)},
+			candidate[int]{derefOr(profVal, 0), profVal != nil, profSource},
+			candidate[int]{def, true, sourceDefault},
+		)
+	}
+	float := func(field string, paramVal float64, env string, envVal float64, envSet bool, profVal *float64, def float64) float64 {
+		return pick(sources, field,
+			candidate[float64]{paramVal, paramVal != 0, sourceParam},
+			candidate[float64]{envVal, envSet, envSource(env)},
+			candidate[float64]{derefOr(profVal, 0), profVal != nil, profSource},
+			candidate[float64]{def, true, sourceDefault},
+		)
+	}
+	flag := func(field string, paramVal *bool, env string, envVal, envSet bool, profVal *bool, def bool) bool {
+		return pick(sources, field,
+			candidate[bool]{derefOr(paramVal, false), paramVal != nil, sourceParam},
+			candidate[bool]{envVal, envSet, envSource(env)},
+			candidate[bool]{derefOr(profVal, false), profVal != nil, profSource},
+			candidate[bool]{def, true, sourceDefault},
+		)
+	}
+
+	timeout := params.Timeout
+	if timeout <= 0 && params.TimeoutSeconds > 0 {
+		timeout = time.Duration(params.TimeoutSeconds * float64(time.Second))
 	}
 
 	cfg := Config{
-		APIKey:               firstNonEmpty(params.APIKey, os.Getenv("ROE_API_KEY")),
-		OrganizationID:       firstNonEmpty(params.OrganizationID, os.Getenv("ROE_ORGANIZATION_ID")),
-		BaseURL:              firstNonEmpty(params.BaseURL, os.Getenv("ROE_BASE_URL"), defaultBaseURL),
-		MaxRetries:           maxRetries,
+		APIKey:               str("api_key", params.APIKey, "ROE_API_KEY", prof.APIKey, ""),
+		Credentials:          params.Credentials,
+		OrganizationID:       str("organization_id", params.OrganizationID, "ROE_ORGANIZATION_ID", prof.OrganizationID, ""),
+		BaseURL:              str("base_url", params.BaseURL, "ROE_BASE_URL", prof.BaseURL, defaultBaseURL),
+		Timeout:              duration("timeout", timeout, "ROE_TIMEOUT", envTimeout, prof.Timeout, defaultTimeout),
+		MaxRetries:           count("max_retries", params.MaxRetries, "ROE_MAX_RETRIES", envMaxRetries, envMaxRetriesSet, prof.MaxRetries, defaultMaxRetries),
+		Debug:                flag("debug", params.Debug, "ROE_DEBUG", envDebug, envDebugSet, prof.Debug, false),
 		ExtraHeaders:         cloneHeaders(params.ExtraHeaders),
-		RequestIDHeader:      firstNonEmpty(params.RequestIDHeader, os.Getenv("ROE_REQUEST_ID_HEADER"), defaultRequestIDHeader),
-		DefaultRequestID:     firstNonEmpty(params.RequestID, os.Getenv("ROE_REQUEST_ID")),
-		RetryInitialInterval: defaultRetryInitial,
-		RetryMaxInterval:     defaultRetryMax,
-		RetryMultiplier:      defaultRetryMultiplier,
-		RetryJitter:          defaultRetryJitter,
-		MaxIdleConns:         maxIdleConns,
-		MaxIdleConnsPerHost:  maxIdlePerHost,
-		IdleConnTimeout:      firstNonZeroDuration(params.IdleConnTimeout, envIdleTimeout, defaultIdleConnTimeout),
+		RequestIDHeader:      str("request_id_header", params.RequestIDHeader, "ROE_REQUEST_ID_HEADER", "", defaultRequestIDHeader),
+		DefaultRequestID:     str("request_id", params.RequestID, "ROE_REQUEST_ID", "", ""),
+		AutoRequestID:        flag("auto_request_id", params.AutoRequestID, "ROE_AUTO_REQUEST_ID", envAutoRequestID, envAutoRequestIDSet, nil, true),
+		RetryInitialInterval: duration("retry_initial_interval", params.RetryInitialInterval, "ROE_RETRY_INITIAL_MS", envRetryInitial, prof.RetryInitialInterval, defaultRetryInitial),
+		RetryMaxInterval:     duration("retry_max_interval", params.RetryMaxInterval, "ROE_RETRY_MAX_MS", envRetryMax, prof.RetryMaxInterval, defaultRetryMax),
+		RetryMultiplier:      float("retry_multiplier", params.RetryMultiplier, "ROE_RETRY_MULTIPLIER", envRetryMultiplier, envRetryMultiplierSet, prof.RetryMultiplier, defaultRetryMultiplier),
+		RetryJitter:          float("retry_jitter", params.RetryJitter, "ROE_RETRY_JITTER", envRetryJitter, envRetryJitterSet, prof.RetryJitter, defaultRetryJitter),
+		RetryPolicy:          params.RetryPolicy,
+		RateLimiter:          params.RateLimiter,
+		CircuitBreaker:       params.CircuitBreaker,
+		Tracer:               params.Tracer,
+		Metrics:              params.Metrics,
+		PollSchedule:         params.PollSchedule,
+		SharedJobPolling:     params.SharedJobPolling,
+		SharedPollInterval:   params.SharedPollInterval,
+		MaxIdleConns:         count("max_idle_conns", params.MaxIdleConns, "ROE_MAX_IDLE_CONNS", envMaxIdleConns, envMaxIdleConnsSet, nil, defaultMaxIdleConns),
+		MaxIdleConnsPerHost:  count("max_idle_conns_per_host", params.MaxIdleConnsPerHost, "ROE_MAX_IDLE_CONNS_PER_HOST", envMaxIdlePerHost, envMaxIdlePerHostSet, nil, defaultMaxIdlePerHost),
+		IdleConnTimeout:      duration("idle_conn_timeout", params.IdleConnTimeout, "ROE_IDLE_CONN_TIMEOUT", envIdleTimeout, 0, defaultIdleConnTimeout),
+		HTTPClient:           params.HTTPClient,
+		Transport:            params.Transport,
+		Middleware:           params.Middleware,
 		Logger:               params.Logger,
+		SlogLogger:           params.SlogLogger,
 		RedactHeaders:        params.RedactHeaders,
 		BeforeRequest:        params.BeforeRequest,
 		AfterResponse:        params.AfterResponse,
-		AutoRequestID:        true,
+		sources:              sources,
 	}
 
-	if cfg.ExtraHeaders == nil {
-		cfg.ExtraHeaders = http.Header{}
-	}
-	if cfg.RedactHeaders == nil {
-		cfg.RedactHeaders = []string{"Authorization", "X-API-Key", "X-Request-ID"}
-	}
-
-	if params.Debug != nil {
-		cfg.Debug = *params.Debug
-	} else if env := os.Getenv("ROE_DEBUG"); env != "" {
-		val, err := strconv.ParseBool(env)
+	if cfg.Credentials != nil {
+		sources["credentials"] = sourceParam
+	} else if cfg.APIKey == "" && profile != nil {
+		provider, desc, err := profile.credentials()
 		if err != nil {
-			return Config{}, fmt.Errorf("parse ROE_DEBUG: %w", err)
+			return Config{}, err
+		}
+		if provider != nil {
+			cfg.Credentials = provider
+			sources["credentials"] = desc
 		}
-		cfg.Debug = val
 	}
 
-	if params.Timeout > 0 {
-		cfg.Timeout = params.Timeout
-	} else if params.TimeoutSeconds > 0 {
-		cfg.Timeout = time.Duration(params.TimeoutSeconds * float64(time.Second))
-	} else if envTimeout, err := parseEnvDuration("ROE_TIMEOUT", time.Second); err != nil {
-		return Config{}, err
-	} else if envTimeout > 0 {
-		cfg.Timeout = envTimeout
-	}
-	if cfg.Timeout == 0 {
-		cfg.Timeout = defaultTimeout
+	if cfg.RedactHeaders == nil {
+		cfg.RedactHeaders = []string{"Authorization", "X-API-Key", "X-Request-ID"}
 	}
 	if cfg.Timeout < 0 {
 		return Config{}, fmt.Errorf("timeout must be non-negative")
 	}
 
+	for k := range cfg.ExtraHeaders {
+		sources["extra_headers."+k] = sourceParam
+	}
 	if env := os.Getenv("ROE_EXTRA_HEADERS"); env != "" {
 		envHeaders, err := parseHeadersEnv(env)
 		if err != nil {
@@ -225,14 +388,20 @@ func LoadConfigWithParams(params ConfigParams) (Config, error) {
 			for _, v := range vals {
 				cfg.ExtraHeaders.Add(k, v)
 			}
+			if _, ok := sources["extra_headers."+k]; !ok {
+				sources["extra_headers."+k] = envSource("ROE_EXTRA_HEADERS")
+			}
 		}
 	}
-
-	proxyURL := params.ProxyURL
-	if proxyURL == "" {
-		proxyURL = os.Getenv("ROE_PROXY")
+	for k, v := range prof.ExtraHeaders {
+		k = http.CanonicalHeaderKey(k)
+		if _, ok := cfg.ExtraHeaders[k]; !ok {
+			cfg.ExtraHeaders.Set(k, v)
+			sources["extra_headers."+k] = profSource
+		}
 	}
-	if proxyURL != "" {
+
+	if proxyURL := str("proxy", params.ProxyURL, "ROE_PROXY", prof.Proxy, ""); proxyURL != "" {
 		parsed, err := url.Parse(proxyURL)
 		if err != nil {
 			return Config{}, fmt.Errorf("parse ROE_PROXY: %w", err)
@@ -240,42 +409,7 @@ func LoadConfigWithParams(params ConfigParams) (Config, error) {
 		cfg.ProxyURL = parsed
 	}
 
-	if params.AutoRequestID != nil {
-		cfg.AutoRequestID = *params.AutoRequestID
-	} else if env := os.Getenv("ROE_AUTO_REQUEST_ID"); env != "" {
-		val, err := strconv.ParseBool(env)
-		if err != nil {
-			return Config{}, fmt.Errorf("parse ROE_AUTO_REQUEST_ID: %w", err)
-		}
-		cfg.AutoRequestID = val
-	}
-
-	if val, err := parseEnvDuration("ROE_RETRY_INITIAL_MS", time.Millisecond); err != nil {
-		return Config{}, err
-	} else if val > 0 {
-		cfg.RetryInitialInterval = val
-	}
-	if val, err := parseEnvDuration("ROE_RETRY_MAX_MS", time.Millisecond); err != nil {
-		return Config{}, err
-	} else if val > 0 {
-		cfg.RetryMaxInterval = val
-	}
-	if valStr := os.Getenv("ROE_RETRY_MULTIPLIER"); valStr != "" {
-		val, err := strconv.ParseFloat(valStr, 64)
-		if err != nil {
-			return Config{}, fmt.Errorf("parse ROE_RETRY_MULTIPLIER: %w", err)
-		}
-		cfg.RetryMultiplier = val
-	}
-	if valStr := os.Getenv("ROE_RETRY_JITTER"); valStr != "" {
-		val, err := strconv.ParseFloat(valStr, 64)
-		if err != nil {
-			return Config{}, fmt.Errorf("parse ROE_RETRY_JITTER: %w", err)
-		}
-		cfg.RetryJitter = val
-	}
-
-	if cfg.APIKey == "" {
+	if cfg.APIKey == "" && cfg.Credentials == nil {
 		return Config{}, ErrMissingAPIKey
 	}
 	if cfg.OrganizationID == "" {
@@ -336,6 +470,37 @@ func parseEnvInt(env string) (int, bool, error) {
 	return parsed, true, nil
 }
 
+func parseEnvFloat(env string) (float64, bool, error) {
+	val := os.Getenv(env)
+	if val == "" {
+		return 0, false, nil
+	}
+	parsed, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, true, fmt.Errorf("parse %s: %w", env, err)
+	}
+	return parsed, true, nil
+}
+
+func parseEnvBool(env string) (bool, bool, error) {
+	val := os.Getenv(env)
+	if val == "" {
+		return false, false, nil
+	}
+	parsed, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, true, fmt.Errorf("parse %s: %w", env, err)
+	}
+	return parsed, true, nil
+}
+
+func derefOr[T any](v *T, def T) T {
+	if v == nil {
+		return def
+	}
+	return *v
+}
+
 func parseEnvDuration(env string, numericUnit time.Duration) (time.Duration, error) {
 	val := os.Getenv(env)
 	if val == "" {
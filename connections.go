@@ -19,12 +19,14 @@ func newConnectionsAPI(cfg Config, httpClient *httpClient) *ConnectionsAPI {
 }
 
 // List List connections.
-func (a *ConnectionsAPI) List(connectorType string, search string, page int, pageSize int) (generated.PaginatedConnectionListList, error) {
-	return a.ListWithContext(context.Background(), connectorType, search, page, pageSize)
+func (a *ConnectionsAPI) List(connectorType string, search string, page int, pageSize int, opts ...RequestOption) (generated.PaginatedConnectionListList, error) {
+	return a.ListWithContext(context.Background(), connectorType, search, page, pageSize, opts...)
 }
 
 // ListWithContext List connections.
-func (a *ConnectionsAPI) ListWithContext(ctx context.Context, connectorType string, search string, page int, pageSize int) (generated.PaginatedConnectionListList, error) {
+func (a *ConnectionsAPI) ListWithContext(ctx context.Context, connectorType string, search string, page int, pageSize int, opts ...RequestOption) (_ generated.PaginatedConnectionListList, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "ConnectionsAPI.List")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	query["organization_id"] = a.cfg.OrganizationID
 	if connectorType != "" {
@@ -40,19 +42,21 @@ func (a *ConnectionsAPI) ListWithContext(ctx context.Context, connectorType stri
 		query["page_size"] = fmt.Sprint(pageSize)
 	}
 	var resp generated.PaginatedConnectionListList
-	if err := a.httpClient.getWithContext(ctx, "/v1/connections/", query, &resp); err != nil {
+	if err := a.httpClient.getWithContext(ctx, "/v1/connections/", query, &resp, opts...); err != nil {
 		return generated.PaginatedConnectionListList{}, err
 	}
 	return resp, nil
 }
 
 // Create Create a connection.
-func (a *ConnectionsAPI) Create(connectorType string, name string, config map[string]any, description string, authConfig map[string]any, dynamicInputs map[string]string) (generated.Connection, error) {
-	return a.CreateWithContext(context.Background(), connectorType, name, config, description, authConfig, dynamicInputs)
+func (a *ConnectionsAPI) Create(connectorType string, name string, config map[string]any, description string, authConfig map[string]any, dynamicInputs map[string]string, opts ...RequestOption) (generated.Connection, error) {
+	return a.CreateWithContext(context.Background(), connectorType, name, config, description, authConfig, dynamicInputs, opts...)
 }
 
 // CreateWithContext Create a connection.
-func (a *ConnectionsAPI) CreateWithContext(ctx context.Context, connectorType string, name string, config map[string]any, description string, authConfig map[string]any, dynamicInputs map[string]string) (generated.Connection, error) {
+func (a *ConnectionsAPI) CreateWithContext(ctx context.Context, connectorType string, name string, config map[string]any, description string, authConfig map[string]any, dynamicInputs map[string]string, opts ...RequestOption) (_ generated.Connection, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "ConnectionsAPI.Create")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	query["organization_id"] = a.cfg.OrganizationID
 	payload := map[string]any{}
@@ -69,19 +73,21 @@ func (a *ConnectionsAPI) CreateWithContext(ctx context.Context, connectorType st
 		payload["dynamic_inputs"] = dynamicInputs
 	}
 	var resp generated.Connection
-	if err := a.httpClient.postJSONWithContext(ctx, "/v1/connections/", payload, query, &resp); err != nil {
+	if err := a.httpClient.postJSONWithContext(ctx, "/v1/connections/", payload, query, &resp, opts...); err != nil {
 		return generated.Connection{}, err
 	}
 	return resp, nil
 }
 
 // TestCredentials Test connection credentials without saving a connection.
-func (a *ConnectionsAPI) TestCredentials(connectorType string, config map[string]any, authConfig map[string]any, dynamicInputs map[string]string) (generated.TestConnection, error) {
-	return a.TestCredentialsWithContext(context.Background(), connectorType, config, authConfig, dynamicInputs)
+func (a *ConnectionsAPI) TestCredentials(connectorType string, config map[string]any, authConfig map[string]any, dynamicInputs map[string]string, opts ...RequestOption) (generated.TestConnection, error) {
+	return a.TestCredentialsWithContext(context.Background(), connectorType, config, authConfig, dynamicInputs, opts...)
 }
 
 // TestCredentialsWithContext Test connection credentials without saving a connection.
-func (a *ConnectionsAPI) TestCredentialsWithContext(ctx context.Context, connectorType string, config map[string]any, authConfig map[string]any, dynamicInputs map[string]string) (generated.TestConnection, error) {
+func (a *ConnectionsAPI) TestCredentialsWithContext(ctx context.Context, connectorType string, config map[string]any, authConfig map[string]any, dynamicInputs map[string]string, opts ...RequestOption) (_ generated.TestConnection, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "ConnectionsAPI.TestCredentials")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	payload := map[string]any{}
 	payload["connector_type"] = connectorType
@@ -93,35 +99,39 @@ func (a *ConnectionsAPI) TestCredentialsWithContext(ctx context.Context, connect
 		payload["dynamic_inputs"] = dynamicInputs
 	}
 	var resp generated.TestConnection
-	if err := a.httpClient.postJSONWithContext(ctx, "/v1/connections/test-credentials/", payload, query, &resp); err != nil {
+	if err := a.httpClient.postJSONWithContext(ctx, "/v1/connections/test-credentials/", payload, query, &resp, opts...); err != nil {
 		return generated.TestConnection{}, err
 	}
 	return resp, nil
 }
 
 // Retrieve Retrieve a connection.
-func (a *ConnectionsAPI) Retrieve(connectionID string) (generated.Connection, error) {
-	return a.RetrieveWithContext(context.Background(), connectionID)
+func (a *ConnectionsAPI) Retrieve(connectionID string, opts ...RequestOption) (generated.Connection, error) {
+	return a.RetrieveWithContext(context.Background(), connectionID, opts...)
 }
 
 // RetrieveWithContext Retrieve a connection.
-func (a *ConnectionsAPI) RetrieveWithContext(ctx context.Context, connectionID string) (generated.Connection, error) {
+func (a *ConnectionsAPI) RetrieveWithContext(ctx context.Context, connectionID string, opts ...RequestOption) (_ generated.Connection, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "ConnectionsAPI.Retrieve")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	query["organization_id"] = a.cfg.OrganizationID
 	var resp generated.Connection
-	if err := a.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/connections/%s/", connectionID), query, &resp); err != nil {
+	if err := a.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/connections/%s/", connectionID), query, &resp, opts...); err != nil {
 		return generated.Connection{}, err
 	}
 	return resp, nil
 }
 
 // Update Update mutable connection fields.
-func (a *ConnectionsAPI) Update(connectionID string, name string, description string, config map[string]any, authConfig map[string]any, dynamicInputs map[string]string) (generated.Connection, error) {
-	return a.UpdateWithContext(context.Background(), connectionID, name, description, config, authConfig, dynamicInputs)
+func (a *ConnectionsAPI) Update(connectionID string, name string, description string, config map[string]any, authConfig map[string]any, dynamicInputs map[string]string, opts ...RequestOption) (generated.Connection, error) {
+	return a.UpdateWithContext(context.Background(), connectionID, name, description, config, authConfig, dynamicInputs, opts...)
 }
 
 // UpdateWithContext Update mutable connection fields.
-func (a *ConnectionsAPI) UpdateWithContext(ctx context.Context, connectionID string, name string, description string, config map[string]any, authConfig map[string]any, dynamicInputs map[string]string) (generated.Connection, error) {
+func (a *ConnectionsAPI) UpdateWithContext(ctx context.Context, connectionID string, name string, description string, config map[string]any, authConfig map[string]any, dynamicInputs map[string]string, opts ...RequestOption) (_ generated.Connection, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "ConnectionsAPI.Update")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	query["organization_id"] = a.cfg.OrganizationID
 	payload := map[string]any{}
@@ -141,19 +151,21 @@ func (a *ConnectionsAPI) UpdateWithContext(ctx context.Context, connectionID str
 		payload["dynamic_inputs"] = dynamicInputs
 	}
 	var resp generated.Connection
-	if err := a.httpClient.patchJSONWithContext(ctx, fmt.Sprintf("/v1/connections/%s/", connectionID), payload, query, &resp); err != nil {
+	if err := a.httpClient.patchJSONWithContext(ctx, fmt.Sprintf("/v1/connections/%s/", connectionID), payload, query, &resp, opts...); err != nil {
 		return generated.Connection{}, err
 	}
 	return resp, nil
 }
 
 // Replace Replace a connection.
-func (a *ConnectionsAPI) Replace(connectionID string, name string, description string, config map[string]any, authConfig map[string]any, dynamicInputs map[string]string) (generated.Connection, error) {
-	return a.ReplaceWithContext(context.Background(), connectionID, name, description, config, authConfig, dynamicInputs)
+func (a *ConnectionsAPI) Replace(connectionID string, name string, description string, config map[string]any, authConfig map[string]any, dynamicInputs map[string]string, opts ...RequestOption) (generated.Connection, error) {
+	return a.ReplaceWithContext(context.Background(), connectionID, name, description, config, authConfig, dynamicInputs, opts...)
 }
 
 // ReplaceWithContext Replace a connection.
-func (a *ConnectionsAPI) ReplaceWithContext(ctx context.Context, connectionID string, name string, description string, config map[string]any, authConfig map[string]any, dynamicInputs map[string]string) (generated.Connection, error) {
+func (a *ConnectionsAPI) ReplaceWithContext(ctx context.Context, connectionID string, name string, description string, config map[string]any, authConfig map[string]any, dynamicInputs map[string]string, opts ...RequestOption) (_ generated.Connection, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "ConnectionsAPI.Replace")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	query["organization_id"] = a.cfg.OrganizationID
 	payload := map[string]any{}
@@ -169,35 +181,39 @@ func (a *ConnectionsAPI) ReplaceWithContext(ctx context.Context, connectionID st
 		payload["dynamic_inputs"] = dynamicInputs
 	}
 	var resp generated.Connection
-	if err := a.httpClient.putJSONWithContext(ctx, fmt.Sprintf("/v1/connections/%s/", connectionID), payload, query, &resp); err != nil {
+	if err := a.httpClient.putJSONWithContext(ctx, fmt.Sprintf("/v1/connections/%s/", connectionID), payload, query, &resp, opts...); err != nil {
 		return generated.Connection{}, err
 	}
 	return resp, nil
 }
 
 // Delete Delete a connection.
-func (a *ConnectionsAPI) Delete(connectionID string) error {
-	return a.DeleteWithContext(context.Background(), connectionID)
+func (a *ConnectionsAPI) Delete(connectionID string, opts ...RequestOption) error {
+	return a.DeleteWithContext(context.Background(), connectionID, opts...)
 }
 
 // DeleteWithContext Delete a connection.
-func (a *ConnectionsAPI) DeleteWithContext(ctx context.Context, connectionID string) error {
+func (a *ConnectionsAPI) DeleteWithContext(ctx context.Context, connectionID string, opts ...RequestOption) (err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "ConnectionsAPI.Delete")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	query["organization_id"] = a.cfg.OrganizationID
-	return a.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/connections/%s/", connectionID), query)
+	return a.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/connections/%s/", connectionID), query, opts...)
 }
 
 // Test Test a saved connection.
-func (a *ConnectionsAPI) Test(connectionID string) (generated.TestConnection, error) {
-	return a.TestWithContext(context.Background(), connectionID)
+func (a *ConnectionsAPI) Test(connectionID string, opts ...RequestOption) (generated.TestConnection, error) {
+	return a.TestWithContext(context.Background(), connectionID, opts...)
 }
 
 // TestWithContext Test a saved connection.
-func (a *ConnectionsAPI) TestWithContext(ctx context.Context, connectionID string) (generated.TestConnection, error) {
+func (a *ConnectionsAPI) TestWithContext(ctx context.Context, connectionID string, opts ...RequestOption) (_ generated.TestConnection, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "ConnectionsAPI.Test")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	query["organization_id"] = a.cfg.OrganizationID
 	var resp generated.TestConnection
-	if err := a.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/connections/%s/test/", connectionID), nil, query, &resp); err != nil {
+	if err := a.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/connections/%s/test/", connectionID), nil, query, &resp, opts...); err != nil {
 		return generated.TestConnection{}, err
 	}
 	return resp, nil
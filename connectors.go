@@ -19,30 +19,34 @@ func newConnectorsAPI(cfg Config, httpClient *httpClient) *ConnectorsAPI {
 }
 
 // List List available connector types.
-func (a *ConnectorsAPI) List() (generated.ConnectorListResponse, error) {
-	return a.ListWithContext(context.Background())
+func (a *ConnectorsAPI) List(opts ...RequestOption) (generated.ConnectorListResponse, error) {
+	return a.ListWithContext(context.Background(), opts...)
 }
 
 // ListWithContext List available connector types.
-func (a *ConnectorsAPI) ListWithContext(ctx context.Context) (generated.ConnectorListResponse, error) {
+func (a *ConnectorsAPI) ListWithContext(ctx context.Context, opts ...RequestOption) (_ generated.ConnectorListResponse, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "ConnectorsAPI.List")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	var resp generated.ConnectorListResponse
-	if err := a.httpClient.getWithContext(ctx, "/v1/connectors/", query, &resp); err != nil {
+	if err := a.httpClient.getWithContext(ctx, "/v1/connectors/", query, &resp, opts...); err != nil {
 		return generated.ConnectorListResponse{}, err
 	}
 	return resp, nil
 }
 
 // Retrieve Retrieve metadata for a connector type.
-func (a *ConnectorsAPI) Retrieve(connectorType string) (generated.ConnectorMetadata, error) {
-	return a.RetrieveWithContext(context.Background(), connectorType)
+func (a *ConnectorsAPI) Retrieve(connectorType string, opts ...RequestOption) (generated.ConnectorMetadata, error) {
+	return a.RetrieveWithContext(context.Background(), connectorType, opts...)
 }
 
 // RetrieveWithContext Retrieve metadata for a connector type.
-func (a *ConnectorsAPI) RetrieveWithContext(ctx context.Context, connectorType string) (generated.ConnectorMetadata, error) {
+func (a *ConnectorsAPI) RetrieveWithContext(ctx context.Context, connectorType string, opts ...RequestOption) (_ generated.ConnectorMetadata, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "ConnectorsAPI.Retrieve")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	var resp generated.ConnectorMetadata
-	if err := a.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/connectors/%s/", connectorType), query, &resp); err != nil {
+	if err := a.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/connectors/%s/", connectorType), query, &resp, opts...); err != nil {
 		return generated.ConnectorMetadata{}, err
 	}
 	return resp, nil
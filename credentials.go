@@ -0,0 +1,135 @@
+package roe
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialsProvider supplies the API key for every request, so keys can be
+// rotated without rebuilding the client. Implementations must be safe for
+// concurrent use and should cache: APIKey is called once per HTTP attempt.
+//
+// When the API answers 401, the client calls Refresh and, if the key changed,
+// retries the request once with the new key.
+type CredentialsProvider interface {
+	APIKey(ctx context.Context) (string, error)
+	// Refresh discards any cached key so the next APIKey call fetches a
+	// fresh one.
+	Refresh(ctx context.Context) error
+}
+
+// CredentialsFunc adapts a function to CredentialsProvider. It is called for
+// every attempt; wrap it with NewCachedCredentials when fetching is costly.
+type CredentialsFunc func(ctx context.Context) (string, error)
+
+// APIKey calls f.
+func (f CredentialsFunc) APIKey(ctx context.Context) (string, error) { return f(ctx) }
+
+// Refresh is a no-op: f is called afresh every time anyway.
+func (f CredentialsFunc) Refresh(context.Context) error { return nil }
+
+// StaticCredentials returns a provider that always supplies key. It is what
+// the client uses when only Config.APIKey is set.
+func StaticCredentials(key string) CredentialsProvider {
+	return CredentialsFunc(func(context.Context) (string, error) { return key, nil })
+}
+
+// EnvCredentials returns a provider that reads the environment variable name
+// on every call, so a key updated in the process environment takes effect on
+// the next request.
+func EnvCredentials(name string) CredentialsProvider {
+	return CredentialsFunc(func(context.Context) (string, error) {
+		key := os.Getenv(name)
+		if key == "" {
+			return "", fmt.Errorf("environment variable %s is empty", name)
+		}
+		return key, nil
+	})
+}
+
+// FileCredentials returns a provider that reads the key from path, trimming
+// surrounding whitespace. The file is stat'ed on each call and re-read only
+// when its size or modification time changes, so a mounted secret that is
+// rewritten in place is picked up without a restart.
+func FileCredentials(path string) CredentialsProvider {
+	return &fileCredentials{path: path}
+}
+
+type fileCredentials struct {
+	path string
+
+	mu      sync.Mutex
+	key     string
+	modTime time.Time
+	size    int64
+}
+
+func (f *fileCredentials) APIKey(context.Context) (string, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("read credentials file: %w", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.key != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.key, nil
+	}
+	raw, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("read credentials file: %w", err)
+	}
+	key := strings.TrimSpace(string(raw))
+	if key == "" {
+		return "", fmt.Errorf("credentials file %s is empty", f.path)
+	}
+	f.key, f.modTime, f.size = key, info.ModTime(), info.Size()
+	return key, nil
+}
+
+func (f *fileCredentials) Refresh(context.Context) error {
+	f.mu.Lock()
+	f.key = ""
+	f.mu.Unlock()
+	return nil
+}
+
+// NewCachedCredentials caches the keys supplied by provider for ttl, which
+// suits providers that call out to a secrets manager. Concurrent callers that
+// miss the cache share a single fetch. A ttl of zero caches until Refresh.
+func NewCachedCredentials(provider CredentialsProvider, ttl time.Duration) CredentialsProvider {
+	return &cachedCredentials{provider: provider, ttl: ttl}
+}
+
+type cachedCredentials struct {
+	provider CredentialsProvider
+	ttl      time.Duration
+
+	mu        sync.Mutex
+	key       string
+	fetchedAt time.Time
+}
+
+func (c *cachedCredentials) APIKey(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.key != "" && (c.ttl <= 0 || time.Since(c.fetchedAt) < c.ttl) {
+		return c.key, nil
+	}
+	key, err := c.provider.APIKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.key, c.fetchedAt = key, time.Now()
+	return key, nil
+}
+
+func (c *cachedCredentials) Refresh(ctx context.Context) error {
+	c.mu.Lock()
+	c.key = ""
+	c.mu.Unlock()
+	return c.provider.Refresh(ctx)
+}
@@ -0,0 +1,206 @@
+package roe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileCredentialsRereadsChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte("first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider := FileCredentials(path)
+	ctx := context.Background()
+
+	if key, err := provider.APIKey(ctx); err != nil || key != "first" {
+		t.Fatalf("expected first, got %q (%v)", key, err)
+	}
+	if err := os.WriteFile(path, []byte("second-key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := provider.APIKey(ctx); err != nil || key != "second-key" {
+		t.Fatalf("expected the rewritten key, got %q (%v)", key, err)
+	}
+}
+
+func TestCachedCredentialsShareFetchesUntilRefresh(t *testing.T) {
+	var fetches atomic.Int32
+	provider := NewCachedCredentials(CredentialsFunc(func(context.Context) (string, error) {
+		fetches.Add(1)
+		return "key", nil
+	}), 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if key, err := provider.APIKey(context.Background()); err != nil || key != "key" {
+				t.Errorf("unexpected key %q (%v)", key, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if fetches.Load() != 1 {
+		t.Fatalf("expected one fetch, got %d", fetches.Load())
+	}
+	if err := provider.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.APIKey(context.Background()); err != nil || fetches.Load() != 2 {
+		t.Fatalf("expected Refresh to force a fetch, got %d fetches (%v)", fetches.Load(), err)
+	}
+}
+
+func TestClientRefreshesCredentialsOnceAfter401(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Get("Authorization"))
+		mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"detail":"Invalid API key."}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"agent-1","name":"a"}`))
+	}))
+	defer server.Close()
+
+	var current atomic.Value
+	current.Store("old")
+	rotating := NewCachedCredentials(CredentialsFunc(func(context.Context) (string, error) {
+		return current.Load().(string), nil
+	}), time.Hour)
+
+	client, err := NewClientWithConfig(Config{OrganizationID: "org", BaseURL: server.URL, Credentials: rotating, MaxRetries: 0})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Agents.Retrieve("agent-1"); err == nil {
+		t.Fatal("expected 401 while the source still holds the old key")
+	}
+	current.Store("new")
+	if _, err := client.Agents.Retrieve("agent-1"); err != nil {
+		t.Fatalf("expected the refreshed key to succeed, got %v", err)
+	}
+	want := []string{"Bearer old", "Bearer old", "Bearer new"}
+	if len(seen) != len(want) {
+		t.Fatalf("expected requests %v, got %v", want, seen)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("expected requests %v, got %v", want, seen)
+		}
+	}
+}
+
+func TestClientRefreshWithOneShotBodyReturnsAuthenticationError(t *testing.T) {
+	requests := 0
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"detail":"Invalid API key."}`))
+	}))
+	defer server.Close()
+
+	var fetches atomic.Int32
+	rotating := NewCachedCredentials(CredentialsFunc(func(context.Context) (string, error) {
+		return fmt.Sprintf("key-%d", fetches.Add(1)), nil
+	}), time.Hour)
+	metrics := &recordingMetrics{}
+	cfg := Config{OrganizationID: "org", BaseURL: server.URL, Timeout: time.Second, Credentials: rotating, Metrics: metrics}
+	client := newHTTPClient(cfg, newAuth(cfg))
+	defer client.close()
+
+	// io.MultiReader hides the Seek method, so the upload is one-shot.
+	err := client.postDynamicInputs("/upload", map[string]any{
+		"upload": FileUpload{Reader: io.MultiReader(strings.NewReader("streamed")), Filename: "stream.txt"},
+	}, nil, nil, nil)
+	var authErr *AuthenticationError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("expected *AuthenticationError, got %T: %v", err, err)
+	}
+	if requests != 1 || len(metrics.requests) != 1 || metrics.requests[0].Attempt != 0 {
+		t.Fatalf("expected one sent attempt, got %d requests and metrics %+v", requests, metrics.requests)
+	}
+}
+
+func TestClientSurfacesCredentialsErrors(t *testing.T) {
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request should be sent without a key")
+	}))
+	defer server.Close()
+
+	missing := errors.New("vault unavailable")
+	client, err := NewClientWithParams(ConfigParams{
+		OrganizationID: "org",
+		BaseURL:        server.URL,
+		Credentials:    CredentialsFunc(func(context.Context) (string, error) { return "", missing }),
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Agents.Retrieve("agent-1"); !errors.Is(err, missing) {
+		t.Fatalf("expected the provider error, got %v", err)
+	}
+}
+
+func TestClientCredentialsErrorDoesNotHangMultipartUpload(t *testing.T) {
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request should be sent without a key")
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "upload.txt")
+	if err := os.WriteFile(path, []byte("hello world"), 0o600); err != nil {
+		t.Fatalf("write upload: %v", err)
+	}
+
+	missing := errors.New("vault unavailable")
+	client, err := NewClientWithParams(ConfigParams{
+		OrganizationID: "org",
+		BaseURL:        server.URL,
+		Credentials:    CredentialsFunc(func(context.Context) (string, error) { return "", missing }),
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Agents.Run("agent-1", 0, map[string]any{"upload": FileUpload{Path: path}}, nil)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, missing) {
+			t.Fatalf("expected the provider error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("multipart run hung after the credentials provider failed")
+	}
+}
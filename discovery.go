@@ -19,33 +19,37 @@ func newDiscoveryAPI(cfg Config, httpClient *httpClient) *DiscoveryAPI {
 }
 
 // ListAgentEngineTypes Return production engine_class_id values accepted by agent creation.
-func (a *DiscoveryAPI) ListAgentEngineTypes() (generated.AgentEngineTypeList, error) {
-	return a.ListAgentEngineTypesWithContext(context.Background())
+func (a *DiscoveryAPI) ListAgentEngineTypes(opts ...RequestOption) (generated.AgentEngineTypeList, error) {
+	return a.ListAgentEngineTypesWithContext(context.Background(), opts...)
 }
 
 // ListAgentEngineTypesWithContext Return production engine_class_id values accepted by agent creation.
-func (a *DiscoveryAPI) ListAgentEngineTypesWithContext(ctx context.Context) (generated.AgentEngineTypeList, error) {
+func (a *DiscoveryAPI) ListAgentEngineTypesWithContext(ctx context.Context, opts ...RequestOption) (_ generated.AgentEngineTypeList, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "DiscoveryAPI.ListAgentEngineTypes")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	var resp generated.AgentEngineTypeList
-	if err := a.httpClient.getWithContext(ctx, "/v1/agents/types/", query, &resp); err != nil {
+	if err := a.httpClient.getWithContext(ctx, "/v1/agents/types/", query, &resp, opts...); err != nil {
 		return generated.AgentEngineTypeList{}, err
 	}
 	return resp, nil
 }
 
 // ListSupportedModels Return non-deprecated model IDs accepted in engine_config.model.
-func (a *DiscoveryAPI) ListSupportedModels(capability string) (generated.SupportedLLMModelList, error) {
-	return a.ListSupportedModelsWithContext(context.Background(), capability)
+func (a *DiscoveryAPI) ListSupportedModels(capability string, opts ...RequestOption) (generated.SupportedLLMModelList, error) {
+	return a.ListSupportedModelsWithContext(context.Background(), capability, opts...)
 }
 
 // ListSupportedModelsWithContext Return non-deprecated model IDs accepted in engine_config.model.
-func (a *DiscoveryAPI) ListSupportedModelsWithContext(ctx context.Context, capability string) (generated.SupportedLLMModelList, error) {
+func (a *DiscoveryAPI) ListSupportedModelsWithContext(ctx context.Context, capability string, opts ...RequestOption) (_ generated.SupportedLLMModelList, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "DiscoveryAPI.ListSupportedModels")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	if capability != "" {
 		query["capability"] = fmt.Sprint(capability)
 	}
 	var resp generated.SupportedLLMModelList
-	if err := a.httpClient.getWithContext(ctx, "/v1/agents/models/", query, &resp); err != nil {
+	if err := a.httpClient.getWithContext(ctx, "/v1/agents/models/", query, &resp, opts...); err != nil {
 		return generated.SupportedLLMModelList{}, err
 	}
 	return resp, nil
@@ -1,10 +1,13 @@
 package roe
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -13,6 +16,7 @@ import (
 var (
 	ErrMissingAPIKey         = errors.New("API key is required. Provide it or set ROE_API_KEY")
 	ErrMissingOrganizationID = errors.New("Organization ID is required. Provide it or set ROE_ORGANIZATION_ID")
+	ErrCircuitOpen           = errors.New("circuit breaker is open")
 )
 
 // APIError represents an error returned by the Roe API.
@@ -22,6 +26,9 @@ type APIError struct {
 	Body       []byte
 	RequestID  string
 	Details    map[string]any
+	// Attempts logs every HTTP attempt of the request that returned this
+	// error, including retries.
+	Attempts []RequestAttempt
 }
 
 func (e *APIError) Error() string {
@@ -34,7 +41,33 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("roe api error (%d): %s", e.StatusCode, e.Message)
 }
 
-type BadRequestError struct{ *APIError }
+// apiError is promoted to every typed error embedding *APIError, so
+// errors.As can recover the shared fields from any of them.
+func (e *APIError) apiError() *APIError {
+	return e
+}
+
+// BadRequestError is returned for 400 responses. Validation failures in the
+// Django REST framework shape, such as {"inputs": {"text": ["This field is
+// required."]}}, are parsed into FieldErrors and NonFieldErrors.
+type BadRequestError struct {
+	*APIError
+	FieldErrors    []FieldError
+	NonFieldErrors []string
+}
+
+// FieldError is one validation message for a request field. Field is a path
+// into the request body, such as "inputs.text" or
+// "input_definitions[2].data_type".
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) String() string {
+	return e.Field + ": " + e.Message
+}
+
 type AuthenticationError struct{ *APIError }
 type InsufficientCreditsError struct{ *APIError }
 type ForbiddenError struct{ *APIError }
@@ -45,6 +78,120 @@ type RateLimitError struct {
 }
 type ServerError struct{ *APIError }
 
+// ConflictError is returned for 409 responses.
+type ConflictError struct{ *APIError }
+
+// ResourceInUseError is the ConflictError returned when a deletion is blocked
+// by agents that still reference the resource, such as a policy in use.
+// Detach or delete DependentAgents and retry. errors.As also matches it as a
+// *ConflictError.
+type ResourceInUseError struct {
+	*ConflictError
+	DependentAgents []DependentAgent
+}
+
+func (e *ResourceInUseError) Unwrap() error { return e.ConflictError }
+
+// CleanupFailedError is the ServerError returned when an agent or agent
+// version was deleted but some of its vector collections could not be
+// removed. The deletion itself succeeded, so it is not retried. errors.As
+// also matches it as a *ServerError.
+type CleanupFailedError struct {
+	*ServerError
+	FailedCollections []string
+}
+
+func (e *CleanupFailedError) Unwrap() error { return e.ServerError }
+
+// RequestError is returned when a request that was retried fails with a
+// transport error, a cancelled context or another error that is not an API
+// response; API errors carry their attempt log in APIError.Attempts instead.
+// It wraps the final error, so errors.As and errors.Is still find it, and
+// keeps a log of every attempt.
+type RequestError struct {
+	Method   string
+	Path     string
+	Attempts []RequestAttempt
+	Err      error
+}
+
+// RequestAttempt records one HTTP attempt of a failed request.
+type RequestAttempt struct {
+	// StatusCode is zero when the attempt failed at the transport level.
+	StatusCode int
+	Err        error
+	RequestID  string
+	Duration   time.Duration
+	// Delay is the backoff waited after this attempt before the next one.
+	Delay time.Duration
+}
+
+func (e *RequestError) Error() string {
+	if len(e.Attempts) <= 1 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v (after %d attempts, %s backoff)", e.Err, len(e.Attempts), e.Backoff())
+}
+
+func (e *RequestError) Unwrap() error { return e.Err }
+
+// Backoff is the total time spent waiting between attempts.
+func (e *RequestError) Backoff() time.Duration {
+	var total time.Duration
+	for _, attempt := range e.Attempts {
+		total += attempt.Delay
+	}
+	return total
+}
+
+// IsNotFound reports whether err, or any error it wraps, is a 404.
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
+
+// IsTransient reports whether err is a temporary failure of the network or
+// the API: a transport timeout or connection error, a 5xx or 408 response, or
+// an open circuit breaker. Cancelled contexts and CleanupFailedError (the
+// deletion itself succeeded) are not transient.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+	var cleanupErr *CleanupFailedError
+	if errors.As(err, &cleanupErr) {
+		return false
+	}
+	var apiErr interface{ apiError() *APIError }
+	if errors.As(err, &apiErr) {
+		status := apiErr.apiError().StatusCode
+		return status >= 500 || status == http.StatusRequestTimeout
+	}
+	return isTransientNetError(err)
+}
+
+// IsRetryable reports whether calling again later may succeed: err is
+// transient or a 429 rate limit. Whether repeating the call is safe is up to
+// the caller; agent runs are protected by their idempotency keys.
+func IsRetryable(err error) bool {
+	var rateLimit *RateLimitError
+	return IsTransient(err) || errors.As(err, &rateLimit)
+}
+
+// isTransientNetError reports transport timeouts and connection errors
+// (DNS, connection refused, etc.).
+func isTransientNetError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
 // apiErrorFromResponse maps an HTTP status code and optional JSON body to a typed error.
 func apiErrorFromResponse(status int, body []byte, headers http.Header, requestIDHeader string) error {
 	message, details := extractErrorDetail(status, body)
@@ -63,7 +210,12 @@ func apiErrorFromResponse(status int, body []byte, headers http.Header, requestI
 
 	switch status {
 	case http.StatusBadRequest:
-		return &BadRequestError{APIError: base}
+		badRequest := &BadRequestError{APIError: base}
+		badRequest.FieldErrors, badRequest.NonFieldErrors = parseValidationErrors(details)
+		if findDetailString(details) == "" && (len(badRequest.FieldErrors) > 0 || len(badRequest.NonFieldErrors) > 0) {
+			base.Message = badRequest.summary()
+		}
+		return badRequest
 	case http.StatusUnauthorized:
 		return &AuthenticationError{APIError: base}
 	case http.StatusPaymentRequired:
@@ -72,11 +224,27 @@ func apiErrorFromResponse(status int, body []byte, headers http.Header, requestI
 		return &ForbiddenError{APIError: base}
 	case http.StatusNotFound:
 		return &NotFoundError{APIError: base}
+	case http.StatusConflict:
+		conflict := &ConflictError{APIError: base}
+		var inUse struct {
+			DependentAgents []DependentAgent `json:"dependent_agents"`
+		}
+		if json.Unmarshal(body, &inUse) == nil && inUse.DependentAgents != nil {
+			return &ResourceInUseError{ConflictError: conflict, DependentAgents: inUse.DependentAgents}
+		}
+		return conflict
 	case http.StatusTooManyRequests:
 		return &RateLimitError{APIError: base, RetryAfter: parseRetryAfter(headers)}
 	default:
 		if status >= 500 {
-			return &ServerError{APIError: base}
+			serverErr := &ServerError{APIError: base}
+			var cleanup struct {
+				FailedCollections []string `json:"failed_collections"`
+			}
+			if json.Unmarshal(body, &cleanup) == nil && cleanup.FailedCollections != nil {
+				return &CleanupFailedError{ServerError: serverErr, FailedCollections: cleanup.FailedCollections}
+			}
+			return serverErr
 		}
 		return base
 	}
@@ -113,6 +281,109 @@ func findDetailString(parsed map[string]any) string {
 	return ""
 }
 
+// summary joins the validation messages into one line for Error().
+func (e *BadRequestError) summary() string {
+	parts := append([]string(nil), e.NonFieldErrors...)
+	for _, fieldErr := range e.FieldErrors {
+		parts = append(parts, fieldErr.String())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// parseValidationErrors walks a DRF validation body. Lists of strings are the
+// messages for the enclosing path, nested objects extend the path with
+// ".key" and lists of objects with "[i]". Top-level and nested
+// "non_field_errors" apply to the enclosing object; at the top level they
+// are returned separately, in key order. Field errors are sorted by path.
+func parseValidationErrors(details map[string]any) ([]FieldError, []string) {
+	var fieldErrs []FieldError
+	var nonField []string
+	var walk func(path string, value any)
+	walk = func(path string, value any) {
+		switch v := value.(type) {
+		case string:
+			if path == "" {
+				nonField = append(nonField, v)
+			} else {
+				fieldErrs = append(fieldErrs, FieldError{Field: path, Message: v})
+			}
+		case []any:
+			for i, item := range v {
+				if _, ok := item.(string); ok {
+					walk(path, item)
+				} else {
+					walk(fmt.Sprintf("%s[%d]", path, i), item)
+				}
+			}
+		case map[string]any:
+			for _, key := range sortedKeys(v) {
+				item := v[key]
+				switch {
+				case key == "non_field_errors":
+					walk(path, item)
+				case path == "":
+					walk(key, item)
+				default:
+					walk(path+"."+key, item)
+				}
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(details) {
+		value := details[key]
+		switch key {
+		case "detail", "message", "error", "code":
+			// Carried in APIError.Message and Details; a field with one of
+			// these names has a list of messages instead.
+			if _, ok := value.(string); ok {
+				continue
+			}
+		}
+		walk("", map[string]any{key: value})
+	}
+	sort.SliceStable(fieldErrs, func(i, j int) bool { return fieldPathLess(fieldErrs[i].Field, fieldErrs[j].Field) })
+	return fieldErrs, nonField
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fieldPathLess orders field paths segment by segment, comparing list
+// indexes numerically so that "items[2]" sorts before "items[10]".
+func fieldPathLess(a, b string) bool {
+	for a != "" && b != "" {
+		segA, restA := nextPathSegment(a)
+		segB, restB := nextPathSegment(b)
+		if segA != segB {
+			indexA, errA := strconv.Atoi(strings.Trim(segA, "[]"))
+			indexB, errB := strconv.Atoi(strings.Trim(segB, "[]"))
+			if strings.HasPrefix(segA, "[") && strings.HasPrefix(segB, "[") && errA == nil && errB == nil {
+				return indexA < indexB
+			}
+			return segA < segB
+		}
+		a, b = restA, restB
+	}
+	return a == "" && b != ""
+}
+
+// nextPathSegment splits the first ".key" or "[i]" segment off a field path.
+func nextPathSegment(path string) (string, string) {
+	path = strings.TrimPrefix(path, ".")
+	end := strings.IndexAny(path[1:], ".[")
+	if end < 0 {
+		return path, ""
+	}
+	return path[:end+1], path[end+1:]
+}
+
 func parseRetryAfter(headers http.Header) *time.Duration {
 	if headers == nil {
 		return nil
@@ -1,7 +1,12 @@
 package roe
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -61,6 +66,12 @@ func TestAPIErrorTypes(t *testing.T) {
 			body:       []byte(`Bad gateway`),
 			wantType:   "*roe.ServerError",
 		},
+		{
+			name:       "Conflict",
+			statusCode: http.StatusConflict,
+			body:       []byte(`{"error": "Already exists"}`),
+			wantType:   "*roe.ConflictError",
+		},
 		{
 			name:       "GenericClientError",
 			statusCode: 418, // I'm a teapot
@@ -285,6 +296,136 @@ func TestAPIErrorErrorMethod(t *testing.T) {
 	}
 }
 
+func TestBadRequestErrorFieldErrors(t *testing.T) {
+	body := []byte(`{
+		"inputs": {"text": ["This field is required."]},
+		"input_definitions": [{}, {}, {"data_type": ["\"blob\" is not a valid choice."]}],
+		"engine_config": {"non_field_errors": ["Unknown model."]},
+		"non_field_errors": ["Agent name already exists."]
+	}`)
+	err := apiErrorFromResponse(http.StatusBadRequest, body, nil, "")
+	var badRequest *BadRequestError
+	if !errors.As(err, &badRequest) {
+		t.Fatalf("expected *BadRequestError, got %T", err)
+	}
+
+	want := []FieldError{
+		{Field: "engine_config", Message: "Unknown model."},
+		{Field: "input_definitions[2].data_type", Message: `"blob" is not a valid choice.`},
+		{Field: "inputs.text", Message: "This field is required."},
+	}
+	if len(badRequest.FieldErrors) != len(want) {
+		t.Fatalf("expected %v, got %v", want, badRequest.FieldErrors)
+	}
+	for i := range want {
+		if badRequest.FieldErrors[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, badRequest.FieldErrors)
+		}
+	}
+	if len(badRequest.NonFieldErrors) != 1 || badRequest.NonFieldErrors[0] != "Agent name already exists." {
+		t.Fatalf("unexpected non-field errors %v", badRequest.NonFieldErrors)
+	}
+	wantMessage := `Agent name already exists.; engine_config: Unknown model.; input_definitions[2].data_type: "blob" is not a valid choice.; inputs.text: This field is required.`
+	if badRequest.Message != wantMessage {
+		t.Fatalf("expected message %q, got %q", wantMessage, badRequest.Message)
+	}
+
+	listed := apiErrorFromResponse(http.StatusBadRequest, []byte(`{
+		"items": {"10": ["Too long."]},
+		"rows": [{}, {}, {"name": ["Blank."]}, {}, {}, {}, {}, {}, {}, {}, {"name": ["Taken."]}],
+		"code": ["Enter a valid code."]
+	}`), nil, "").(*BadRequestError)
+	wantListed := []string{"code", "items.10", "rows[2].name", "rows[10].name"}
+	if len(listed.FieldErrors) != len(wantListed) {
+		t.Fatalf("expected fields %v, got %v", wantListed, listed.FieldErrors)
+	}
+	for i, field := range wantListed {
+		if listed.FieldErrors[i].Field != field {
+			t.Fatalf("expected fields %v, got %v", wantListed, listed.FieldErrors)
+		}
+	}
+
+	detailOnly := apiErrorFromResponse(http.StatusBadRequest, []byte(`{"detail": "Invalid input"}`), nil, "").(*BadRequestError)
+	if detailOnly.Message != "Invalid input" || len(detailOnly.FieldErrors) != 0 || len(detailOnly.NonFieldErrors) != 0 {
+		t.Fatalf("expected a plain detail error, got %+v", detailOnly)
+	}
+}
+
+func TestCleanupFailedErrorIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"detail":"Agent deleted but cleanup failed.","failed_collections":["agent_a1_docs"]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithConfig(Config{
+		APIKey:               "k",
+		OrganizationID:       "org",
+		BaseURL:              server.URL,
+		Timeout:              time.Second,
+		MaxRetries:           3,
+		RetryInitialInterval: time.Millisecond,
+		RetryMaxInterval:     time.Millisecond,
+		RetryMultiplier:      1,
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	err = client.Agents.Delete("a1")
+	var cleanup *CleanupFailedError
+	if !errors.As(err, &cleanup) {
+		t.Fatalf("expected *CleanupFailedError, got %T: %v", err, err)
+	}
+	if len(cleanup.FailedCollections) != 1 || cleanup.FailedCollections[0] != "agent_a1_docs" {
+		t.Fatalf("unexpected failed collections %v", cleanup.FailedCollections)
+	}
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) {
+		t.Fatal("expected the error to match *ServerError")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected no retries, got %d calls", calls.Load())
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+		retryable bool
+	}{
+		{"ServerError", apiErrorFromResponse(http.StatusBadGateway, nil, nil, ""), true, true},
+		{"RequestTimeout", apiErrorFromResponse(http.StatusRequestTimeout, nil, nil, ""), true, true},
+		{"RateLimited", apiErrorFromResponse(http.StatusTooManyRequests, nil, nil, ""), false, true},
+		{"BadRequest", apiErrorFromResponse(http.StatusBadRequest, nil, nil, ""), false, false},
+		{"CleanupFailed", apiErrorFromResponse(http.StatusInternalServerError, []byte(`{"detail":"x","failed_collections":["c"]}`), nil, ""), false, false},
+		{"CircuitOpen", fmt.Errorf("list agents: %w", &CircuitOpenError{}), true, true},
+		{"ConnectionRefused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true, true},
+		{"Cancelled", &RequestError{Err: context.Canceled}, false, false},
+		{"Nil", nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := tt.err
+			if wrapped != nil {
+				wrapped = fmt.Errorf("run agent a1: %w", &RequestError{Err: tt.err})
+			}
+			if got := IsTransient(wrapped); got != tt.transient {
+				t.Errorf("IsTransient = %v, want %v", got, tt.transient)
+			}
+			if got := IsRetryable(wrapped); got != tt.retryable {
+				t.Errorf("IsRetryable = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
+
 func extractAPIError(err error) *APIError {
 	switch e := err.(type) {
 	case *BadRequestError:
@@ -301,6 +442,8 @@ func extractAPIError(err error) *APIError {
 		return e.APIError
 	case *ServerError:
 		return e.APIError
+	case *ConflictError:
+		return e.APIError
 	case *APIError:
 		return e
 	default:
@@ -328,6 +471,8 @@ func getShortTypeName(err error) string {
 		return "RateLimitError"
 	case *ServerError:
 		return "ServerError"
+	case *ConflictError:
+		return "ConflictError"
 	case *APIError:
 		return "APIError"
 	default:
@@ -22,11 +22,13 @@ func TestPostDynamicInputsWithFile(t *testing.T) {
 
 	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
-			t.Fatalf("expected multipart content type, got %s", r.Header.Get("Content-Type"))
+			t.Errorf("expected multipart content type, got %s", r.Header.Get("Content-Type"))
+			return
 		}
 		reader, err := r.MultipartReader()
 		if err != nil {
-			t.Fatalf("multipart reader: %v", err)
+			t.Errorf("multipart reader: %v", err)
+			return
 		}
 		seenFile := false
 		for {
@@ -35,28 +37,33 @@ func TestPostDynamicInputsWithFile(t *testing.T) {
 				break
 			}
 			if err != nil {
-				t.Fatalf("read part: %v", err)
+				t.Errorf("read part: %v", err)
+				return
 			}
 			defer part.Close()
 			switch part.FormName() {
 			case "text":
 				content, _ := io.ReadAll(part)
 				if string(content) != "greeting" {
-					t.Fatalf("unexpected text field %s", string(content))
+					t.Errorf("unexpected text field %s", string(content))
+					return
 				}
 			case "upload":
 				seenFile = true
 				if part.FileName() == "" {
-					t.Fatalf("expected filename on upload")
+					t.Errorf("expected filename on upload")
+					return
 				}
 				content, _ := io.ReadAll(part)
 				if string(content) != "hello world" {
-					t.Fatalf("unexpected file content: %s", string(content))
+					t.Errorf("unexpected file content: %s", string(content))
+					return
 				}
 			}
 		}
 		if !seenFile {
-			t.Fatalf("expected to see file upload")
+			t.Errorf("expected to see file upload")
+			return
 		}
 		w.Header().Set("Content-Type", "application/json")
 		_, _ = w.Write([]byte(`{"ok":true}`))
@@ -94,11 +101,13 @@ func TestPostDynamicInputsWithFile(t *testing.T) {
 func TestPostDynamicInputsWithURLInput(t *testing.T) {
 	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
-			t.Fatalf("expected urlencoded content type, got %s", r.Header.Get("Content-Type"))
+			t.Errorf("expected urlencoded content type, got %s", r.Header.Get("Content-Type"))
+			return
 		}
 		body, _ := io.ReadAll(r.Body)
 		if string(body) != "upload=https%3A%2F%2Fexample.com%2Ffile.pdf" {
-			t.Fatalf("unexpected form body: %s", string(body))
+			t.Errorf("unexpected form body: %s", string(body))
+			return
 		}
 		w.Header().Set("Content-Type", "application/json")
 		_, _ = w.Write([]byte(`{"ok":true}`))
@@ -128,3 +137,151 @@ func TestPostDynamicInputsWithURLInput(t *testing.T) {
 		t.Fatalf("request failed: %v", err)
 	}
 }
+
+func TestPostDynamicInputsReplaysFileOnRetry(t *testing.T) {
+	tmp, err := os.CreateTemp("", "roe-upload-*.txt")
+	if err != nil {
+		t.Fatalf("create temp file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString("hello world"); err != nil {
+		t.Fatalf("write temp file: %v", err)
+	}
+	tmp.Close()
+
+	attempts := 0
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.ContentLength <= 0 {
+			t.Errorf("attempt %d: expected known content length, got %d", attempts, r.ContentLength)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("attempt %d: parse multipart: %v", attempts, err)
+			return
+		}
+		file, _, err := r.FormFile("upload")
+		if err != nil {
+			t.Errorf("attempt %d: form file: %v", attempts, err)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		file.Close()
+		if string(content) != "hello world" {
+			t.Errorf("attempt %d: unexpected file content: %q", attempts, content)
+			return
+		}
+		if attempts < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		APIKey:               "k",
+		OrganizationID:       "org",
+		BaseURL:              server.URL,
+		Timeout:              time.Second,
+		MaxRetries:           1,
+		RetryInitialInterval: 5 * time.Millisecond,
+		RetryMaxInterval:     5 * time.Millisecond,
+		RetryMultiplier:      1,
+		RetryJitter:          0,
+	}
+
+	client := newHTTPClient(cfg, newAuth(cfg))
+	defer client.close()
+
+	var out map[string]bool
+	err = client.postDynamicInputs("/upload", map[string]any{
+		"text":   "greeting",
+		"upload": FileUpload{Path: tmp.Name()},
+	}, nil, &out, nil)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestPostDynamicInputsDoesNotReplayOneShotReader(t *testing.T) {
+	attempts := 0
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"detail":"unavailable"}`))
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		APIKey:               "k",
+		OrganizationID:       "org",
+		BaseURL:              server.URL,
+		Timeout:              time.Second,
+		MaxRetries:           2,
+		RetryInitialInterval: 5 * time.Millisecond,
+		RetryMaxInterval:     5 * time.Millisecond,
+		RetryMultiplier:      1,
+		RetryJitter:          0,
+	}
+
+	client := newHTTPClient(cfg, newAuth(cfg))
+	defer client.close()
+
+	// io.MultiReader hides the Seek method, so the upload is one-shot.
+	reader := io.MultiReader(strings.NewReader("streamed content"))
+	err := client.postDynamicInputs("/upload", map[string]any{
+		"upload": FileUpload{Reader: reader, Filename: "stream.txt"},
+	}, nil, nil, nil)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if _, ok := err.(*ServerError); !ok {
+		t.Fatalf("expected the original server error, got %T: %v", err, err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected a single attempt for a one-shot reader, got %d", attempts)
+	}
+}
+
+type closeRecorder struct{ closed bool }
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestMultipartBodyCloseStopsUnreadAttempts(t *testing.T) {
+	closer := &closeRecorder{}
+	body := newMultipartBody(nil, []*multipartFile{{
+		fieldName: "upload",
+		filename:  "upload.txt",
+		mimeType:  "text/plain",
+		size:      -1,
+		open: func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("hello world")), nil
+		},
+		closer: closer,
+	}})
+	if _, err := body.open(); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		body.close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("close blocked on a body that was never read")
+	}
+	if !closer.closed {
+		t.Fatalf("expected the caller's reader to be closed")
+	}
+}
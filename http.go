@@ -10,13 +10,8 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"math"
-	mrand "math/rand"
-	"mime"
-	"mime/multipart"
-	"net"
+	"log/slog"
 	"net/http"
-	"net/textproto"
 	"net/url"
 	"os"
 	"strings"
@@ -25,8 +20,10 @@ import (
 
 type httpClient struct {
 	client    *http.Client
+	transport http.RoundTripper // base transport beneath any middleware
 	cfg       Config
 	auth      Auth
+	retry     RetryPolicy
 	logger    Logger
 	redactMap map[string]struct{}
 }
@@ -57,15 +54,22 @@ func newHTTPClient(cfg Config, auth Auth) *httpClient {
 		cfg.IdleConnTimeout = defaultIdleConnTimeout
 	}
 
-	transport := &http.Transport{
-		Proxy:               http.ProxyFromEnvironment,
-		MaxIdleConns:        cfg.MaxIdleConns,
-		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
-		IdleConnTimeout:     cfg.IdleConnTimeout,
+	base := cfg.Transport
+	client := &http.Client{Timeout: cfg.Timeout}
+	if cfg.HTTPClient != nil {
+		clone := *cfg.HTTPClient
+		client = &clone
+		if client.Timeout == 0 {
+			client.Timeout = cfg.Timeout
+		}
+		if base == nil {
+			base = client.Transport
+		}
 	}
-	if cfg.ProxyURL != nil {
-		transport.Proxy = http.ProxyURL(cfg.ProxyURL)
+	if base == nil {
+		base = newTransport(cfg)
 	}
+	client.Transport = chainMiddleware(base, cfg.Middleware)
 
 	logger := cfg.Logger
 	if cfg.Debug && logger == nil {
@@ -77,20 +81,50 @@ func newHTTPClient(cfg Config, auth Auth) *httpClient {
 		redactions[strings.ToLower(h)] = struct{}{}
 	}
 
+	retry := cfg.RetryPolicy
+	if retry == nil {
+		retry = newDefaultRetryPolicy(cfg)
+	}
+
 	return &httpClient{
-		cfg:  cfg,
-		auth: auth,
-		client: &http.Client{
-			Timeout:   cfg.Timeout,
-			Transport: transport,
-		},
+		cfg:       cfg,
+		auth:      auth,
+		retry:     retry,
+		client:    client,
+		transport: base,
 		logger:    logger,
 		redactMap: redactions,
 	}
 }
 
+func newTransport(cfg Config) *http.Transport {
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        cfg.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:     cfg.IdleConnTimeout,
+	}
+	if cfg.ProxyURL != nil {
+		transport.Proxy = http.ProxyURL(cfg.ProxyURL)
+	}
+	return transport
+}
+
+// chainMiddleware wraps base so that middleware[0] is the outermost layer.
+func chainMiddleware(base http.RoundTripper, middleware []Middleware) http.RoundTripper {
+	rt := base
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			rt = middleware[i](rt)
+		}
+	}
+	return rt
+}
+
 func (c *httpClient) close() {
-	if t, ok := c.client.Transport.(interface{ CloseIdleConnections() }); ok {
+	// Middleware may hide CloseIdleConnections, so close the base transport too.
+	c.client.CloseIdleConnections()
+	if t, ok := c.transport.(interface{ CloseIdleConnections() }); ok {
 		t.CloseIdleConnections()
 	}
 }
@@ -116,95 +150,295 @@ func (c *httpClient) buildURL(path string, query map[string]string) (string, err
 	return u.String(), nil
 }
 
-func (c *httpClient) doRequest(ctx context.Context, method, path string, headers http.Header, body io.Reader, query map[string]string) ([]byte, error) {
+func (c *httpClient) doRequest(ctx context.Context, method, path string, headers http.Header, body io.Reader, query map[string]string, opts ...RequestOption) ([]byte, error) {
+	var reqBody *requestBody
+	if body != nil {
+		var bodyBytes []byte
+		if b, ok := body.(*bytes.Buffer); ok {
+			bodyBytes = b.Bytes()
+		} else {
+			var err error
+			bodyBytes, err = io.ReadAll(body)
+			if err != nil {
+				return nil, fmt.Errorf("read request body: %w", err)
+			}
+		}
+		reqBody = bytesBody(bodyBytes)
+	}
+	return c.doRequestBody(ctx, method, path, headers, reqBody, query, opts...)
+}
+
+// doRequestBody is doRequest for bodies that are produced fresh on every
+// attempt (e.g. streamed multipart uploads) instead of being held in memory.
+func (c *httpClient) doRequestBody(ctx context.Context, method, path string, headers http.Header, body *requestBody, query map[string]string, opts ...RequestOption) ([]byte, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	respBody, err := c.doAttempts(ctx, method, path, headers, body, query, newRequestOptions(opts))
+	if err != nil {
+		c.logFailure(ctx, method, path, err)
+	}
+	return respBody, err
+}
 
+// doAttempts runs the retry loop for a single logical request.
+func (c *httpClient) doAttempts(ctx context.Context, method, path string, headers http.Header, body *requestBody, query map[string]string, opts requestOptions) ([]byte, error) {
 	fullURL, err := c.buildURL(path, query)
 	if err != nil {
 		return nil, err
 	}
 
-	var bodyBytes []byte
-	if body != nil {
-		if b, ok := body.(*bytes.Buffer); ok {
-			bodyBytes = b.Bytes()
-		} else {
-			bodyBytes, err = io.ReadAll(body)
-			if err != nil {
-				return nil, fmt.Errorf("read request body: %w", err)
-			}
+	if len(opts.headers) > 0 || opts.requestID != "" {
+		headers = cloneHeaders(headers)
+		if headers == nil {
+			headers = http.Header{}
+		}
+		for k, vals := range opts.headers {
+			headers[http.CanonicalHeaderKey(k)] = vals
+		}
+		if opts.requestID != "" {
+			headers.Set(c.cfg.RequestIDHeader, opts.requestID)
 		}
 	}
+	client := c.client
+	if opts.timeout > 0 {
+		clone := *c.client
+		clone.Timeout = opts.timeout
+		client = &clone
+	}
 
 	var lastErr error
-	maxAttempts := c.cfg.MaxRetries + 1
-
-	for attempt := 0; attempt < maxAttempts; attempt++ {
-		if err := ctx.Err(); err != nil {
+	var prevDelay time.Duration
+	var refreshed bool
+	var attempts []RequestAttempt
+	// fail attaches the attempt log to err. Typed API errors carry it in
+	// APIError.Attempts and are returned as they are, so type assertions on
+	// them keep working; other errors are wrapped in a *RequestError once
+	// more than one attempt was sent.
+	fail := func(err error) ([]byte, error) {
+		if apiErr, ok := err.(interface{ apiError() *APIError }); ok {
+			apiErr.apiError().Attempts = attempts
+			return nil, err
+		}
+		if len(attempts) <= 1 {
 			return nil, err
 		}
+		return nil, &RequestError{Method: method, Path: path, Attempts: attempts, Err: err}
+	}
+	maxRetries := opts.retries(c.cfg)
+	maxAttempts := maxRetries + 1
+	class := endpointClassFor(path)
 
-		var bodyReader io.Reader
-		if bodyBytes != nil {
-			bodyReader = bytes.NewReader(bodyBytes)
+	// attempt indexes every request sent; retries counts those that used up
+	// the retry budget, which excludes the replay after a credential refresh.
+	for attempt, retries := 0, 0; retries <= maxRetries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return fail(err)
+		}
+		ticket, change, err := c.cfg.CircuitBreaker.allow(c.cfg.BaseURL, class)
+		c.reportCircuit(ctx, change)
+		if err != nil {
+			return fail(err)
+		}
+		if err := c.cfg.RateLimiter.Wait(ctx, class); err != nil {
+			ticket.done(circuitIgnored)
+			return fail(err)
 		}
 
-		req, err := http.NewRequestWithContext(ctx, method, fullURL, bodyReader)
+		attemptCtx, span := c.startAttemptSpan(ctx, method, path, attempt)
+		// Resolve credentials before opening the body, so a failure here does
+		// not leave a streamed upload open with no one to read it.
+		authHeaders, sentKey, err := c.auth.headers(attemptCtx)
 		if err != nil {
-			return nil, err
+			ticket.done(circuitIgnored)
+			recordSpanError(span, err)
+			span.End()
+			return fail(err)
+		}
+		req, err := c.newRequest(attemptCtx, method, fullURL, body)
+		if err != nil {
+			ticket.done(circuitIgnored)
+			recordSpanError(span, err)
+			span.End()
+			// A one-shot body (e.g. a non-seekable io.Reader upload) cannot be
+			// sent again; surface the failure that triggered the retry or the
+			// credential refresh.
+			if (attempt > 0 || refreshed) && lastErr != nil && errors.Is(err, errBodyNotReplayable) {
+				return fail(lastErr)
+			}
+			return fail(err)
 		}
 
-		c.applyHeaders(req, headers)
+		c.applyHeaders(req, authHeaders, headers)
 		c.attachRequestID(req)
+		c.injectTraceContext(req)
+		if requestID := req.Header.Get(c.cfg.RequestIDHeader); requestID != "" {
+			span.SetAttributes(SpanAttribute{Key: AttrRequestID, Value: requestID})
+		}
 		c.runRequestHooks(req)
 		c.logRequest(req, attempt)
 
+		metric := RequestMetric{Method: method, Path: path, Endpoint: class, Attempt: attempt}
 		start := time.Now()
-		resp, err := c.client.Do(req)
+		resp, err := client.Do(req)
 		duration := time.Since(start)
+		attempts = append(attempts, RequestAttempt{RequestID: req.Header.Get(c.cfg.RequestIDHeader), Duration: duration, Err: err})
+		record := &attempts[len(attempts)-1]
 
 		if err != nil {
-			if !c.shouldRetry(nil, err, attempt) {
-				return nil, err
+			c.reportCircuit(ctx, ticket.done(circuitOutcomeFor(ctx, 0, err)))
+			recordSpanError(span, err)
+			span.End()
+			metric.Duration, metric.Err = duration, err
+			c.observeRequest(metric)
+			delay, retry := c.retryDecision(ctx, retries, RetryAttempt{
+				Method:        method,
+				Path:          path,
+				Attempt:       attempt,
+				MaxRetries:    maxRetries,
+				Err:           err,
+				PreviousDelay: prevDelay,
+			})
+			if !retry {
+				return fail(err)
 			}
 			lastErr = err
-			c.logf("retrying after error (attempt %d/%d): %v", attempt+1, maxAttempts, err)
-			if err := c.sleepWithContext(ctx, c.backoffDuration(attempt)); err != nil {
-				return nil, err
+			prevDelay = delay
+			retries++
+			record.Delay = delay
+			c.observeRetry(RetryMetric{Method: method, Path: path, Endpoint: class, Attempt: attempt, Delay: delay})
+			c.logRetry(ctx, method, path, attempt, maxAttempts, 0, err, delay)
+			if err := c.sleepWithContext(ctx, delay); err != nil {
+				return fail(err)
 			}
 			continue
 		}
 
+		span.SetAttributes(SpanAttribute{Key: AttrHTTPStatusCode, Value: resp.StatusCode})
 		respBody, readErr := io.ReadAll(resp.Body)
 		resp.Body.Close()
+		metric.StatusCode, metric.Duration = resp.StatusCode, time.Since(start)
+		record.StatusCode, record.Duration = resp.StatusCode, metric.Duration
+		if requestID := resp.Header.Get(c.cfg.RequestIDHeader); requestID != "" {
+			record.RequestID = requestID
+		}
 		if readErr != nil {
-			return nil, fmt.Errorf("read response: %w", readErr)
+			record.Err = readErr
+			c.reportCircuit(ctx, ticket.done(circuitOutcomeFor(ctx, 0, readErr)))
+			recordSpanError(span, readErr)
+			span.End()
+			metric.Err = readErr
+			c.observeRequest(metric)
+			return fail(fmt.Errorf("read response: %w", readErr))
 		}
+		c.reportCircuit(ctx, ticket.done(circuitOutcomeFor(ctx, resp.StatusCode, nil)))
 
 		c.logResponse(req, resp, respBody, duration)
 		c.runResponseHooks(resp, respBody)
 
 		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			span.End()
+			c.observeRequest(metric)
 			return respBody, nil
 		}
 
+		if retryAfter := parseRetryAfter(resp.Header); resp.StatusCode == http.StatusTooManyRequests || retryAfter != nil {
+			var backoff time.Duration
+			if retryAfter != nil {
+				backoff = *retryAfter
+			}
+			c.cfg.RateLimiter.observeBackoff(class, backoff)
+		}
+
 		apiErr := apiErrorFromResponse(resp.StatusCode, respBody, resp.Header, c.cfg.RequestIDHeader)
 		lastErr = apiErr
+		record.Err = apiErr
+		recordSpanError(span, apiErr)
+		span.End()
+		metric.Err = apiErr
+		c.observeRequest(metric)
+
+		// A rejected key may have been rotated: refresh once and replay with
+		// the new key. The replay does not use up the retry budget.
+		var authErr *AuthenticationError
+		if !refreshed && errors.As(apiErr, &authErr) && c.auth.refresh(ctx, sentKey) {
+			refreshed = true
+			maxAttempts++
+			c.logf("retrying with refreshed credentials after status %d", resp.StatusCode)
+			c.slog(ctx, slog.LevelWarn, "roe retrying with refreshed credentials",
+				slog.String("method", method),
+				slog.String("path", path),
+				slog.Int("status", resp.StatusCode),
+			)
+			continue
+		}
 
-		if c.shouldRetry(resp, nil, attempt) {
-			c.logf("retrying after status %d (attempt %d/%d)", resp.StatusCode, attempt+1, maxAttempts)
-			if err := c.sleepWithContext(ctx, c.retryDelay(resp, attempt)); err != nil {
-				return nil, err
+		delay, retry := c.retryDecision(ctx, retries, RetryAttempt{
+			Method:        method,
+			Path:          path,
+			Attempt:       attempt,
+			MaxRetries:    maxRetries,
+			Response:      resp,
+			Body:          respBody,
+			Err:           apiErr,
+			PreviousDelay: prevDelay,
+		})
+		if retry {
+			prevDelay = delay
+			retries++
+			record.Delay = delay
+			c.observeRetry(RetryMetric{Method: method, Path: path, Endpoint: class, Attempt: attempt, StatusCode: resp.StatusCode, Delay: delay})
+			c.logRetry(ctx, method, path, attempt, maxAttempts, resp.StatusCode, apiErr, delay)
+			if err := c.sleepWithContext(ctx, delay); err != nil {
+				return fail(err)
 			}
 			continue
 		}
 
-		return nil, apiErr
+		return fail(apiErr)
 	}
 
-	return nil, lastErr
+	return fail(lastErr)
+}
+
+// retryDecision consults the retry policy while the call's retry budget
+// lasts; retries is the number of retries already made.
+func (c *httpClient) retryDecision(ctx context.Context, retries int, attempt RetryAttempt) (time.Duration, bool) {
+	if retries >= attempt.MaxRetries {
+		return 0, false
+	}
+	return c.retry.Retry(ctx, attempt)
+}
+
+// newRequest builds a single attempt. The body is opened fresh and also
+// exposed through Request.GetBody so the transport can replay it on redirects.
+func (c *httpClient) newRequest(ctx context.Context, method, fullURL string, body *requestBody) (*http.Request, error) {
+	if body == nil {
+		return http.NewRequestWithContext(ctx, method, fullURL, nil)
+	}
+	rc, err := body.open()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, rc)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	req.GetBody = body.open
+	switch {
+	case body.length == 0:
+		rc.Close()
+		req.Body = http.NoBody
+		req.GetBody = func() (io.ReadCloser, error) { return http.NoBody, nil }
+		req.ContentLength = 0
+	case body.length > 0:
+		req.ContentLength = body.length
+	default:
+		// Unknown length: Body is non-nil, so the transport streams it chunked.
+		req.ContentLength = -1
+	}
+	return req, nil
 }
 
 func (c *httpClient) logf(format string, args ...any) {
@@ -215,6 +449,14 @@ func (c *httpClient) logf(format string, args ...any) {
 }
 
 func (c *httpClient) logRequest(req *http.Request, attempt int) {
+	if c.slogEnabled(req.Context(), slog.LevelDebug) {
+		c.slog(req.Context(), slog.LevelDebug, "roe request",
+			slog.String("method", req.Method),
+			slog.String("url", req.URL.String()),
+			slog.Int("attempt", attempt+1),
+			c.headerAttr(req.Header),
+		)
+	}
 	if c.logger == nil || !c.cfg.Debug {
 		return
 	}
@@ -222,7 +464,9 @@ func (c *httpClient) logRequest(req *http.Request, attempt int) {
 }
 
 func (c *httpClient) logResponse(req *http.Request, resp *http.Response, body []byte, duration time.Duration) {
-	if c.logger == nil || !c.cfg.Debug {
+	legacy := c.logger != nil && c.cfg.Debug
+	structured := c.slogEnabled(req.Context(), slog.LevelDebug)
+	if !legacy && !structured {
 		return
 	}
 	requestID := resp.Header.Get(c.cfg.RequestIDHeader)
@@ -230,9 +474,63 @@ func (c *httpClient) logResponse(req *http.Request, resp *http.Response, body []
 	if len(bodyPreview) > 512 {
 		bodyPreview = bodyPreview[:512] + "…"
 	}
+	if structured {
+		c.slog(req.Context(), slog.LevelDebug, "roe response",
+			slog.String("method", req.Method),
+			slog.String("url", req.URL.String()),
+			slog.Int("status", resp.StatusCode),
+			slog.Duration("duration", duration),
+			slog.String("request_id", requestID),
+			c.headerAttr(resp.Header),
+			slog.String("body", bodyPreview),
+		)
+	}
+	if !legacy {
+		return
+	}
 	c.logger.Printf("[response] %s %s status=%d duration=%s request_id=%s body=%s", req.Method, req.URL.String(), resp.StatusCode, duration, requestID, bodyPreview)
 }
 
+func (c *httpClient) logRetry(ctx context.Context, method, path string, attempt, maxAttempts, status int, err error, delay time.Duration) {
+	if status == 0 {
+		c.logf("retrying after error (attempt %d/%d): %v", attempt+1, maxAttempts, err)
+	} else {
+		c.logf("retrying after status %d (attempt %d/%d)", status, attempt+1, maxAttempts)
+	}
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("path", path),
+		slog.Int("attempt", attempt+1),
+		slog.Int("max_attempts", maxAttempts),
+		slog.Duration("delay", delay),
+		slog.Any("error", err),
+	}
+	if status != 0 {
+		attrs = append(attrs, slog.Int("status", status))
+	}
+	c.slog(ctx, slog.LevelWarn, "roe retrying request", attrs...)
+}
+
+// logFailure reports a request that failed after all attempts.
+func (c *httpClient) logFailure(ctx context.Context, method, path string, err error) {
+	if !c.slogEnabled(ctx, slog.LevelError) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("path", path),
+		slog.Any("error", err),
+	}
+	var apiErr interface{ apiError() *APIError }
+	if errors.As(err, &apiErr) {
+		attrs = append(attrs, slog.Int("status", apiErr.apiError().StatusCode))
+		if requestID := apiErr.apiError().RequestID; requestID != "" {
+			attrs = append(attrs, slog.String("request_id", requestID))
+		}
+	}
+	c.slog(ctx, slog.LevelError, "roe request failed", attrs...)
+}
+
 func (c *httpClient) redactedHeaders(h http.Header) http.Header {
 	if len(c.redactMap) == 0 {
 		return h
@@ -246,8 +544,10 @@ func (c *httpClient) redactedHeaders(h http.Header) http.Header {
 	return cloned
 }
 
-func (c *httpClient) applyHeaders(req *http.Request, headers http.Header) {
-	for k, vals := range c.auth.Headers() {
+// applyHeaders sets the resolved auth headers, then client-wide and
+// per-request headers, on req.
+func (c *httpClient) applyHeaders(req *http.Request, authHeaders, headers http.Header) {
+	for k, vals := range authHeaders {
 		for _, v := range vals {
 			req.Header.Add(k, v)
 		}
@@ -285,11 +585,16 @@ func (c *httpClient) attachRequestID(req *http.Request) {
 }
 
 func (c *httpClient) generateRequestID() string {
+	return randomToken("roe-")
+}
+
+// randomToken returns prefix followed by 24 random hex characters.
+func randomToken(prefix string) string {
 	buf := make([]byte, 12)
 	if _, err := crand.Read(buf); err == nil {
-		return "roe-" + hex.EncodeToString(buf)
+		return prefix + hex.EncodeToString(buf)
 	}
-	return fmt.Sprintf("roe-%d", time.Now().UnixNano())
+	return fmt.Sprintf("%s%d", prefix, time.Now().UnixNano())
 }
 
 func (c *httpClient) runRequestHooks(req *http.Request) {
@@ -298,6 +603,7 @@ func (c *httpClient) runRequestHooks(req *http.Request) {
 			defer func() {
 				if r := recover(); r != nil {
 					c.logf("request hook[%d] panic: %v", i, r)
+					c.slog(req.Context(), slog.LevelError, "roe request hook panicked", slog.Int("hook", i), slog.Any("panic", r))
 				}
 			}()
 			hook(req)
@@ -311,6 +617,11 @@ func (c *httpClient) runResponseHooks(resp *http.Response, body []byte) {
 			defer func() {
 				if r := recover(); r != nil {
 					c.logf("response hook[%d] panic: %v", i, r)
+					ctx := context.Background()
+					if resp.Request != nil {
+						ctx = resp.Request.Context()
+					}
+					c.slog(ctx, slog.LevelError, "roe response hook panicked", slog.Int("hook", i), slog.Any("panic", r))
 				}
 			}()
 			hook(resp, body)
@@ -318,73 +629,6 @@ func (c *httpClient) runResponseHooks(resp *http.Response, body []byte) {
 	}
 }
 
-func (c *httpClient) shouldRetry(resp *http.Response, err error, attempt int) bool {
-	if attempt >= c.cfg.MaxRetries {
-		return false
-	}
-	if err != nil {
-		// Don't retry on context cancellation/timeout
-		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-			return false
-		}
-		// Only retry on temporary network errors
-		var netErr net.Error
-		if errors.As(err, &netErr) && netErr.Timeout() {
-			return true
-		}
-		// Retry on connection errors (DNS, connection refused, etc.)
-		var opErr *net.OpError
-		if errors.As(err, &opErr) {
-			return true
-		}
-		// Don't retry other errors (permanent failures)
-		return false
-	}
-	if resp == nil {
-		return false
-	}
-	if resp.StatusCode >= 500 {
-		return true
-	}
-	switch resp.StatusCode {
-	case http.StatusRequestTimeout, http.StatusTooManyRequests:
-		return true
-	default:
-		return false
-	}
-}
-
-func (c *httpClient) backoffDuration(attempt int) time.Duration {
-	factor := math.Pow(c.cfg.RetryMultiplier, float64(attempt))
-	delay := time.Duration(float64(c.cfg.RetryInitialInterval) * factor)
-	if delay > c.cfg.RetryMaxInterval {
-		delay = c.cfg.RetryMaxInterval
-	}
-	if c.cfg.RetryJitter > 0 {
-		jitterFactor := 1 + (mrand.Float64()*2-1)*c.cfg.RetryJitter
-		delay = time.Duration(float64(delay) * jitterFactor)
-	}
-	if delay < time.Millisecond {
-		return time.Millisecond
-	}
-	return delay
-}
-
-func (c *httpClient) retryDelay(resp *http.Response, attempt int) time.Duration {
-	delay := c.backoffDuration(attempt)
-	if resp == nil {
-		return delay
-	}
-	retryAfter := parseRetryAfter(resp.Header)
-	if retryAfter == nil || *retryAfter <= 0 {
-		return delay
-	}
-	if *retryAfter > delay {
-		return *retryAfter
-	}
-	return delay
-}
-
 func (c *httpClient) sleepWithContext(ctx context.Context, delay time.Duration) error {
 	if delay <= 0 {
 		return nil
@@ -403,8 +647,8 @@ func (c *httpClient) get(path string, query map[string]string, out any) error {
 	return c.getWithContext(context.Background(), path, query, out)
 }
 
-func (c *httpClient) getWithContext(ctx context.Context, path string, query map[string]string, out any) error {
-	data, err := c.doRequest(ctx, http.MethodGet, path, http.Header{}, nil, query)
+func (c *httpClient) getWithContext(ctx context.Context, path string, query map[string]string, out any, opts ...RequestOption) error {
+	data, err := c.doRequest(ctx, http.MethodGet, path, http.Header{}, nil, query, opts...)
 	if err != nil {
 		return err
 	}
@@ -414,22 +658,22 @@ func (c *httpClient) getWithContext(ctx context.Context, path string, query map[
 	return json.Unmarshal(data, out)
 }
 
-func (c *httpClient) getBytesWithContext(ctx context.Context, path string, query map[string]string) ([]byte, error) {
-	return c.doRequest(ctx, http.MethodGet, path, http.Header{}, nil, query)
+func (c *httpClient) getBytesWithContext(ctx context.Context, path string, query map[string]string, opts ...RequestOption) ([]byte, error) {
+	return c.doRequest(ctx, http.MethodGet, path, http.Header{}, nil, query, opts...)
 }
 
-func (c *httpClient) deleteWithContext(ctx context.Context, path string, query map[string]string) error {
-	_, err := c.doRequest(ctx, http.MethodDelete, path, http.Header{}, nil, query)
+func (c *httpClient) deleteWithContext(ctx context.Context, path string, query map[string]string, opts ...RequestOption) error {
+	_, err := c.doRequest(ctx, http.MethodDelete, path, http.Header{}, nil, query, opts...)
 	return err
 }
 
-func (c *httpClient) postJSONWithContext(ctx context.Context, path string, payload any, query map[string]string, out any) error {
-	return c.postJSONHeadersWithContext(ctx, path, payload, query, out, nil)
+func (c *httpClient) postJSONWithContext(ctx context.Context, path string, payload any, query map[string]string, out any, opts ...RequestOption) error {
+	return c.postJSONHeadersWithContext(ctx, path, payload, query, out, nil, opts...)
 }
 
 // postJSONHeadersWithContext is postJSONWithContext plus per-request extra
 // headers (e.g. X-Skip-Cache on agent run requests).
-func (c *httpClient) postJSONHeadersWithContext(ctx context.Context, path string, payload any, query map[string]string, out any, extraHeaders http.Header) error {
+func (c *httpClient) postJSONHeadersWithContext(ctx context.Context, path string, payload any, query map[string]string, out any, extraHeaders http.Header, opts ...RequestOption) error {
 	buf := &bytes.Buffer{}
 	if payload != nil {
 		if err := json.NewEncoder(buf).Encode(payload); err != nil {
@@ -440,7 +684,7 @@ func (c *httpClient) postJSONHeadersWithContext(ctx context.Context, path string
 	headers.Set("Content-Type", "application/json")
 	mergeHeaderValues(headers, extraHeaders)
 
-	data, err := c.doRequest(ctx, http.MethodPost, path, headers, buf, query)
+	data, err := c.doRequest(ctx, http.MethodPost, path, headers, buf, query, opts...)
 	if err != nil {
 		return err
 	}
@@ -459,7 +703,7 @@ func mergeHeaderValues(dst, src http.Header) {
 	}
 }
 
-func (c *httpClient) putJSONWithContext(ctx context.Context, path string, payload any, query map[string]string, out any) error {
+func (c *httpClient) putJSONWithContext(ctx context.Context, path string, payload any, query map[string]string, out any, opts ...RequestOption) error {
 	buf := &bytes.Buffer{}
 	if payload != nil {
 		if err := json.NewEncoder(buf).Encode(payload); err != nil {
@@ -469,7 +713,7 @@ func (c *httpClient) putJSONWithContext(ctx context.Context, path string, payloa
 	headers := http.Header{}
 	headers.Set("Content-Type", "application/json")
 
-	data, err := c.doRequest(ctx, http.MethodPut, path, headers, buf, query)
+	data, err := c.doRequest(ctx, http.MethodPut, path, headers, buf, query, opts...)
 	if err != nil {
 		return err
 	}
@@ -479,7 +723,7 @@ func (c *httpClient) putJSONWithContext(ctx context.Context, path string, payloa
 	return json.Unmarshal(data, out)
 }
 
-func (c *httpClient) patchJSONWithContext(ctx context.Context, path string, payload any, query map[string]string, out any) error {
+func (c *httpClient) patchJSONWithContext(ctx context.Context, path string, payload any, query map[string]string, out any, opts ...RequestOption) error {
 	buf := &bytes.Buffer{}
 	if payload != nil {
 		if err := json.NewEncoder(buf).Encode(payload); err != nil {
@@ -489,7 +733,7 @@ func (c *httpClient) patchJSONWithContext(ctx context.Context, path string, payl
 	headers := http.Header{}
 	headers.Set("Content-Type", "application/json")
 
-	data, err := c.doRequest(ctx, http.MethodPatch, path, headers, buf, query)
+	data, err := c.doRequest(ctx, http.MethodPatch, path, headers, buf, query, opts...)
 	if err != nil {
 		return err
 	}
@@ -508,13 +752,13 @@ func (c *httpClient) postDynamicInputs(path string, inputs map[string]any, query
 	return c.postDynamicInputsWithContext(context.Background(), path, inputs, query, out, metadata)
 }
 
-func (c *httpClient) postDynamicInputsWithContext(ctx context.Context, path string, inputs map[string]any, query map[string]string, out any, metadata map[string]any) error {
-	return c.postDynamicInputsHeadersWithContext(ctx, path, inputs, query, out, metadata, nil)
+func (c *httpClient) postDynamicInputsWithContext(ctx context.Context, path string, inputs map[string]any, query map[string]string, out any, metadata map[string]any, opts ...RequestOption) error {
+	return c.postDynamicInputsHeadersWithContext(ctx, path, inputs, query, out, metadata, nil, opts...)
 }
 
 // postDynamicInputsHeadersWithContext is postDynamicInputsWithContext plus
 // per-request extra headers (e.g. X-Skip-Cache on agent run requests).
-func (c *httpClient) postDynamicInputsHeadersWithContext(ctx context.Context, path string, inputs map[string]any, query map[string]string, out any, metadata map[string]any, extraHeaders http.Header) error {
+func (c *httpClient) postDynamicInputsHeadersWithContext(ctx context.Context, path string, inputs map[string]any, query map[string]string, out any, metadata map[string]any, extraHeaders http.Header, opts ...RequestOption) error {
 	if metadata != nil {
 		if _, exists := inputs["metadata"]; exists {
 			return fmt.Errorf("inputs must not contain key \"metadata\" when metadata parameter is set")
@@ -579,7 +823,7 @@ func (c *httpClient) postDynamicInputsHeadersWithContext(ctx context.Context, pa
 		headers := http.Header{}
 		headers.Set("Content-Type", "application/x-www-form-urlencoded")
 		mergeHeaderValues(headers, extraHeaders)
-		data, err := c.doRequest(ctx, http.MethodPost, path, headers, strings.NewReader(form.Encode()), query)
+		data, err := c.doRequest(ctx, http.MethodPost, path, headers, strings.NewReader(form.Encode()), query, opts...)
 		if err != nil {
 			return err
 		}
@@ -589,63 +833,22 @@ func (c *httpClient) postDynamicInputsHeadersWithContext(ctx context.Context, pa
 		return json.Unmarshal(data, out)
 	}
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	for key, values := range form {
-		for _, v := range values {
-			_ = writer.WriteField(key, v)
-		}
-	}
-
-	// Track opened readers for cleanup on error
-	var openedReaders []io.ReadCloser
-	closeAllReaders := func() {
-		for _, r := range openedReaders {
-			r.Close()
-		}
-	}
-
+	parts := make([]*multipartFile, 0, len(files))
 	for _, f := range files {
-		fileReader, filename, mimeType, err := c.prepareMultipartFile(f.File)
+		part, err := c.prepareMultipartFile(f)
 		if err != nil {
-			closeAllReaders()
-			writer.Close()
+			newMultipartBody(nil, parts).close()
 			return err
 		}
-		openedReaders = append(openedReaders, fileReader)
-
-		h := make(textproto.MIMEHeader)
-		// Use mime.FormatMediaType to properly escape filename (handles quotes, newlines, etc.)
-		contentDisp := mime.FormatMediaType("form-data", map[string]string{
-			"name":     f.FieldName,
-			"filename": filename,
-		})
-		h.Set("Content-Disposition", contentDisp)
-		h.Set("Content-Type", mimeType)
-
-		part, err := writer.CreatePart(h)
-		if err != nil {
-			closeAllReaders()
-			writer.Close()
-			return err
-		}
-		if _, err := io.Copy(part, fileReader); err != nil {
-			closeAllReaders()
-			writer.Close()
-			return err
-		}
-		fileReader.Close()
-	}
-
-	if err := writer.Close(); err != nil {
-		return err
+		parts = append(parts, part)
 	}
+	body := newMultipartBody(form, parts)
+	defer body.close()
 
 	headers := http.Header{}
-	headers.Set("Content-Type", writer.FormDataContentType())
+	headers.Set("Content-Type", body.contentType)
 	mergeHeaderValues(headers, extraHeaders)
-	data, err := c.doRequest(ctx, http.MethodPost, path, headers, body, query)
+	data, err := c.doRequestBody(ctx, http.MethodPost, path, headers, body.requestBody(), query, opts...)
 	if err != nil {
 		return err
 	}
@@ -654,49 +857,3 @@ func (c *httpClient) postDynamicInputsHeadersWithContext(ctx context.Context, pa
 	}
 	return json.Unmarshal(data, out)
 }
-
-func (c *httpClient) prepareMultipartFile(file FileUpload) (io.ReadCloser, string, string, error) {
-	rc, err := file.open()
-	if err != nil {
-		return nil, "", "", err
-	}
-
-	filename := file.filename()
-	mimeType := file.mimeType()
-
-	rcWithMime, detected, err := detectMimeType(rc, filename, mimeType)
-	if err != nil {
-		return nil, "", "", err
-	}
-	if detected != "" {
-		mimeType = detected
-	}
-
-	return rcWithMime, filename, mimeType, nil
-}
-
-func detectMimeType(rc io.ReadCloser, filename, fallback string) (io.ReadCloser, string, error) {
-	if seeker, ok := rc.(io.ReadSeeker); ok {
-		buf := make([]byte, 512)
-		n, _ := seeker.Read(buf)
-		_, _ = seeker.Seek(0, io.SeekStart)
-		if n > 0 {
-			detected := http.DetectContentType(buf[:n])
-			return rc, detected, nil
-		}
-		return rc, fallback, nil
-	}
-
-	buf, err := io.ReadAll(io.LimitReader(rc, 1024))
-	if err != nil {
-		rc.Close()
-		return nil, "", err
-	}
-
-	detected := http.DetectContentType(buf)
-	combined := io.NopCloser(io.MultiReader(bytes.NewReader(buf), rc))
-	if detected != "" {
-		return combined, detected, nil
-	}
-	return combined, fallback, nil
-}
@@ -3,7 +3,10 @@ package roe
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 )
@@ -95,6 +98,73 @@ func TestAPIErrorIncludesRequestID(t *testing.T) {
 	}
 }
 
+func TestFailedRequestRecordsAttempts(t *testing.T) {
+	calls := 0
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-Request-ID", fmt.Sprintf("resp-%d", calls))
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"detail":"Agent not found."}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithConfig(Config{
+		APIKey:               "k",
+		OrganizationID:       "org",
+		BaseURL:              server.URL,
+		Timeout:              time.Second,
+		MaxRetries:           3,
+		RetryInitialInterval: 5 * time.Millisecond,
+		RetryMaxInterval:     5 * time.Millisecond,
+		RetryMultiplier:      1,
+		RequestIDHeader:      "X-Request-ID",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.Agents.Run("agent-1", 0, map[string]any{"text": "hi"}, nil)
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected *NotFoundError, got %T: %v", err, err)
+	}
+	if len(notFound.Attempts) != 3 {
+		t.Fatalf("expected 3 attempts, got %+v", notFound.Attempts)
+	}
+	for i, wantStatus := range []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusNotFound} {
+		attempt := notFound.Attempts[i]
+		if attempt.StatusCode != wantStatus || attempt.RequestID != fmt.Sprintf("resp-%d", i+1) || attempt.Err == nil {
+			t.Fatalf("attempt %d: unexpected %+v", i, attempt)
+		}
+	}
+	if notFound.Attempts[0].Delay != 5*time.Millisecond || notFound.Attempts[2].Delay != 0 {
+		t.Fatalf("expected 5ms of backoff after each retried attempt, got %+v", notFound.Attempts)
+	}
+	if !IsNotFound(err) || IsTransient(err) || IsRetryable(err) {
+		t.Fatalf("expected a permanent not-found error through the run wrapping, got %v", err)
+	}
+
+	// Transport failures have no typed API error to carry the log, so after
+	// retries they are wrapped in a *RequestError.
+	server.Close()
+	_, err = client.Agents.Retrieve("agent-1")
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("expected *RequestError, got %T: %v", err, err)
+	}
+	if len(reqErr.Attempts) != 4 || reqErr.Backoff() != 15*time.Millisecond {
+		t.Fatalf("expected 4 attempts and 15ms of backoff, got %+v", reqErr.Attempts)
+	}
+	if !strings.Contains(err.Error(), "after 4 attempts") || !IsTransient(err) {
+		t.Fatalf("expected a transient error with the attempt count, got %v", err)
+	}
+}
+
 func TestHTTPClientRetrySleepHonorsContextCancellation(t *testing.T) {
 	firstResponse := make(chan struct{}, 1)
 	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -139,3 +209,126 @@ func TestHTTPClientRetrySleepHonorsContextCancellation(t *testing.T) {
 		t.Fatalf("expected cancellation to short-circuit retry sleep, took %s", elapsed)
 	}
 }
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestHTTPClientMiddlewareWrapsEveryAttempt(t *testing.T) {
+	attempts := 0
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if got := r.Header.Get("X-Trace"); got != "outer,inner" {
+			t.Errorf("expected middleware order outer,inner, got %q", got)
+		}
+		if r.Header.Get("Authorization") != "Bearer k" {
+			t.Errorf("expected auth header to reach the transport")
+		}
+		if attempts < 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	var seen []string
+	tag := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				seen = append(seen, name)
+				trace := req.Header.Get("X-Trace")
+				if trace != "" {
+					trace += ","
+				}
+				req.Header.Set("X-Trace", trace+name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	cfg := Config{
+		APIKey:               "k",
+		OrganizationID:       "org",
+		BaseURL:              server.URL,
+		Timeout:              time.Second,
+		MaxRetries:           1,
+		RetryInitialInterval: 5 * time.Millisecond,
+		RetryMaxInterval:     5 * time.Millisecond,
+		RetryMultiplier:      1,
+		Middleware:           []Middleware{tag("outer"), tag("inner")},
+	}
+
+	client := newHTTPClient(cfg, newAuth(cfg))
+	defer client.close()
+
+	if err := client.get("/ok", nil, nil); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+	want := []string{"outer", "inner", "outer", "inner"}
+	if strings.Join(seen, " ") != strings.Join(want, " ") {
+		t.Fatalf("expected middleware calls %v, got %v", want, seen)
+	}
+}
+
+func TestHTTPClientUsesConfiguredTransportAndClient(t *testing.T) {
+	var transportCalls int
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		transportCalls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"ok":true}`)),
+			Request:    req,
+		}, nil
+	})
+
+	base := &http.Client{Transport: transport}
+	cfg := Config{
+		APIKey:         "k",
+		OrganizationID: "org",
+		BaseURL:        "https://roe.invalid",
+		Timeout:        time.Second,
+		HTTPClient:     base,
+	}
+
+	client := newHTTPClient(cfg, newAuth(cfg))
+	defer client.close()
+
+	var out map[string]bool
+	if err := client.get("/ok", nil, &out); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if transportCalls != 1 || !out["ok"] {
+		t.Fatalf("expected configured transport to serve the request, calls=%d out=%v", transportCalls, out)
+	}
+	if client.client == base {
+		t.Fatalf("expected the caller's http.Client to be copied, not reused")
+	}
+	if base.Timeout != 0 {
+		t.Fatalf("expected the caller's http.Client to be left untouched, got timeout %s", base.Timeout)
+	}
+	if client.client.Timeout != time.Second {
+		t.Fatalf("expected Config.Timeout on the copied client, got %s", client.client.Timeout)
+	}
+
+	override := 0
+	cfg.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		override++
+		return transport(req)
+	})
+	overridden := newHTTPClient(cfg, newAuth(cfg))
+	defer overridden.close()
+	if err := overridden.get("/ok", nil, nil); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if override != 1 {
+		t.Fatalf("expected Config.Transport to take precedence over HTTPClient.Transport")
+	}
+}
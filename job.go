@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
+	"math"
 	"time"
 )
 
@@ -13,6 +15,7 @@ type Job struct {
 	agentsAPI *AgentsAPI
 	jobID     string
 	timeout   time.Duration
+	schedule  PollSchedule
 }
 
 func newJob(api *AgentsAPI, jobID string, timeoutSeconds int) *Job {
@@ -31,16 +34,37 @@ func (j *Job) Timeout() time.Duration {
 	return j.timeout
 }
 
-// Wait polls for completion and returns result.
+// WithPollSchedule returns a copy of the job that polls on schedule when
+// Wait or Watch is called with a zero interval, overriding
+// Config.PollSchedule.
+func (j *Job) WithPollSchedule(schedule PollSchedule) *Job {
+	clone := *j
+	clone.schedule = schedule
+	return &clone
+}
+
+// Wait polls for completion and returns result. A positive interval polls at
+// that fixed rate; zero uses the job's PollSchedule (DefaultPollSchedule
+// unless configured). With Config.SharedJobPolling the client's shared poller
+// polls the job instead.
 func (j *Job) Wait(interval time.Duration, timeout time.Duration) (AgentJobResult, error) {
 	return j.WaitContext(context.Background(), interval, timeout)
 }
 
 // WaitContext polls for completion with a caller-supplied context.
-func (j *Job) WaitContext(ctx context.Context, interval time.Duration, timeout time.Duration) (AgentJobResult, error) {
-	if interval <= 0 {
-		interval = 2 * time.Second
+func (j *Job) WaitContext(ctx context.Context, interval time.Duration, timeout time.Duration) (_ AgentJobResult, err error) {
+	ctx, span := j.httpClient().startSpan(ctx, "Job.Wait", SpanAttribute{Key: AttrJobID, Value: j.jobID})
+	defer endSpan(span, &err)
+	start := time.Now()
+	result, err := j.wait(ctx, interval, timeout)
+	j.httpClient().observeJobWait(JobWaitMetric{Jobs: 1, Duration: time.Since(start), Err: err})
+	if err == nil && result.Status != nil {
+		span.SetAttributes(SpanAttribute{Key: AttrJobStatus, Value: result.Status.String()})
 	}
+	return result, err
+}
+
+func (j *Job) wait(ctx context.Context, interval time.Duration, timeout time.Duration) (AgentJobResult, error) {
 	if timeout <= 0 {
 		timeout = j.timeout
 	}
@@ -52,42 +76,141 @@ func (j *Job) WaitContext(ctx context.Context, interval time.Duration, timeout t
 		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
+	if j.agentsAPI != nil && j.agentsAPI.poller != nil {
+		return j.agentsAPI.poller.wait(ctx, j.jobID)
+	}
+
+	status, err := j.poll(ctx, "wait", interval, nil)
+	if err != nil {
+		return AgentJobResult{}, err
+	}
+	result, err := j.RetrieveResultWithContext(ctx)
+	if err != nil {
+		// For failed/cancelled jobs, treat a missing result as non-fatal
+		if status.Status == JobFailure || status.Status == JobCancelled {
+			result = AgentJobResult{}
+		} else {
+			return AgentJobResult{}, err
+		}
+	}
+	result.Status = &status.Status
+	result.ErrorMessage = status.ErrorMessage
+	return result, nil
+}
+
+// errStopPolling is returned by poll when its observer asks it to stop.
+var errStopPolling = errors.New("polling stopped")
 
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+// poll fetches the job status on the resolved PollSchedule until it is
+// terminal and returns the terminal status. Rate-limited polls are retried
+// after their Retry-After. Each fetched status is passed to observe, if
+// set; poll stops with errStopPolling when observe returns false. op names
+// the operation in cancellation errors.
+func (j *Job) poll(ctx context.Context, op string, interval time.Duration, observe func(AgentJobStatus) bool) (AgentJobStatus, error) {
+	schedule := resolvePollSchedule(interval, j.schedule, j.agentsAPI)
+	start := time.Now()
 
-	for {
+	for poll := 0; ; poll++ {
 		select {
 		case <-ctx.Done():
-			return AgentJobResult{}, fmt.Errorf("job %s wait cancelled: %w", j.jobID, ctx.Err())
+			return AgentJobStatus{}, fmt.Errorf("job %s %s cancelled: %w", j.jobID, op, ctx.Err())
 		default:
 		}
 
 		status, err := j.RetrieveStatusWithContext(ctx)
-		if err != nil {
-			return AgentJobResult{}, err
+		rateLimit, limited := pollRateLimited(err)
+		if err != nil && !limited {
+			return AgentJobStatus{}, err
 		}
-		if status.Status.IsTerminal() {
-			result, err := j.RetrieveResultWithContext(ctx)
-			if err != nil {
-				// For failed/cancelled jobs, treat a missing result as non-fatal
-				if status.Status == JobFailure || status.Status == JobCancelled {
-					result = AgentJobResult{}
-				} else {
-					return AgentJobResult{}, err
-				}
+		if err == nil {
+			if observe != nil && !observe(status) {
+				return status, errStopPolling
+			}
+			if status.Status.IsTerminal() {
+				return status, nil
 			}
-			result.Status = &status.Status
-			result.ErrorMessage = status.ErrorMessage
-			return result, nil
 		}
 
-		select {
-		case <-ctx.Done():
-			return AgentJobResult{}, fmt.Errorf("job %s wait cancelled: %w", j.jobID, ctx.Err())
-		case <-ticker.C:
+		if err := pausePolling(ctx, schedule, poll, start, rateLimit); err != nil {
+			return AgentJobStatus{}, fmt.Errorf("job %s %s cancelled: %w", j.jobID, op, err)
+		}
+	}
+}
+
+// JobEvent is a status transition reported by Job.Watch.
+type JobEvent struct {
+	JobID  string
+	Status JobStatus
+	// Previous is the status before this transition. On the first event it
+	// equals Status.
+	Previous JobStatus
+	// Time is the server's timestamp for the status, or when the status was
+	// observed if the server sent none.
+	Time         time.Time
+	ErrorMessage *string
+}
+
+// Watch polls the job every interval (on the job's PollSchedule when zero)
+// and yields each status change, starting with the current status and ending
+// after a terminal one. Polls that return an unchanged status are skipped. A
+// polling error or cancelled ctx is yielded as the final error; breaking out
+// of the loop stops polling. Unlike Wait, Watch is bounded only by ctx.
+//
+//	for event, err := range job.Watch(ctx, time.Second) {
+//		if err != nil {
+//			return err
+//		}
+//		log.Printf("%s -> %s at %s", event.Previous, event.Status, event.Time)
+//	}
+func (j *Job) Watch(ctx context.Context, interval time.Duration) iter.Seq2[JobEvent, error] {
+	return func(yield func(JobEvent, error) bool) {
+		if ctx == nil {
+			ctx = context.Background()
 		}
+		ctx, span := j.httpClient().startSpan(ctx, "Job.Watch", SpanAttribute{Key: AttrJobID, Value: j.jobID})
+		defer span.End()
+
+		var last JobStatus
+		seen := false
+		_, err := j.poll(ctx, "watch", interval, func(status AgentJobStatus) bool {
+			if seen && status.Status == last {
+				return true
+			}
+			event := JobEvent{
+				JobID:        j.jobID,
+				Status:       status.Status,
+				Previous:     status.Status,
+				Time:         statusTime(status.Timestamp),
+				ErrorMessage: status.ErrorMessage,
+			}
+			if seen {
+				event.Previous = last
+			}
+			last, seen = status.Status, true
+			return yield(event, nil)
+		})
+		if err != nil && !errors.Is(err, errStopPolling) {
+			recordSpanError(span, err)
+			yield(JobEvent{}, err)
+		}
+	}
+}
+
+// statusTime converts a status timestamp in Unix seconds, falling back to
+// now when it is unset.
+func statusTime(timestamp float64) time.Time {
+	if timestamp <= 0 {
+		return time.Now()
 	}
+	sec, frac := math.Modf(timestamp)
+	return time.Unix(int64(sec), int64(frac*float64(time.Second)))
+}
+
+func (j *Job) httpClient() *httpClient {
+	if j.agentsAPI == nil {
+		return nil
+	}
+	return j.agentsAPI.httpClient
 }
 
 // RetrieveStatus fetches job status.
@@ -132,8 +255,13 @@ type JobBatch struct {
 	agentsAPI *AgentsAPI
 	jobIDs    []string
 	timeout   time.Duration
+	schedule  PollSchedule
 	statuses  map[string]AgentJobStatus
 	completed map[string]AgentJobResult
+
+	checkpoint      func(JobBatchCheckpoint) error
+	checkpointEvery time.Duration
+	checkpointedAt  time.Time
 }
 
 func newJobBatch(api *AgentsAPI, jobIDs []string, timeoutSeconds int) *JobBatch {
@@ -150,25 +278,49 @@ func newJobBatch(api *AgentsAPI, jobIDs []string, timeoutSeconds int) *JobBatch
 	}
 }
 
+func (b *JobBatch) httpClient() *httpClient {
+	if b.agentsAPI == nil {
+		return nil
+	}
+	return b.agentsAPI.httpClient
+}
+
+// WithPollSchedule sets the schedule used when Wait is called with a zero
+// interval, overriding Config.PollSchedule, and returns the batch. Jobs
+// returned by Jobs inherit it.
+func (b *JobBatch) WithPollSchedule(schedule PollSchedule) *JobBatch {
+	b.schedule = schedule
+	return b
+}
+
 // Jobs returns individual Job handles.
 func (b *JobBatch) Jobs() []*Job {
 	jobs := make([]*Job, 0, len(b.jobIDs))
 	for _, id := range b.jobIDs {
-		jobs = append(jobs, newJob(b.agentsAPI, id, int(b.timeout/time.Second)))
+		job := newJob(b.agentsAPI, id, int(b.timeout/time.Second))
+		job.schedule = b.schedule
+		jobs = append(jobs, job)
 	}
 	return jobs
 }
 
-// Wait waits for all jobs to finish and returns results in same order.
+// Wait waits for all jobs to finish and returns results in same order. The
+// interval works as in Job.Wait.
 func (b *JobBatch) Wait(interval time.Duration, timeout time.Duration) ([]AgentJobResult, error) {
 	return b.WaitContext(context.Background(), interval, timeout)
 }
 
 // WaitContext waits for all jobs with context cancellation and ordered results.
-func (b *JobBatch) WaitContext(ctx context.Context, interval time.Duration, timeout time.Duration) ([]AgentJobResult, error) {
-	if interval <= 0 {
-		interval = 2 * time.Second
-	}
+func (b *JobBatch) WaitContext(ctx context.Context, interval time.Duration, timeout time.Duration) (_ []AgentJobResult, err error) {
+	ctx, span := b.httpClient().startSpan(ctx, "JobBatch.Wait", SpanAttribute{Key: AttrJobCount, Value: len(b.jobIDs)})
+	defer endSpan(span, &err)
+	start := time.Now()
+	results, err := b.wait(ctx, interval, timeout)
+	b.httpClient().observeJobWait(JobWaitMetric{Batch: true, Jobs: len(b.jobIDs), Duration: time.Since(start), Err: err})
+	return results, err
+}
+
+func (b *JobBatch) wait(ctx context.Context, interval time.Duration, timeout time.Duration) ([]AgentJobResult, error) {
 	if timeout <= 0 {
 		timeout = b.timeout
 	}
@@ -181,21 +333,157 @@ func (b *JobBatch) WaitContext(ctx context.Context, interval time.Duration, time
 		defer cancel()
 	}
 
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	if err := b.poll(ctx, "wait", interval, nil); err != nil {
+		return nil, b.incomplete(err)
+	}
+
+	results := make([]AgentJobResult, 0, len(b.jobIDs))
+	for _, id := range b.jobIDs {
+		if res, ok := b.completed[id]; ok {
+			results = append(results, res)
+		}
+	}
+
+	return results, nil
+}
+
+// BatchIncompleteError is returned by JobBatch.Wait when it stops before
+// every job has finished: on timeout, a cancelled context or a polling error,
+// which Err holds. It keeps what the wait had already collected. Call Wait
+// again to keep waiting on the pending jobs; results already fetched are not
+// fetched again.
+type BatchIncompleteError struct {
+	// Completed holds the finished jobs in batch order.
+	Completed []JobBatchResult
+	// Pending lists the unfinished job IDs in batch order.
+	Pending []string
+	// Statuses holds the last known status of each pending job that was
+	// polled at least once.
+	Statuses map[string]AgentJobStatus
+	Err      error
+}
+
+func (e *BatchIncompleteError) Error() string {
+	return fmt.Sprintf("job batch incomplete (%d of %d jobs finished): %v", len(e.Completed), len(e.Completed)+len(e.Pending), e.Err)
+}
+
+func (e *BatchIncompleteError) Unwrap() error { return e.Err }
+
+func (b *JobBatch) incomplete(err error) *BatchIncompleteError {
+	incomplete := &BatchIncompleteError{Statuses: map[string]AgentJobStatus{}, Err: err}
+	for i, id := range b.jobIDs {
+		if result, ok := b.completed[id]; ok {
+			incomplete.Completed = append(incomplete.Completed, JobBatchResult{Index: i, JobID: id, Result: result})
+			continue
+		}
+		incomplete.Pending = append(incomplete.Pending, id)
+		if status, ok := b.statuses[id]; ok {
+			incomplete.Statuses[id] = status
+		}
+	}
+	return incomplete
+}
+
+// JobBatchResult is one finished job yielded by JobBatch.Results.
+type JobBatchResult struct {
+	// Index is the job's position in the batch, matching the order of the
+	// inputs the batch was run with.
+	Index  int
+	JobID  string
+	Result AgentJobResult
+}
+
+// Results polls the batch like Wait but yields each job's result as soon as
+// it is fetched instead of waiting for the whole batch, so results can be
+// processed while slower jobs are still running. Jobs that finished before
+// Results was called are yielded first, in batch order. A polling error or
+// cancelled ctx is yielded as the final error; breaking out of the loop
+// stops polling. Like Watch, Results is bounded only by ctx.
+//
+//	for item, err := range batch.Results(ctx, 0) {
+//		if err != nil {
+//			return err
+//		}
+//		write(item.Index, item.Result)
+//	}
+func (b *JobBatch) Results(ctx context.Context, interval time.Duration) iter.Seq2[JobBatchResult, error] {
+	return func(yield func(JobBatchResult, error) bool) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, span := b.httpClient().startSpan(ctx, "JobBatch.Results", SpanAttribute{Key: AttrJobCount, Value: len(b.jobIDs)})
+		defer span.End()
+
+		indexes := map[string][]int{}
+		for i, id := range b.jobIDs {
+			indexes[id] = append(indexes[id], i)
+		}
+		emit := func(id string, result AgentJobResult) bool {
+			for _, index := range indexes[id] {
+				if !yield(JobBatchResult{Index: index, JobID: id, Result: result}, nil) {
+					return false
+				}
+			}
+			return true
+		}
+		for _, id := range b.jobIDs {
+			if result, ok := b.completed[id]; ok && len(indexes[id]) > 0 {
+				if !emit(id, result) {
+					return
+				}
+				delete(indexes, id)
+			}
+		}
+
+		err := b.poll(ctx, "results", interval, emit)
+		if err != nil && !errors.Is(err, errStopPolling) {
+			recordSpanError(span, err)
+			yield(JobBatchResult{}, err)
+		}
+	}
+}
 
-	pending := append([]string{}, b.jobIDs...)
+// poll fetches statuses for the unfinished jobs on the resolved PollSchedule
+// and the results of those that became terminal, until every job is done.
+// Each result is passed to emit, if set, as soon as it is fetched; poll stops
+// with errStopPolling when emit returns false. op names the operation in
+// cancellation errors. Configured checkpoints are saved between rounds and
+// when poll returns.
+func (b *JobBatch) poll(ctx context.Context, op string, interval time.Duration, emit func(id string, result AgentJobResult) bool) (err error) {
+	defer func() {
+		if saveErr := b.saveCheckpoint(true); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}()
+	schedule := resolvePollSchedule(interval, b.schedule, b.agentsAPI)
+	start := time.Now()
+	pending := []string{}
+	for _, id := range b.jobIDs {
+		if _, done := b.completed[id]; !done {
+			pending = append(pending, id)
+		}
+	}
+	complete := func(id string, result AgentJobResult) bool {
+		b.completed[id] = result
+		return emit == nil || emit(id, result)
+	}
 
-	for len(pending) > 0 {
+	for poll := 0; len(pending) > 0; poll++ {
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("job batch wait cancelled: %w", ctx.Err())
+			return fmt.Errorf("job batch %s cancelled: %w", op, ctx.Err())
 		default:
 		}
 
 		statusBatch, err := b.agentsAPI.Jobs.RetrieveStatusManyWithContext(ctx, pending)
+		if rateLimit, limited := pollRateLimited(err); limited {
+			if err := pausePolling(ctx, schedule, poll, start, rateLimit); err != nil {
+				return fmt.Errorf("job batch %s cancelled: %w", op, err)
+			}
+			continue
+		}
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		var ready []string
@@ -214,43 +502,41 @@ func (b *JobBatch) WaitContext(ctx context.Context, interval time.Duration, time
 
 		if len(ready) > 0 {
 			resultsBatch, err := b.agentsAPI.Jobs.RetrieveResultManyWithContext(ctx, ready)
+			finished := map[string]AgentJobResult{}
 			if err != nil {
 				// Synthesize empty results for failed/cancelled jobs; propagate error if any success/cached jobs are affected
-				allFailed := true
 				for _, id := range ready {
-					if cached, ok := b.statuses[id]; ok && (cached.Status == JobFailure || cached.Status == JobCancelled) {
-						result := AgentJobResult{Status: &cached.Status, ErrorMessage: cached.ErrorMessage}
-						b.completed[id] = result
-					} else {
-						allFailed = false
+					cached, ok := b.statuses[id]
+					if !ok || (cached.Status != JobFailure && cached.Status != JobCancelled) {
+						return err
 					}
+					finished[id] = AgentJobResult{Status: &cached.Status, ErrorMessage: cached.ErrorMessage}
 				}
-				if !allFailed {
-					return nil, err
-				}
-				pending = removeCompleted(pending, ready)
 			} else {
-				received := map[string]AgentJobResult{}
 				for _, res := range resultsBatch {
 					converted, err := convertBatchResult(res)
 					if err != nil {
-						return nil, err
+						return err
 					}
 					if cached, ok := b.statuses[res.ID]; ok {
 						converted.Status = &cached.Status
 						converted.ErrorMessage = cached.ErrorMessage
 					}
-					received[res.ID] = converted
-					b.completed[res.ID] = converted
+					finished[res.ID] = converted
 				}
 
 				for _, id := range ready {
-					if _, ok := received[id]; !ok {
-						return nil, fmt.Errorf("job %s result missing in batch response", id)
+					if _, ok := finished[id]; !ok {
+						return fmt.Errorf("job %s result missing in batch response", id)
 					}
 				}
+			}
 
-				pending = removeCompleted(pending, ready)
+			pending = removeCompleted(pending, ready)
+			for _, id := range ready {
+				if !complete(id, finished[id]) {
+					return errStopPolling
+				}
 			}
 		}
 
@@ -258,21 +544,14 @@ func (b *JobBatch) WaitContext(ctx context.Context, interval time.Duration, time
 			break
 		}
 
-		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("job batch wait cancelled: %w", ctx.Err())
-		case <-ticker.C:
+		if err := b.saveCheckpoint(false); err != nil {
+			return err
 		}
-	}
-
-	results := make([]AgentJobResult, 0, len(b.jobIDs))
-	for _, id := range b.jobIDs {
-		if res, ok := b.completed[id]; ok {
-			results = append(results, res)
+		if err := pausePolling(ctx, schedule, poll, start, nil); err != nil {
+			return fmt.Errorf("job batch %s cancelled: %w", op, err)
 		}
 	}
-
-	return results, nil
+	return nil
 }
 
 // RetrieveStatus returns latest known statuses keyed by job id.
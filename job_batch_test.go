@@ -1,9 +1,12 @@
 package roe
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -101,3 +104,156 @@ func TestJobBatchWaitPreservesOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestJobBatchResultsStreamsAsJobsFinish(t *testing.T) {
+	jobIDs := []string{"job-1", "job-2", "job-3"}
+	var mu sync.Mutex
+	statusPolls := 0
+	var resultRequests [][]string
+
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			JobIDs []string `json:"job_ids"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.Header().Set("Content-Type", "application/json")
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/statuses/"):
+			statusPolls++
+			statuses := make([]AgentJobStatusBatch, 0, len(payload.JobIDs))
+			for _, id := range payload.JobIDs {
+				status := JobStarted
+				// job-2 finishes on the first poll, the others on the third.
+				if id == "job-2" || statusPolls >= 3 {
+					status = JobSuccess
+				}
+				statuses = append(statuses, AgentJobStatusBatch{ID: id, Status: &status})
+			}
+			_ = json.NewEncoder(w).Encode(statuses)
+		case strings.HasSuffix(r.URL.Path, "/results/"):
+			resultRequests = append(resultRequests, payload.JobIDs)
+			agentID, versionID := "agent", "v1"
+			results := make([]AgentJobResultBatch, 0, len(payload.JobIDs))
+			for _, id := range payload.JobIDs {
+				results = append(results, AgentJobResultBatch{
+					ID:             id,
+					AgentID:        &agentID,
+					AgentVersionID: &versionID,
+					Result:         []any{map[string]any{"key": "out", "value": id, "description": "", "data_type": "text/plain"}},
+				})
+			}
+			_ = json.NewEncoder(w).Encode(results)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := Config{APIKey: "k", OrganizationID: "org", BaseURL: server.URL, Timeout: time.Second}
+	client := newHTTPClient(cfg, newAuth(cfg))
+	defer client.close()
+
+	batch := newJobBatch(newAgentsAPI(cfg, client), jobIDs, 1)
+	var got []JobBatchResult
+	for item, err := range batch.Results(context.Background(), time.Millisecond) {
+		if err != nil {
+			t.Fatalf("results: %v", err)
+		}
+		got = append(got, item)
+		if len(got) == 1 {
+			mu.Lock()
+			polls := statusPolls
+			mu.Unlock()
+			if polls != 1 {
+				t.Fatalf("expected the first result after one poll, got %d polls", polls)
+			}
+		}
+	}
+
+	wantOrder := []JobBatchResult{{Index: 1, JobID: "job-2"}, {Index: 0, JobID: "job-1"}, {Index: 2, JobID: "job-3"}}
+	if len(got) != len(wantOrder) {
+		t.Fatalf("expected %d results, got %+v", len(wantOrder), got)
+	}
+	for i, want := range wantOrder {
+		if got[i].Index != want.Index || got[i].JobID != want.JobID || got[i].Result.Outputs[0].Value != want.JobID {
+			t.Fatalf("result %d: expected %+v, got %+v", i, want, got[i])
+		}
+	}
+	if len(resultRequests) != 2 || len(resultRequests[0]) != 1 {
+		t.Fatalf("expected results fetched per poll, got %v", resultRequests)
+	}
+
+	// A later Wait reuses the results already fetched.
+	results, err := batch.Wait(time.Millisecond, time.Second)
+	if err != nil || len(results) != 3 || results[1].Outputs[0].Value != "job-2" {
+		t.Fatalf("expected cached ordered results, got %+v (%v)", results, err)
+	}
+	if len(resultRequests) != 2 {
+		t.Fatalf("expected no refetch, got %v", resultRequests)
+	}
+}
+
+func TestJobBatchWaitTimeoutKeepsPartialResults(t *testing.T) {
+	var mu sync.Mutex
+	job2Done := false
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			JobIDs []string `json:"job_ids"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.Header().Set("Content-Type", "application/json")
+		mu.Lock()
+		defer mu.Unlock()
+
+		if strings.HasSuffix(r.URL.Path, "/statuses/") {
+			statuses := make([]AgentJobStatusBatch, 0, len(payload.JobIDs))
+			for _, id := range payload.JobIDs {
+				status := JobSuccess
+				if id == "job-2" && !job2Done {
+					status = JobStarted
+				}
+				statuses = append(statuses, AgentJobStatusBatch{ID: id, Status: &status})
+			}
+			_ = json.NewEncoder(w).Encode(statuses)
+			return
+		}
+		agentID, versionID := "agent", "v1"
+		results := make([]AgentJobResultBatch, 0, len(payload.JobIDs))
+		for _, id := range payload.JobIDs {
+			results = append(results, AgentJobResultBatch{ID: id, AgentID: &agentID, AgentVersionID: &versionID, Result: []any{}})
+		}
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	cfg := Config{APIKey: "k", OrganizationID: "org", BaseURL: server.URL, Timeout: time.Second}
+	client := newHTTPClient(cfg, newAuth(cfg))
+	defer client.close()
+
+	batch := newJobBatch(newAgentsAPI(cfg, client), []string{"job-1", "job-2"}, 1)
+	_, err := batch.Wait(time.Millisecond, 30*time.Millisecond)
+	var incomplete *BatchIncompleteError
+	if !errors.As(err, &incomplete) {
+		t.Fatalf("expected *BatchIncompleteError, got %T: %v", err, err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the timeout to be wrapped, got %v", err)
+	}
+	if len(incomplete.Completed) != 1 || incomplete.Completed[0].JobID != "job-1" || incomplete.Completed[0].Index != 0 {
+		t.Fatalf("unexpected completed results %+v", incomplete.Completed)
+	}
+	if len(incomplete.Pending) != 1 || incomplete.Pending[0] != "job-2" || incomplete.Statuses["job-2"].Status != JobStarted {
+		t.Fatalf("unexpected pending state %v %+v", incomplete.Pending, incomplete.Statuses)
+	}
+
+	mu.Lock()
+	job2Done = true
+	mu.Unlock()
+	results, err := batch.Wait(time.Millisecond, time.Second)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("expected the resumed wait to finish, got %d results (%v)", len(results), err)
+	}
+}
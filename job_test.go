@@ -0,0 +1,95 @@
+package roe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newStatusSequenceClient(t *testing.T, statuses []JobStatus) (*AgentsAPI, func()) {
+	t.Helper()
+	var mu sync.Mutex
+	polls := 0
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		status := statuses[min(polls, len(statuses)-1)]
+		polls++
+		mu.Unlock()
+		resp := AgentJobStatus{Status: status, Timestamp: 1767225600.5}
+		if status == JobRetry {
+			msg := "worker restarted"
+			resp.ErrorMessage = &msg
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+
+	cfg := Config{APIKey: "k", OrganizationID: "org", BaseURL: server.URL, Timeout: time.Second}
+	client := newHTTPClient(cfg, newAuth(cfg))
+	return newAgentsAPI(cfg, client), func() {
+		client.close()
+		server.Close()
+	}
+}
+
+func TestJobWatchYieldsTransitionsUntilTerminal(t *testing.T) {
+	agents, cleanup := newStatusSequenceClient(t, []JobStatus{JobPending, JobPending, JobStarted, JobStarted, JobRetry, JobStarted, JobSuccess})
+	defer cleanup()
+
+	var got []JobEvent
+	for event, err := range newJob(agents, "job-1", 0).Watch(context.Background(), time.Millisecond) {
+		if err != nil {
+			t.Fatalf("watch: %v", err)
+		}
+		got = append(got, event)
+	}
+
+	want := []struct{ previous, status JobStatus }{
+		{JobPending, JobPending},
+		{JobPending, JobStarted},
+		{JobStarted, JobRetry},
+		{JobRetry, JobStarted},
+		{JobStarted, JobSuccess},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Previous != w.previous || got[i].Status != w.status || got[i].JobID != "job-1" {
+			t.Fatalf("event %d: expected %s -> %s, got %+v", i, w.previous, w.status, got[i])
+		}
+	}
+	if got[2].ErrorMessage == nil || *got[2].ErrorMessage != "worker restarted" {
+		t.Fatalf("expected the retry error message, got %+v", got[2])
+	}
+	if !got[0].Time.Equal(time.Unix(1767225600, int64(500*time.Millisecond))) {
+		t.Fatalf("expected the server timestamp, got %s", got[0].Time)
+	}
+}
+
+func TestJobWatchStopsOnCancellation(t *testing.T) {
+	agents, cleanup := newStatusSequenceClient(t, []JobStatus{JobStarted})
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := 0
+	var watchErr error
+	for event, err := range newJob(agents, "job-1", 0).Watch(ctx, time.Millisecond) {
+		if err != nil {
+			watchErr = err
+			continue
+		}
+		events++
+		if event.Status == JobStarted {
+			cancel()
+		}
+	}
+	if events != 1 || !errors.Is(watchErr, context.Canceled) {
+		t.Fatalf("expected one event then context.Canceled, got %d events and %v", events, watchErr)
+	}
+}
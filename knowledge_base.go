@@ -80,12 +80,14 @@ func (k *KnowledgeBaseAPI) orgQuery() map[string]string {
 }
 
 // List returns paginated knowledge bases for the organisation.
-func (k *KnowledgeBaseAPI) List(page, pageSize int) (PaginatedResponse[KnowledgeBase], error) {
-	return k.ListWithContext(context.Background(), page, pageSize)
+func (k *KnowledgeBaseAPI) List(page, pageSize int, opts ...RequestOption) (PaginatedResponse[KnowledgeBase], error) {
+	return k.ListWithContext(context.Background(), page, pageSize, opts...)
 }
 
 // ListWithContext returns paginated knowledge bases with a caller-supplied context.
-func (k *KnowledgeBaseAPI) ListWithContext(ctx context.Context, page, pageSize int) (PaginatedResponse[KnowledgeBase], error) {
+func (k *KnowledgeBaseAPI) ListWithContext(ctx context.Context, page, pageSize int, opts ...RequestOption) (_ PaginatedResponse[KnowledgeBase], err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.List")
+	defer endSpan(span, &err)
 	params := k.orgQuery()
 	if page > 0 {
 		params["page"] = fmt.Sprintf("%d", page)
@@ -94,7 +96,7 @@ func (k *KnowledgeBaseAPI) ListWithContext(ctx context.Context, page, pageSize i
 		params["page_size"] = fmt.Sprintf("%d", pageSize)
 	}
 	var resp PaginatedResponse[KnowledgeBase]
-	if err := k.httpClient.getWithContext(ctx, "/v1/knowledge-base/", params, &resp); err != nil {
+	if err := k.httpClient.getWithContext(ctx, "/v1/knowledge-base/", params, &resp, opts...); err != nil {
 		return PaginatedResponse[KnowledgeBase]{}, err
 	}
 	return resp, nil
@@ -102,12 +104,14 @@ func (k *KnowledgeBaseAPI) ListWithContext(ctx context.Context, page, pageSize i
 
 // Create starts a new knowledge base draft (async generation). Pass empty
 // strings for the optional name, productName, and websiteURL.
-func (k *KnowledgeBaseAPI) Create(company, brief, name, productName, websiteURL string) (KnowledgeBase, error) {
-	return k.CreateWithContext(context.Background(), company, brief, name, productName, websiteURL)
+func (k *KnowledgeBaseAPI) Create(company, brief, name, productName, websiteURL string, opts ...RequestOption) (KnowledgeBase, error) {
+	return k.CreateWithContext(context.Background(), company, brief, name, productName, websiteURL, opts...)
 }
 
 // CreateWithContext starts a new knowledge base draft with a caller-supplied context.
-func (k *KnowledgeBaseAPI) CreateWithContext(ctx context.Context, company, brief, name, productName, websiteURL string) (KnowledgeBase, error) {
+func (k *KnowledgeBaseAPI) CreateWithContext(ctx context.Context, company, brief, name, productName, websiteURL string, opts ...RequestOption) (_ KnowledgeBase, err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.Create")
+	defer endSpan(span, &err)
 	if company == "" {
 		return KnowledgeBase{}, fmt.Errorf("company cannot be empty")
 	}
@@ -128,68 +132,76 @@ func (k *KnowledgeBaseAPI) CreateWithContext(ctx context.Context, company, brief
 		payload["website_url"] = websiteURL
 	}
 	var resp KnowledgeBase
-	if err := k.httpClient.postJSONWithContext(ctx, "/v1/knowledge-base/", payload, k.orgQuery(), &resp); err != nil {
+	if err := k.httpClient.postJSONWithContext(ctx, "/v1/knowledge-base/", payload, k.orgQuery(), &resp, opts...); err != nil {
 		return KnowledgeBase{}, err
 	}
 	return resp, nil
 }
 
 // Retrieve fetches a single knowledge base record.
-func (k *KnowledgeBaseAPI) Retrieve(knowledgeBaseID string) (KnowledgeBase, error) {
-	return k.RetrieveWithContext(context.Background(), knowledgeBaseID)
+func (k *KnowledgeBaseAPI) Retrieve(knowledgeBaseID string, opts ...RequestOption) (KnowledgeBase, error) {
+	return k.RetrieveWithContext(context.Background(), knowledgeBaseID, opts...)
 }
 
 // RetrieveWithContext fetches a single knowledge base record with a caller-supplied context.
-func (k *KnowledgeBaseAPI) RetrieveWithContext(ctx context.Context, knowledgeBaseID string) (KnowledgeBase, error) {
+func (k *KnowledgeBaseAPI) RetrieveWithContext(ctx context.Context, knowledgeBaseID string, opts ...RequestOption) (_ KnowledgeBase, err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.Retrieve")
+	defer endSpan(span, &err)
 	if knowledgeBaseID == "" {
 		return KnowledgeBase{}, fmt.Errorf("knowledgeBaseID cannot be empty")
 	}
 	var resp KnowledgeBase
-	if err := k.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/", knowledgeBaseID), k.orgQuery(), &resp); err != nil {
+	if err := k.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/", knowledgeBaseID), k.orgQuery(), &resp, opts...); err != nil {
 		return KnowledgeBase{}, err
 	}
 	return resp, nil
 }
 
 // Delete removes a knowledge base and its associated Atlas draft or lens.
-func (k *KnowledgeBaseAPI) Delete(knowledgeBaseID string) error {
-	return k.DeleteWithContext(context.Background(), knowledgeBaseID)
+func (k *KnowledgeBaseAPI) Delete(knowledgeBaseID string, opts ...RequestOption) error {
+	return k.DeleteWithContext(context.Background(), knowledgeBaseID, opts...)
 }
 
 // DeleteWithContext removes a knowledge base with a caller-supplied context.
-func (k *KnowledgeBaseAPI) DeleteWithContext(ctx context.Context, knowledgeBaseID string) error {
+func (k *KnowledgeBaseAPI) DeleteWithContext(ctx context.Context, knowledgeBaseID string, opts ...RequestOption) (err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.Delete")
+	defer endSpan(span, &err)
 	if knowledgeBaseID == "" {
 		return fmt.Errorf("knowledgeBaseID cannot be empty")
 	}
-	return k.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/", knowledgeBaseID), k.orgQuery())
+	return k.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/", knowledgeBaseID), k.orgQuery(), opts...)
 }
 
 // Unlink removes the local knowledge base row only, preserving the Atlas lens.
-func (k *KnowledgeBaseAPI) Unlink(knowledgeBaseID string) error {
-	return k.UnlinkWithContext(context.Background(), knowledgeBaseID)
+func (k *KnowledgeBaseAPI) Unlink(knowledgeBaseID string, opts ...RequestOption) error {
+	return k.UnlinkWithContext(context.Background(), knowledgeBaseID, opts...)
 }
 
 // UnlinkWithContext unlinks a knowledge base with a caller-supplied context.
-func (k *KnowledgeBaseAPI) UnlinkWithContext(ctx context.Context, knowledgeBaseID string) error {
+func (k *KnowledgeBaseAPI) UnlinkWithContext(ctx context.Context, knowledgeBaseID string, opts ...RequestOption) (err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.Unlink")
+	defer endSpan(span, &err)
 	if knowledgeBaseID == "" {
 		return fmt.Errorf("knowledgeBaseID cannot be empty")
 	}
-	return k.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/unlink/", knowledgeBaseID), k.orgQuery())
+	return k.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/unlink/", knowledgeBaseID), k.orgQuery(), opts...)
 }
 
 // PollDraft fetches the Atlas draft status. Poll until the status is "ready"
 // (or "error").
-func (k *KnowledgeBaseAPI) PollDraft(knowledgeBaseID string) (KnowledgeBaseDraft, error) {
-	return k.PollDraftWithContext(context.Background(), knowledgeBaseID)
+func (k *KnowledgeBaseAPI) PollDraft(knowledgeBaseID string, opts ...RequestOption) (KnowledgeBaseDraft, error) {
+	return k.PollDraftWithContext(context.Background(), knowledgeBaseID, opts...)
 }
 
 // PollDraftWithContext fetches the Atlas draft status with a caller-supplied context.
-func (k *KnowledgeBaseAPI) PollDraftWithContext(ctx context.Context, knowledgeBaseID string) (KnowledgeBaseDraft, error) {
+func (k *KnowledgeBaseAPI) PollDraftWithContext(ctx context.Context, knowledgeBaseID string, opts ...RequestOption) (_ KnowledgeBaseDraft, err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.PollDraft")
+	defer endSpan(span, &err)
 	if knowledgeBaseID == "" {
 		return KnowledgeBaseDraft{}, fmt.Errorf("knowledgeBaseID cannot be empty")
 	}
 	var resp KnowledgeBaseDraft
-	if err := k.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/draft/", knowledgeBaseID), k.orgQuery(), &resp); err != nil {
+	if err := k.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/draft/", knowledgeBaseID), k.orgQuery(), &resp, opts...); err != nil {
 		return KnowledgeBaseDraft{}, err
 	}
 	return resp, nil
@@ -197,12 +209,14 @@ func (k *KnowledgeBaseAPI) PollDraftWithContext(ctx context.Context, knowledgeBa
 
 // PatchSelection persists hand-edits to the draft's typology/tactic selection.
 // Pass an empty string for the optional suggestedName.
-func (k *KnowledgeBaseAPI) PatchSelection(knowledgeBaseID string, refs []map[string]any, suggestedName string) (KnowledgeBaseDraft, error) {
-	return k.PatchSelectionWithContext(context.Background(), knowledgeBaseID, refs, suggestedName)
+func (k *KnowledgeBaseAPI) PatchSelection(knowledgeBaseID string, refs []map[string]any, suggestedName string, opts ...RequestOption) (KnowledgeBaseDraft, error) {
+	return k.PatchSelectionWithContext(context.Background(), knowledgeBaseID, refs, suggestedName, opts...)
 }
 
 // PatchSelectionWithContext patches the draft selection with a caller-supplied context.
-func (k *KnowledgeBaseAPI) PatchSelectionWithContext(ctx context.Context, knowledgeBaseID string, refs []map[string]any, suggestedName string) (KnowledgeBaseDraft, error) {
+func (k *KnowledgeBaseAPI) PatchSelectionWithContext(ctx context.Context, knowledgeBaseID string, refs []map[string]any, suggestedName string, opts ...RequestOption) (_ KnowledgeBaseDraft, err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.PatchSelection")
+	defer endSpan(span, &err)
 	if knowledgeBaseID == "" {
 		return KnowledgeBaseDraft{}, fmt.Errorf("knowledgeBaseID cannot be empty")
 	}
@@ -211,7 +225,7 @@ func (k *KnowledgeBaseAPI) PatchSelectionWithContext(ctx context.Context, knowle
 		payload["suggested_name"] = suggestedName
 	}
 	var resp KnowledgeBaseDraft
-	if err := k.httpClient.patchJSONWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/selection/", knowledgeBaseID), payload, k.orgQuery(), &resp); err != nil {
+	if err := k.httpClient.patchJSONWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/selection/", knowledgeBaseID), payload, k.orgQuery(), &resp, opts...); err != nil {
 		return KnowledgeBaseDraft{}, err
 	}
 	return resp, nil
@@ -219,12 +233,14 @@ func (k *KnowledgeBaseAPI) PatchSelectionWithContext(ctx context.Context, knowle
 
 // Regenerate kicks off an async regeneration round. Pass an empty string for
 // no feedback.
-func (k *KnowledgeBaseAPI) Regenerate(knowledgeBaseID, feedback string) (KnowledgeBaseDraft, error) {
-	return k.RegenerateWithContext(context.Background(), knowledgeBaseID, feedback)
+func (k *KnowledgeBaseAPI) Regenerate(knowledgeBaseID, feedback string, opts ...RequestOption) (KnowledgeBaseDraft, error) {
+	return k.RegenerateWithContext(context.Background(), knowledgeBaseID, feedback, opts...)
 }
 
 // RegenerateWithContext kicks off a regeneration round with a caller-supplied context.
-func (k *KnowledgeBaseAPI) RegenerateWithContext(ctx context.Context, knowledgeBaseID, feedback string) (KnowledgeBaseDraft, error) {
+func (k *KnowledgeBaseAPI) RegenerateWithContext(ctx context.Context, knowledgeBaseID, feedback string, opts ...RequestOption) (_ KnowledgeBaseDraft, err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.Regenerate")
+	defer endSpan(span, &err)
 	if knowledgeBaseID == "" {
 		return KnowledgeBaseDraft{}, fmt.Errorf("knowledgeBaseID cannot be empty")
 	}
@@ -233,7 +249,7 @@ func (k *KnowledgeBaseAPI) RegenerateWithContext(ctx context.Context, knowledgeB
 		payload["feedback"] = feedback
 	}
 	var resp KnowledgeBaseDraft
-	if err := k.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/regenerate/", knowledgeBaseID), payload, k.orgQuery(), &resp); err != nil {
+	if err := k.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/regenerate/", knowledgeBaseID), payload, k.orgQuery(), &resp, opts...); err != nil {
 		return KnowledgeBaseDraft{}, err
 	}
 	return resp, nil
@@ -242,12 +258,14 @@ func (k *KnowledgeBaseAPI) RegenerateWithContext(ctx context.Context, knowledgeB
 // Resolve approves or declines a pending regeneration proposal. To apply,
 // pass the resolved refs (and optionally suggestedName / acceptSummary); to
 // decline, pass discard=true.
-func (k *KnowledgeBaseAPI) Resolve(knowledgeBaseID string, refs []map[string]any, suggestedName string, acceptSummary, discard bool) (KnowledgeBaseDraft, error) {
-	return k.ResolveWithContext(context.Background(), knowledgeBaseID, refs, suggestedName, acceptSummary, discard)
+func (k *KnowledgeBaseAPI) Resolve(knowledgeBaseID string, refs []map[string]any, suggestedName string, acceptSummary, discard bool, opts ...RequestOption) (KnowledgeBaseDraft, error) {
+	return k.ResolveWithContext(context.Background(), knowledgeBaseID, refs, suggestedName, acceptSummary, discard, opts...)
 }
 
 // ResolveWithContext resolves a pending proposal with a caller-supplied context.
-func (k *KnowledgeBaseAPI) ResolveWithContext(ctx context.Context, knowledgeBaseID string, refs []map[string]any, suggestedName string, acceptSummary, discard bool) (KnowledgeBaseDraft, error) {
+func (k *KnowledgeBaseAPI) ResolveWithContext(ctx context.Context, knowledgeBaseID string, refs []map[string]any, suggestedName string, acceptSummary, discard bool, opts ...RequestOption) (_ KnowledgeBaseDraft, err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.Resolve")
+	defer endSpan(span, &err)
 	if knowledgeBaseID == "" {
 		return KnowledgeBaseDraft{}, fmt.Errorf("knowledgeBaseID cannot be empty")
 	}
@@ -262,7 +280,7 @@ func (k *KnowledgeBaseAPI) ResolveWithContext(ctx context.Context, knowledgeBase
 		payload["suggested_name"] = suggestedName
 	}
 	var resp KnowledgeBaseDraft
-	if err := k.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/resolve/", knowledgeBaseID), payload, k.orgQuery(), &resp); err != nil {
+	if err := k.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/resolve/", knowledgeBaseID), payload, k.orgQuery(), &resp, opts...); err != nil {
 		return KnowledgeBaseDraft{}, err
 	}
 	return resp, nil
@@ -271,12 +289,14 @@ func (k *KnowledgeBaseAPI) ResolveWithContext(ctx context.Context, knowledgeBase
 // Finalize commits the draft into a permanent Atlas lens. Pass an empty
 // string to keep the knowledge base's current name. The backend defaults
 // mcpEnabled and public to true; pass them explicitly here.
-func (k *KnowledgeBaseAPI) Finalize(knowledgeBaseID, name string, mcpEnabled, public bool) (KnowledgeBase, error) {
-	return k.FinalizeWithContext(context.Background(), knowledgeBaseID, name, mcpEnabled, public)
+func (k *KnowledgeBaseAPI) Finalize(knowledgeBaseID, name string, mcpEnabled, public bool, opts ...RequestOption) (KnowledgeBase, error) {
+	return k.FinalizeWithContext(context.Background(), knowledgeBaseID, name, mcpEnabled, public, opts...)
 }
 
 // FinalizeWithContext finalizes the draft with a caller-supplied context.
-func (k *KnowledgeBaseAPI) FinalizeWithContext(ctx context.Context, knowledgeBaseID, name string, mcpEnabled, public bool) (KnowledgeBase, error) {
+func (k *KnowledgeBaseAPI) FinalizeWithContext(ctx context.Context, knowledgeBaseID, name string, mcpEnabled, public bool, opts ...RequestOption) (_ KnowledgeBase, err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.Finalize")
+	defer endSpan(span, &err)
 	if knowledgeBaseID == "" {
 		return KnowledgeBase{}, fmt.Errorf("knowledgeBaseID cannot be empty")
 	}
@@ -288,7 +308,7 @@ func (k *KnowledgeBaseAPI) FinalizeWithContext(ctx context.Context, knowledgeBas
 		payload["name"] = name
 	}
 	var resp KnowledgeBase
-	if err := k.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/finalize/", knowledgeBaseID), payload, k.orgQuery(), &resp); err != nil {
+	if err := k.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/finalize/", knowledgeBaseID), payload, k.orgQuery(), &resp, opts...); err != nil {
 		return KnowledgeBase{}, err
 	}
 	return resp, nil
@@ -296,17 +316,19 @@ func (k *KnowledgeBaseAPI) FinalizeWithContext(ctx context.Context, knowledgeBas
 
 // Sync refreshes the lens snapshot from Atlas (best-effort; always returns
 // the record, with any failure described in sync_error).
-func (k *KnowledgeBaseAPI) Sync(knowledgeBaseID string) (KnowledgeBase, error) {
-	return k.SyncWithContext(context.Background(), knowledgeBaseID)
+func (k *KnowledgeBaseAPI) Sync(knowledgeBaseID string, opts ...RequestOption) (KnowledgeBase, error) {
+	return k.SyncWithContext(context.Background(), knowledgeBaseID, opts...)
 }
 
 // SyncWithContext refreshes the lens snapshot with a caller-supplied context.
-func (k *KnowledgeBaseAPI) SyncWithContext(ctx context.Context, knowledgeBaseID string) (KnowledgeBase, error) {
+func (k *KnowledgeBaseAPI) SyncWithContext(ctx context.Context, knowledgeBaseID string, opts ...RequestOption) (_ KnowledgeBase, err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.Sync")
+	defer endSpan(span, &err)
 	if knowledgeBaseID == "" {
 		return KnowledgeBase{}, fmt.Errorf("knowledgeBaseID cannot be empty")
 	}
 	var resp KnowledgeBase
-	if err := k.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/sync/", knowledgeBaseID), nil, k.orgQuery(), &resp); err != nil {
+	if err := k.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/%s/sync/", knowledgeBaseID), nil, k.orgQuery(), &resp, opts...); err != nil {
 		return KnowledgeBase{}, err
 	}
 	return resp, nil
@@ -314,14 +336,16 @@ func (k *KnowledgeBaseAPI) SyncWithContext(ctx context.Context, knowledgeBaseID
 
 // Catalog fetches the names-only typology and tactic catalog. The endpoint
 // declares no response schema, so the decoded JSON is returned as-is.
-func (k *KnowledgeBaseAPI) Catalog() (any, error) {
-	return k.CatalogWithContext(context.Background())
+func (k *KnowledgeBaseAPI) Catalog(opts ...RequestOption) (any, error) {
+	return k.CatalogWithContext(context.Background(), opts...)
 }
 
 // CatalogWithContext fetches the catalog with a caller-supplied context.
-func (k *KnowledgeBaseAPI) CatalogWithContext(ctx context.Context) (any, error) {
+func (k *KnowledgeBaseAPI) CatalogWithContext(ctx context.Context, opts ...RequestOption) (_ any, err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.Catalog")
+	defer endSpan(span, &err)
 	var resp any
-	if err := k.httpClient.getWithContext(ctx, "/v1/knowledge-base/catalog/", k.orgQuery(), &resp); err != nil {
+	if err := k.httpClient.getWithContext(ctx, "/v1/knowledge-base/catalog/", k.orgQuery(), &resp, opts...); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -330,17 +354,19 @@ func (k *KnowledgeBaseAPI) CatalogWithContext(ctx context.Context) (any, error)
 // LensByAtlasId fetches (and best-effort syncs) a lens directly from Atlas by
 // its atlas_lens_id. The endpoint declares no response schema, so the decoded
 // JSON object is returned as-is.
-func (k *KnowledgeBaseAPI) LensByAtlasId(atlasLensID string) (map[string]any, error) {
-	return k.LensByAtlasIdWithContext(context.Background(), atlasLensID)
+func (k *KnowledgeBaseAPI) LensByAtlasId(atlasLensID string, opts ...RequestOption) (map[string]any, error) {
+	return k.LensByAtlasIdWithContext(context.Background(), atlasLensID, opts...)
 }
 
 // LensByAtlasIdWithContext fetches a lens by Atlas ID with a caller-supplied context.
-func (k *KnowledgeBaseAPI) LensByAtlasIdWithContext(ctx context.Context, atlasLensID string) (map[string]any, error) {
+func (k *KnowledgeBaseAPI) LensByAtlasIdWithContext(ctx context.Context, atlasLensID string, opts ...RequestOption) (_ map[string]any, err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.LensByAtlasId")
+	defer endSpan(span, &err)
 	if atlasLensID == "" {
 		return nil, fmt.Errorf("atlasLensID cannot be empty")
 	}
 	var resp map[string]any
-	if err := k.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/lens/%s/", atlasLensID), k.orgQuery(), &resp); err != nil {
+	if err := k.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/knowledge-base/lens/%s/", atlasLensID), k.orgQuery(), &resp, opts...); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -349,18 +375,20 @@ func (k *KnowledgeBaseAPI) LensByAtlasIdWithContext(ctx context.Context, atlasLe
 // ImportLens imports a finalized Atlas lens into roe-main by its
 // atlas_lens_id. Idempotent: re-importing an already-tracked lens syncs and
 // returns the existing record.
-func (k *KnowledgeBaseAPI) ImportLens(atlasLensID string) (KnowledgeBase, error) {
-	return k.ImportLensWithContext(context.Background(), atlasLensID)
+func (k *KnowledgeBaseAPI) ImportLens(atlasLensID string, opts ...RequestOption) (KnowledgeBase, error) {
+	return k.ImportLensWithContext(context.Background(), atlasLensID, opts...)
 }
 
 // ImportLensWithContext imports a lens with a caller-supplied context.
-func (k *KnowledgeBaseAPI) ImportLensWithContext(ctx context.Context, atlasLensID string) (KnowledgeBase, error) {
+func (k *KnowledgeBaseAPI) ImportLensWithContext(ctx context.Context, atlasLensID string, opts ...RequestOption) (_ KnowledgeBase, err error) {
+	ctx, span := k.httpClient.startSpan(ctx, "KnowledgeBaseAPI.ImportLens")
+	defer endSpan(span, &err)
 	if atlasLensID == "" {
 		return KnowledgeBase{}, fmt.Errorf("atlasLensID cannot be empty")
 	}
 	payload := map[string]any{"atlas_lens_id": atlasLensID}
 	var resp KnowledgeBase
-	if err := k.httpClient.postJSONWithContext(ctx, "/v1/knowledge-base/import-lens/", payload, k.orgQuery(), &resp); err != nil {
+	if err := k.httpClient.postJSONWithContext(ctx, "/v1/knowledge-base/import-lens/", payload, k.orgQuery(), &resp, opts...); err != nil {
 		return KnowledgeBase{}, err
 	}
 	return resp, nil
@@ -0,0 +1,73 @@
+package roe
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+)
+
+type logAttrsKey struct{}
+
+// WithLogAttrs returns a context whose SDK log records carry attrs, e.g. a
+// tenant or worker ID. Attributes accumulate across nested calls.
+func WithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	existing, _ := ctx.Value(logAttrsKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, logAttrsKey{}, merged)
+}
+
+// slog emits a record through Config.SlogLogger, adding the context's
+// attributes from WithLogAttrs.
+func (c *httpClient) slog(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	logger := c.cfg.SlogLogger
+	if logger == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	if extra, ok := ctx.Value(logAttrsKey{}).([]slog.Attr); ok {
+		attrs = append(attrs, extra...)
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// slogEnabled lets callers skip building expensive attributes (bodies).
+func (c *httpClient) slogEnabled(ctx context.Context, level slog.Level) bool {
+	if c.cfg.SlogLogger == nil {
+		return false
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return c.cfg.SlogLogger.Enabled(ctx, level)
+}
+
+// headerAttr renders headers as a group, applying RedactHeaders.
+func (c *httpClient) headerAttr(h http.Header) slog.Attr {
+	redacted := c.redactedHeaders(h)
+	keys := make([]string, 0, len(redacted))
+	for k := range redacted {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]any, 0, len(keys))
+	for _, k := range keys {
+		values := redacted[k]
+		if len(values) == 1 {
+			attrs = append(attrs, slog.String(k, values[0]))
+		} else {
+			attrs = append(attrs, slog.Any(k, values))
+		}
+	}
+	return slog.Group("headers", attrs...)
+}
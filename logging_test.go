@@ -0,0 +1,128 @@
+package roe
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) records(t *testing.T) []map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func newSlogTestClient(t *testing.T, baseURL string, level slog.Level, out *lockedBuffer) *RoeClient {
+	t.Helper()
+	client, err := NewClientWithConfig(Config{
+		APIKey:               "secret-key",
+		OrganizationID:       "org",
+		BaseURL:              baseURL,
+		Timeout:              time.Second,
+		MaxRetries:           1,
+		RetryInitialInterval: time.Millisecond,
+		RetryMaxInterval:     time.Millisecond,
+		RetryMultiplier:      1,
+		RedactHeaders:        []string{"Authorization"},
+		SlogLogger:           slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: level})),
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return client
+}
+
+func TestSlogLoggerLevelsAndContextAttrs(t *testing.T) {
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "req-503")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"detail":"down"}`))
+	}))
+	defer server.Close()
+
+	var out lockedBuffer
+	client := newSlogTestClient(t, server.URL, slog.LevelDebug, &out)
+	defer client.Close()
+
+	ctx := WithLogAttrs(WithLogAttrs(t.Context(), slog.String("tenant", "acme")), slog.Int("worker", 7))
+	if _, err := client.Agents.Jobs.RetrieveStatusWithContext(ctx, "job-1"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	var levels []string
+	for _, record := range out.records(t) {
+		levels = append(levels, record["level"].(string)+" "+record["msg"].(string))
+		if record["tenant"] != "acme" || record["worker"] != float64(7) {
+			t.Fatalf("expected context attrs on every record, got %v", record)
+		}
+		switch record["msg"] {
+		case "roe request":
+			headers := record["headers"].(map[string]any)
+			if headers["Authorization"] != "[redacted]" {
+				t.Fatalf("expected Authorization to be redacted, got %v", headers)
+			}
+		case "roe response":
+			if !strings.Contains(record["body"].(string), "down") || record["request_id"] != "req-503" {
+				t.Fatalf("expected body preview and request ID, got %v", record)
+			}
+		case "roe request failed":
+			if record["status"] != float64(http.StatusServiceUnavailable) || record["request_id"] != "req-503" {
+				t.Fatalf("expected status and request ID on the failure, got %v", record)
+			}
+		}
+	}
+	want := []string{
+		"DEBUG roe request", "DEBUG roe response", "WARN roe retrying request",
+		"DEBUG roe request", "DEBUG roe response", "ERROR roe request failed",
+	}
+	if strings.Join(levels, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected records:\n got %v\nwant %v", levels, want)
+	}
+}
+
+func TestSlogLoggerRespectsHandlerLevel(t *testing.T) {
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":1}`))
+	}))
+	defer server.Close()
+
+	var out lockedBuffer
+	client := newSlogTestClient(t, server.URL, slog.LevelWarn, &out)
+	defer client.Close()
+
+	if _, err := client.Agents.Jobs.RetrieveStatus("job-1"); err != nil {
+		t.Fatalf("retrieve status: %v", err)
+	}
+	if records := out.records(t); len(records) != 0 {
+		t.Fatalf("expected no records below warn, got %v", records)
+	}
+}
@@ -0,0 +1,67 @@
+package roe
+
+import (
+	"time"
+)
+
+// Metrics receives measurements from the SDK. Implementations must be safe for
+// concurrent use; PrometheusMetrics is a ready-made one.
+type Metrics interface {
+	// ObserveRequest is called once per HTTP attempt, including retries.
+	ObserveRequest(m RequestMetric)
+	// ObserveRetry is called each time a failed attempt is about to be retried.
+	ObserveRetry(m RetryMetric)
+	// ObserveJobWait is called when Job.Wait or JobBatch.Wait returns.
+	ObserveJobWait(m JobWaitMetric)
+}
+
+// RequestMetric describes one HTTP attempt.
+type RequestMetric struct {
+	Method   string
+	Path     string
+	Endpoint EndpointClass
+	// Attempt is zero-based; values above zero are retries.
+	Attempt int
+	// StatusCode is zero when the attempt failed at the transport level.
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// RetryMetric describes a retry decision.
+type RetryMetric struct {
+	Method     string
+	Path       string
+	Endpoint   EndpointClass
+	Attempt    int
+	StatusCode int
+	Delay      time.Duration
+}
+
+// JobWaitMetric describes a finished Job.Wait or JobBatch.Wait call.
+type JobWaitMetric struct {
+	// Batch is true for JobBatch.Wait.
+	Batch    bool
+	Jobs     int
+	Duration time.Duration
+	// Err is nil when every job reached a terminal status.
+	Err error
+}
+
+func (c *httpClient) observeRequest(m RequestMetric) {
+	if c.cfg.Metrics != nil {
+		c.cfg.Metrics.ObserveRequest(m)
+	}
+}
+
+func (c *httpClient) observeRetry(m RetryMetric) {
+	if c.cfg.Metrics != nil {
+		c.cfg.Metrics.ObserveRetry(m)
+	}
+}
+
+func (c *httpClient) observeJobWait(m JobWaitMetric) {
+	if c != nil && c.cfg.Metrics != nil {
+		c.cfg.Metrics.ObserveJobWait(m)
+	}
+}
@@ -0,0 +1,93 @@
+package roe
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingMetrics struct {
+	mu       sync.Mutex
+	requests []RequestMetric
+	retries  []RetryMetric
+	waits    []JobWaitMetric
+}
+
+func (m *recordingMetrics) ObserveRequest(r RequestMetric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, r)
+}
+
+func (m *recordingMetrics) ObserveRetry(r RetryMetric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries = append(m.retries, r)
+}
+
+func (m *recordingMetrics) ObserveJobWait(r JobWaitMetric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.waits = append(m.waits, r)
+}
+
+func TestMetricsObserveAttemptsRetriesAndJobWaits(t *testing.T) {
+	statusCalls := 0
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/agents/jobs/job-1/status/":
+			statusCalls++
+			if statusCalls == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{"status": 3}`))
+		case "/v1/agents/jobs/job-1/result/":
+			_, _ = w.Write([]byte(`{"agent_id": "agent-1", "outputs": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	metrics := &recordingMetrics{}
+	client, err := NewClientWithConfig(Config{
+		APIKey:               "k",
+		OrganizationID:       "org",
+		BaseURL:              server.URL,
+		Timeout:              time.Second,
+		MaxRetries:           1,
+		RetryInitialInterval: time.Millisecond,
+		RetryMaxInterval:     time.Millisecond,
+		RetryMultiplier:      1,
+		Metrics:              metrics,
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	job := newJob(client.Agents, "job-1", 0)
+	if _, err := job.Wait(time.Millisecond, time.Second); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+
+	if len(metrics.requests) != 3 {
+		t.Fatalf("expected 3 attempts (failed status, status, result), got %+v", metrics.requests)
+	}
+	failed := metrics.requests[0]
+	if failed.StatusCode != http.StatusServiceUnavailable || failed.Attempt != 0 || failed.Err == nil || failed.Endpoint != EndpointStatus {
+		t.Fatalf("unexpected failed attempt metric: %+v", failed)
+	}
+	if retried := metrics.requests[1]; retried.StatusCode != http.StatusOK || retried.Attempt != 1 || retried.Err != nil {
+		t.Fatalf("unexpected retried attempt metric: %+v", retried)
+	}
+	if len(metrics.retries) != 1 || metrics.retries[0].StatusCode != http.StatusServiceUnavailable || metrics.retries[0].Delay <= 0 {
+		t.Fatalf("unexpected retry metrics: %+v", metrics.retries)
+	}
+	if len(metrics.waits) != 1 || metrics.waits[0].Batch || metrics.waits[0].Jobs != 1 || metrics.waits[0].Err != nil || metrics.waits[0].Duration <= 0 {
+		t.Fatalf("unexpected job wait metrics: %+v", metrics.waits)
+	}
+}
@@ -0,0 +1,293 @@
+package roe
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+)
+
+// errBodyNotReplayable is returned when a retry needs to resend a body that
+// was backed by a one-shot (non-seekable) io.Reader.
+var errBodyNotReplayable = errors.New("request body cannot be replayed")
+
+var errUnknownLength = errors.New("unknown body length")
+
+// requestBody produces a fresh request body for every attempt, so retries can
+// resend uploads without holding them in memory.
+type requestBody struct {
+	open   func() (io.ReadCloser, error)
+	length int64 // -1 when unknown
+}
+
+func bytesBody(b []byte) *requestBody {
+	return &requestBody{
+		open: func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(b)), nil
+		},
+		length: int64(len(b)),
+	}
+}
+
+// multipartFile is a single file part whose content can be reopened for each
+// attempt: paths are re-opened from disk and seekable readers are rewound.
+type multipartFile struct {
+	fieldName string
+	filename  string
+	mimeType  string
+	size      int64 // -1 when unknown
+	open      func() (io.ReadCloser, error)
+	closer    io.Closer // caller-supplied reader, closed once the request is done
+}
+
+func (c *httpClient) prepareMultipartFile(f preparedFile) (*multipartFile, error) {
+	rc, err := f.File.open()
+	if err != nil {
+		return nil, err
+	}
+	part := &multipartFile{
+		fieldName: f.FieldName,
+		filename:  f.File.filename(),
+		mimeType:  f.File.mimeType(),
+		size:      -1,
+	}
+
+	if f.File.Reader == nil {
+		// Opened from Path: sniff and size it now, then re-open on every attempt.
+		defer rc.Close()
+		if st, ok := rc.(interface{ Stat() (os.FileInfo, error) }); ok {
+			if info, err := st.Stat(); err == nil {
+				part.size = info.Size()
+			}
+		}
+		head, err := readHead(rc)
+		if err != nil {
+			return nil, err
+		}
+		part.mimeType = sniffMimeType(head, part.mimeType)
+		part.open = f.File.open
+		return part, nil
+	}
+
+	part.closer = rc
+	if seeker, ok := f.File.Reader.(io.ReadSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			rc.Close()
+			return nil, err
+		}
+		end, err := seeker.Seek(0, io.SeekEnd)
+		if err != nil {
+			rc.Close()
+			return nil, err
+		}
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			rc.Close()
+			return nil, err
+		}
+		head, err := readHead(seeker)
+		if err != nil {
+			rc.Close()
+			return nil, err
+		}
+		part.size = end - start
+		part.mimeType = sniffMimeType(head, part.mimeType)
+		part.open = func() (io.ReadCloser, error) {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return io.NopCloser(seeker), nil
+		}
+		return part, nil
+	}
+
+	// Non-seekable readers can only be sent once; keep the sniffed prefix so
+	// the first attempt still sees the whole stream.
+	head, err := readHead(rc)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	part.mimeType = sniffMimeType(head, part.mimeType)
+	combined := io.MultiReader(bytes.NewReader(head), rc)
+	used := false
+	part.open = func() (io.ReadCloser, error) {
+		if used {
+			return nil, errBodyNotReplayable
+		}
+		used = true
+		return io.NopCloser(combined), nil
+	}
+	return part, nil
+}
+
+// readHead reads up to the 512 bytes http.DetectContentType considers.
+func readHead(r io.Reader) ([]byte, error) {
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+func sniffMimeType(head []byte, fallback string) string {
+	if len(head) == 0 {
+		return fallback
+	}
+	return http.DetectContentType(head)
+}
+
+// multipartBody streams a multipart/form-data payload through an io.Pipe, so
+// memory use stays constant regardless of file size. The boundary is fixed
+// up front so every attempt produces an identical body.
+type multipartBody struct {
+	boundary    string
+	contentType string
+	form        url.Values
+	files       []*multipartFile
+
+	mu      sync.Mutex
+	pipes   []*io.PipeReader
+	writers sync.WaitGroup
+}
+
+func newMultipartBody(form url.Values, files []*multipartFile) *multipartBody {
+	mw := multipart.NewWriter(io.Discard)
+	return &multipartBody{
+		boundary:    mw.Boundary(),
+		contentType: mw.FormDataContentType(),
+		form:        form,
+		files:       files,
+	}
+}
+
+// writeTo renders the body to w; copyFile writes the content of files[i].
+func (b *multipartBody) writeTo(w io.Writer, copyFile func(part io.Writer, i int) error) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(b.boundary); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(b.form))
+	for key := range b.form {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, v := range b.form[key] {
+			if err := mw.WriteField(key, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, f := range b.files {
+		h := make(textproto.MIMEHeader)
+		// Use mime.FormatMediaType to properly escape filename (handles quotes, newlines, etc.)
+		contentDisp := mime.FormatMediaType("form-data", map[string]string{
+			"name":     f.fieldName,
+			"filename": f.filename,
+		})
+		h.Set("Content-Disposition", contentDisp)
+		h.Set("Content-Type", f.mimeType)
+
+		part, err := mw.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		if err := copyFile(part, i); err != nil {
+			return err
+		}
+	}
+
+	return mw.Close()
+}
+
+// length returns the exact encoded size, or -1 when any file size is unknown.
+func (b *multipartBody) length() int64 {
+	counter := &countingWriter{}
+	err := b.writeTo(counter, func(_ io.Writer, i int) error {
+		if b.files[i].size < 0 {
+			return errUnknownLength
+		}
+		counter.n += b.files[i].size
+		return nil
+	})
+	if err != nil {
+		return -1
+	}
+	return counter.n
+}
+
+// open starts a new attempt: every file is (re)opened up front so errors
+// surface synchronously, then the body is encoded on a goroutine.
+func (b *multipartBody) open() (io.ReadCloser, error) {
+	readers := make([]io.ReadCloser, 0, len(b.files))
+	for _, f := range b.files {
+		rc, err := f.open()
+		if err != nil {
+			for _, r := range readers {
+				r.Close()
+			}
+			return nil, err
+		}
+		readers = append(readers, rc)
+	}
+
+	pr, pw := io.Pipe()
+	b.mu.Lock()
+	b.pipes = append(b.pipes, pr)
+	b.mu.Unlock()
+	b.writers.Add(1)
+	go func() {
+		defer b.writers.Done()
+		err := b.writeTo(pw, func(part io.Writer, i int) error {
+			_, err := io.Copy(part, readers[i])
+			return err
+		})
+		for _, r := range readers {
+			r.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
+
+func (b *multipartBody) requestBody() *requestBody {
+	return &requestBody{open: b.open, length: b.length()}
+}
+
+// close stops in-flight encoders by closing every pipe it opened, including
+// bodies that never reached the transport, waits for them to exit and then
+// closes caller-supplied readers.
+func (b *multipartBody) close() {
+	b.mu.Lock()
+	for _, pr := range b.pipes {
+		pr.Close()
+	}
+	b.pipes = nil
+	b.mu.Unlock()
+	b.writers.Wait()
+	for _, f := range b.files {
+		if f.closer != nil {
+			f.closer.Close()
+		}
+	}
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
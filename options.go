@@ -0,0 +1,79 @@
+package roe
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestOption customizes a single API call. Every API method accepts a
+// trailing list of options; they override the client-wide Config for that
+// call only:
+//
+//	client.Tables.Upload(name, file, true, roe.WithTimeout(10*time.Minute))
+//	client.Agents.Delete(agentID, roe.WithMaxRetries(0))
+//
+// RunOptions is also a RequestOption, so agent run calls take both kinds.
+type RequestOption interface {
+	applyRequestOption(*requestOptions)
+}
+
+type requestOptionFunc func(*requestOptions)
+
+func (f requestOptionFunc) applyRequestOption(o *requestOptions) { f(o) }
+
+// WithTimeout overrides Config.Timeout for one call. Like Config.Timeout it
+// bounds each HTTP attempt, not the call as a whole; use a context deadline
+// for that.
+func WithTimeout(timeout time.Duration) RequestOption {
+	return requestOptionFunc(func(o *requestOptions) { o.timeout = timeout })
+}
+
+// WithMaxRetries overrides Config.MaxRetries for one call. Zero sends a
+// single attempt.
+func WithMaxRetries(maxRetries int) RequestOption {
+	return requestOptionFunc(func(o *requestOptions) { o.maxRetries = &maxRetries })
+}
+
+// WithHeader sets a header on one call. It replaces a Config.ExtraHeaders
+// entry of the same name instead of adding a second value.
+func WithHeader(key, value string) RequestOption {
+	return requestOptionFunc(func(o *requestOptions) {
+		if o.headers == nil {
+			o.headers = http.Header{}
+		}
+		o.headers.Add(key, value)
+	})
+}
+
+// WithRequestID sends requestID in Config.RequestIDHeader on every attempt of
+// one call, in place of DefaultRequestID or a generated ID.
+func WithRequestID(requestID string) RequestOption {
+	return requestOptionFunc(func(o *requestOptions) { o.requestID = requestID })
+}
+
+// requestOptions is the resolved form of a call's options.
+type requestOptions struct {
+	timeout    time.Duration
+	maxRetries *int
+	headers    http.Header
+	requestID  string
+	run        *RunOptions
+}
+
+func newRequestOptions(opts []RequestOption) requestOptions {
+	var ro requestOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt.applyRequestOption(&ro)
+		}
+	}
+	return ro
+}
+
+// retries returns the retry budget for the call.
+func (o requestOptions) retries(cfg Config) int {
+	if o.maxRetries != nil && *o.maxRetries >= 0 {
+		return *o.maxRetries
+	}
+	return cfg.MaxRetries
+}
@@ -0,0 +1,114 @@
+package roe
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRequestOptionsOverrideConfigPerCall(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+	var headers []http.Header
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		headers = append(headers, r.Header.Clone())
+		mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithConfig(Config{
+		APIKey:               "k",
+		OrganizationID:       "org",
+		BaseURL:              server.URL,
+		Timeout:              time.Second,
+		MaxRetries:           3,
+		RetryInitialInterval: time.Millisecond,
+		RetryMaxInterval:     time.Millisecond,
+		ExtraHeaders:         http.Header{"X-Tenant": []string{"client"}},
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	err = client.Agents.Delete("agent-1", WithMaxRetries(0), WithHeader("X-Tenant", "call"), WithRequestID("req-42"))
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) {
+		t.Fatalf("expected ServerError, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected WithMaxRetries(0) to send one attempt, got %d", calls)
+	}
+	if got := headers[0].Values("X-Tenant"); len(got) != 1 || got[0] != "call" {
+		t.Fatalf("expected the call header to replace the client one, got %v", got)
+	}
+	if got := headers[0].Get(defaultRequestIDHeader); got != "req-42" {
+		t.Fatalf("expected request ID req-42, got %q", got)
+	}
+
+	calls = 0
+	if _, err := client.Agents.Retrieve("agent-1"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 4 {
+		t.Fatalf("expected options not to leak into later calls (4 attempts), got %d", calls)
+	}
+}
+
+func TestWithTimeoutOverridesClientTimeout(t *testing.T) {
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(150 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"00000000-0000-0000-0000-000000000001","table_name":"t"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithConfig(Config{
+		APIKey:         "k",
+		OrganizationID: "org",
+		BaseURL:        server.URL,
+		Timeout:        50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	file := FileUpload{Reader: strings.NewReader("a,b\n1,2\n"), Filename: "t.csv"}
+	if _, err := client.Tables.Upload("t", file, true, WithTimeout(time.Second)); err != nil {
+		t.Fatalf("expected the longer per-call timeout to apply, got %v", err)
+	}
+	if _, err := client.Tables.List(); err == nil {
+		t.Fatal("expected the client timeout to apply without the option")
+	}
+}
+
+func TestRunOptionsMixWithRequestOptions(t *testing.T) {
+	var got http.Header
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`"job-1"`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithConfig(Config{APIKey: "k", OrganizationID: "org", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Agents.Run("agent-1", 0, map[string]any{"text": "hi"}, nil,
+		RunOptions{SkipCache: true, IdempotencyKey: "key-1"}, WithRequestID("req-7")); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got.Get("X-Skip-Cache") != "true" || got.Get(idempotencyKeyHeader) != "key-1" || got.Get(defaultRequestIDHeader) != "req-7" {
+		t.Fatalf("unexpected headers: %v", got)
+	}
+}
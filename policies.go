@@ -20,12 +20,14 @@ func newPoliciesAPI(cfg Config, httpClient *httpClient) *PoliciesAPI {
 }
 
 // List returns paginated policies.
-func (p *PoliciesAPI) List(page, pageSize int) (PaginatedResponse[Policy], error) {
-	return p.ListWithContext(context.Background(), page, pageSize)
+func (p *PoliciesAPI) List(page, pageSize int, opts ...RequestOption) (PaginatedResponse[Policy], error) {
+	return p.ListWithContext(context.Background(), page, pageSize, opts...)
 }
 
 // ListWithContext returns paginated policies with a caller-supplied context.
-func (p *PoliciesAPI) ListWithContext(ctx context.Context, page, pageSize int) (PaginatedResponse[Policy], error) {
+func (p *PoliciesAPI) ListWithContext(ctx context.Context, page, pageSize int, opts ...RequestOption) (_ PaginatedResponse[Policy], err error) {
+	ctx, span := p.httpClient.startSpan(ctx, "PoliciesAPI.List")
+	defer endSpan(span, &err)
 	params := map[string]string{
 		"organization_id": p.cfg.OrganizationID,
 	}
@@ -36,36 +38,40 @@ func (p *PoliciesAPI) ListWithContext(ctx context.Context, page, pageSize int) (
 		params["page_size"] = fmt.Sprintf("%d", pageSize)
 	}
 	var resp PaginatedResponse[Policy]
-	if err := p.httpClient.getWithContext(ctx, "/v1/policies/", params, &resp); err != nil {
+	if err := p.httpClient.getWithContext(ctx, "/v1/policies/", params, &resp, opts...); err != nil {
 		return PaginatedResponse[Policy]{}, err
 	}
 	return resp, nil
 }
 
 // Retrieve fetches a policy by ID.
-func (p *PoliciesAPI) Retrieve(policyID string) (Policy, error) {
-	return p.RetrieveWithContext(context.Background(), policyID)
+func (p *PoliciesAPI) Retrieve(policyID string, opts ...RequestOption) (Policy, error) {
+	return p.RetrieveWithContext(context.Background(), policyID, opts...)
 }
 
 // RetrieveWithContext fetches a policy by ID with a caller-supplied context.
-func (p *PoliciesAPI) RetrieveWithContext(ctx context.Context, policyID string) (Policy, error) {
+func (p *PoliciesAPI) RetrieveWithContext(ctx context.Context, policyID string, opts ...RequestOption) (_ Policy, err error) {
+	ctx, span := p.httpClient.startSpan(ctx, "PoliciesAPI.Retrieve")
+	defer endSpan(span, &err)
 	if policyID == "" {
 		return Policy{}, fmt.Errorf("policyID cannot be empty")
 	}
 	var resp Policy
-	if err := p.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/policies/%s/", policyID), nil, &resp); err != nil {
+	if err := p.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/policies/%s/", policyID), nil, &resp, opts...); err != nil {
 		return Policy{}, err
 	}
 	return resp, nil
 }
 
 // Create creates a new policy with an initial version.
-func (p *PoliciesAPI) Create(name string, content map[string]any, description string, versionName string) (Policy, error) {
-	return p.CreateWithContext(context.Background(), name, content, description, versionName)
+func (p *PoliciesAPI) Create(name string, content map[string]any, description string, versionName string, opts ...RequestOption) (Policy, error) {
+	return p.CreateWithContext(context.Background(), name, content, description, versionName, opts...)
 }
 
 // CreateWithContext creates a new policy with a caller-supplied context.
-func (p *PoliciesAPI) CreateWithContext(ctx context.Context, name string, content map[string]any, description string, versionName string) (Policy, error) {
+func (p *PoliciesAPI) CreateWithContext(ctx context.Context, name string, content map[string]any, description string, versionName string, opts ...RequestOption) (_ Policy, err error) {
+	ctx, span := p.httpClient.startSpan(ctx, "PoliciesAPI.Create")
+	defer endSpan(span, &err)
 	payload := map[string]any{
 		"name":            name,
 		"content":         content,
@@ -76,19 +82,21 @@ func (p *PoliciesAPI) CreateWithContext(ctx context.Context, name string, conten
 		payload["version_name"] = versionName
 	}
 	var resp Policy
-	if err := p.httpClient.postJSONWithContext(ctx, "/v1/policies/", payload, nil, &resp); err != nil {
+	if err := p.httpClient.postJSONWithContext(ctx, "/v1/policies/", payload, nil, &resp, opts...); err != nil {
 		return Policy{}, err
 	}
 	return resp, nil
 }
 
 // Update updates a policy's metadata. Pass nil for fields you don't want to change.
-func (p *PoliciesAPI) Update(policyID string, name *string, description *string) (Policy, error) {
-	return p.UpdateWithContext(context.Background(), policyID, name, description)
+func (p *PoliciesAPI) Update(policyID string, name *string, description *string, opts ...RequestOption) (Policy, error) {
+	return p.UpdateWithContext(context.Background(), policyID, name, description, opts...)
 }
 
 // UpdateWithContext updates a policy with a caller-supplied context.
-func (p *PoliciesAPI) UpdateWithContext(ctx context.Context, policyID string, name *string, description *string) (Policy, error) {
+func (p *PoliciesAPI) UpdateWithContext(ctx context.Context, policyID string, name *string, description *string, opts ...RequestOption) (_ Policy, err error) {
+	ctx, span := p.httpClient.startSpan(ctx, "PoliciesAPI.Update")
+	defer endSpan(span, &err)
 	if policyID == "" {
 		return Policy{}, fmt.Errorf("policyID cannot be empty")
 	}
@@ -100,19 +108,21 @@ func (p *PoliciesAPI) UpdateWithContext(ctx context.Context, policyID string, na
 		payload["description"] = *description
 	}
 	var resp Policy
-	if err := p.httpClient.patchJSONWithContext(ctx, fmt.Sprintf("/v1/policies/%s/", policyID), payload, nil, &resp); err != nil {
+	if err := p.httpClient.patchJSONWithContext(ctx, fmt.Sprintf("/v1/policies/%s/", policyID), payload, nil, &resp, opts...); err != nil {
 		return Policy{}, err
 	}
 	return resp, nil
 }
 
 // Replace replaces a policy's metadata.
-func (p *PoliciesAPI) Replace(policyID string, name string, description string) (Policy, error) {
-	return p.ReplaceWithContext(context.Background(), policyID, name, description)
+func (p *PoliciesAPI) Replace(policyID string, name string, description string, opts ...RequestOption) (Policy, error) {
+	return p.ReplaceWithContext(context.Background(), policyID, name, description, opts...)
 }
 
 // ReplaceWithContext replaces a policy's metadata with a caller-supplied context.
-func (p *PoliciesAPI) ReplaceWithContext(ctx context.Context, policyID string, name string, description string) (Policy, error) {
+func (p *PoliciesAPI) ReplaceWithContext(ctx context.Context, policyID string, name string, description string, opts ...RequestOption) (_ Policy, err error) {
+	ctx, span := p.httpClient.startSpan(ctx, "PoliciesAPI.Replace")
+	defer endSpan(span, &err)
 	if policyID == "" {
 		return Policy{}, fmt.Errorf("policyID cannot be empty")
 	}
@@ -121,23 +131,25 @@ func (p *PoliciesAPI) ReplaceWithContext(ctx context.Context, policyID string, n
 		"description": description,
 	}
 	var resp Policy
-	if err := p.httpClient.putJSONWithContext(ctx, fmt.Sprintf("/v1/policies/%s/", policyID), payload, nil, &resp); err != nil {
+	if err := p.httpClient.putJSONWithContext(ctx, fmt.Sprintf("/v1/policies/%s/", policyID), payload, nil, &resp, opts...); err != nil {
 		return Policy{}, err
 	}
 	return resp, nil
 }
 
 // Delete removes a policy and all its versions.
-func (p *PoliciesAPI) Delete(policyID string) error {
-	return p.DeleteWithContext(context.Background(), policyID)
+func (p *PoliciesAPI) Delete(policyID string, opts ...RequestOption) error {
+	return p.DeleteWithContext(context.Background(), policyID, opts...)
 }
 
 // DeleteWithContext removes a policy with a caller-supplied context.
-func (p *PoliciesAPI) DeleteWithContext(ctx context.Context, policyID string) error {
+func (p *PoliciesAPI) DeleteWithContext(ctx context.Context, policyID string, opts ...RequestOption) (err error) {
+	ctx, span := p.httpClient.startSpan(ctx, "PoliciesAPI.Delete")
+	defer endSpan(span, &err)
 	if policyID == "" {
 		return fmt.Errorf("policyID cannot be empty")
 	}
-	return p.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/policies/%s/", policyID), nil)
+	return p.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/policies/%s/", policyID), nil, opts...)
 }
 
 // PolicyVersionsAPI handles policy version operations.
@@ -146,17 +158,19 @@ type PolicyVersionsAPI struct {
 }
 
 // List returns all versions of a policy.
-func (v *PolicyVersionsAPI) List(policyID string) ([]PolicyVersion, error) {
-	return v.ListWithContext(context.Background(), policyID)
+func (v *PolicyVersionsAPI) List(policyID string, opts ...RequestOption) ([]PolicyVersion, error) {
+	return v.ListWithContext(context.Background(), policyID, opts...)
 }
 
 // ListWithContext returns all versions of a policy with a caller-supplied context.
-func (v *PolicyVersionsAPI) ListWithContext(ctx context.Context, policyID string) ([]PolicyVersion, error) {
+func (v *PolicyVersionsAPI) ListWithContext(ctx context.Context, policyID string, opts ...RequestOption) (_ []PolicyVersion, err error) {
+	ctx, span := v.policiesAPI.httpClient.startSpan(ctx, "PolicyVersionsAPI.List")
+	defer endSpan(span, &err)
 	if policyID == "" {
 		return nil, fmt.Errorf("policyID cannot be empty")
 	}
 	// The endpoint may return either a paginated response or a raw array.
-	raw, err := v.policiesAPI.httpClient.getBytesWithContext(ctx, fmt.Sprintf("/v1/policies/%s/versions/", policyID), nil)
+	raw, err := v.policiesAPI.httpClient.getBytesWithContext(ctx, fmt.Sprintf("/v1/policies/%s/versions/", policyID), nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -179,12 +193,14 @@ func (v *PolicyVersionsAPI) ListWithContext(ctx context.Context, policyID string
 }
 
 // Retrieve fetches a specific policy version.
-func (v *PolicyVersionsAPI) Retrieve(policyID, versionID string) (PolicyVersion, error) {
-	return v.RetrieveWithContext(context.Background(), policyID, versionID)
+func (v *PolicyVersionsAPI) Retrieve(policyID, versionID string, opts ...RequestOption) (PolicyVersion, error) {
+	return v.RetrieveWithContext(context.Background(), policyID, versionID, opts...)
 }
 
 // RetrieveWithContext fetches a specific policy version with a caller-supplied context.
-func (v *PolicyVersionsAPI) RetrieveWithContext(ctx context.Context, policyID, versionID string) (PolicyVersion, error) {
+func (v *PolicyVersionsAPI) RetrieveWithContext(ctx context.Context, policyID, versionID string, opts ...RequestOption) (_ PolicyVersion, err error) {
+	ctx, span := v.policiesAPI.httpClient.startSpan(ctx, "PolicyVersionsAPI.Retrieve")
+	defer endSpan(span, &err)
 	if policyID == "" {
 		return PolicyVersion{}, fmt.Errorf("policyID cannot be empty")
 	}
@@ -192,19 +208,21 @@ func (v *PolicyVersionsAPI) RetrieveWithContext(ctx context.Context, policyID, v
 		return PolicyVersion{}, fmt.Errorf("versionID cannot be empty")
 	}
 	var resp PolicyVersion
-	if err := v.policiesAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/policies/%s/versions/%s/", policyID, versionID), nil, &resp); err != nil {
+	if err := v.policiesAPI.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/policies/%s/versions/%s/", policyID, versionID), nil, &resp, opts...); err != nil {
 		return PolicyVersion{}, err
 	}
 	return resp, nil
 }
 
 // Create creates a new policy version. The new version automatically becomes current.
-func (v *PolicyVersionsAPI) Create(policyID string, content map[string]any, versionName string, baseVersionID string) (PolicyVersion, error) {
-	return v.CreateWithContext(context.Background(), policyID, content, versionName, baseVersionID)
+func (v *PolicyVersionsAPI) Create(policyID string, content map[string]any, versionName string, baseVersionID string, opts ...RequestOption) (PolicyVersion, error) {
+	return v.CreateWithContext(context.Background(), policyID, content, versionName, baseVersionID, opts...)
 }
 
 // CreateWithContext creates a new policy version with a caller-supplied context.
-func (v *PolicyVersionsAPI) CreateWithContext(ctx context.Context, policyID string, content map[string]any, versionName string, baseVersionID string) (PolicyVersion, error) {
+func (v *PolicyVersionsAPI) CreateWithContext(ctx context.Context, policyID string, content map[string]any, versionName string, baseVersionID string, opts ...RequestOption) (_ PolicyVersion, err error) {
+	ctx, span := v.policiesAPI.httpClient.startSpan(ctx, "PolicyVersionsAPI.Create")
+	defer endSpan(span, &err)
 	if policyID == "" {
 		return PolicyVersion{}, fmt.Errorf("policyID cannot be empty")
 	}
@@ -221,11 +239,11 @@ func (v *PolicyVersionsAPI) CreateWithContext(ctx context.Context, policyID stri
 	var respID struct {
 		ID string `json:"id"`
 	}
-	if err := v.policiesAPI.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/policies/%s/versions/", policyID), payload, nil, &respID); err != nil {
+	if err := v.policiesAPI.httpClient.postJSONWithContext(ctx, fmt.Sprintf("/v1/policies/%s/versions/", policyID), payload, nil, &respID, opts...); err != nil {
 		return PolicyVersion{}, err
 	}
 	if respID.ID == "" {
 		return PolicyVersion{}, fmt.Errorf("unexpected response: missing version ID")
 	}
-	return v.RetrieveWithContext(ctx, policyID, respID.ID)
+	return v.RetrieveWithContext(ctx, policyID, respID.ID, opts...)
 }
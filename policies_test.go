@@ -2,6 +2,7 @@ package roe
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"testing"
@@ -291,6 +292,38 @@ func TestPoliciesAPIDelete(t *testing.T) {
 	}
 }
 
+func TestPoliciesDeleteInUseReturnsDependentAgents(t *testing.T) {
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error":"Policy is in use by 1 agent(s).","dependent_agents":[{"base_agent_id":"a1","agent_name":"Claims triage","version_id":"v3","version_name":"v3"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithConfig(Config{APIKey: "k", OrganizationID: "org", BaseURL: server.URL, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	err = client.Policies.Delete("p1")
+	var inUse *ResourceInUseError
+	if !errors.As(err, &inUse) {
+		t.Fatalf("expected *ResourceInUseError, got %T: %v", err, err)
+	}
+	want := DependentAgent{BaseAgentID: "a1", AgentName: "Claims triage", VersionID: "v3", VersionName: "v3"}
+	if len(inUse.DependentAgents) != 1 || inUse.DependentAgents[0] != want {
+		t.Fatalf("unexpected dependents %+v", inUse.DependentAgents)
+	}
+	if inUse.Message != "Policy is in use by 1 agent(s)." {
+		t.Fatalf("unexpected message %q", inUse.Message)
+	}
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.StatusCode != http.StatusConflict {
+		t.Fatalf("expected the error to match *ConflictError, got %v", err)
+	}
+}
+
 func TestPolicyVersionsListPaginated(t *testing.T) {
 	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.HasPrefix(r.URL.Path, "/v1/policies/p1/versions/") {
@@ -0,0 +1,159 @@
+package roe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// PollSchedule decides how long Job and JobBatch waits pause between status
+// polls. It is used when the interval passed to Wait or Watch is zero; a
+// positive interval still polls at that fixed rate.
+type PollSchedule interface {
+	// Next returns the pause after the poll with zero-based index poll,
+	// elapsed since the wait started.
+	Next(poll int, elapsed time.Duration) time.Duration
+}
+
+// PollScheduleFunc adapts a function to the PollSchedule interface.
+type PollScheduleFunc func(poll int, elapsed time.Duration) time.Duration
+
+// Next calls f(poll, elapsed).
+func (f PollScheduleFunc) Next(poll int, elapsed time.Duration) time.Duration {
+	return f(poll, elapsed)
+}
+
+// FixedPollSchedule polls every interval.
+func FixedPollSchedule(interval time.Duration) PollSchedule {
+	return PollScheduleFunc(func(int, time.Duration) time.Duration { return interval })
+}
+
+// BackoffPollSchedule polls quickly at first, so short jobs return promptly,
+// then backs off exponentially so long jobs are not polled every few seconds
+// for hours. Zero fields take the defaults shown.
+type BackoffPollSchedule struct {
+	Initial    time.Duration // 250ms
+	Max        time.Duration // 30s
+	Multiplier float64       // 1.5
+}
+
+// DefaultPollSchedule is used when neither Config.PollSchedule nor a job's
+// WithPollSchedule is set.
+var DefaultPollSchedule PollSchedule = BackoffPollSchedule{}
+
+// Next implements PollSchedule.
+func (s BackoffPollSchedule) Next(poll int, _ time.Duration) time.Duration {
+	initial, maxWait, multiplier := s.Initial, s.Max, s.Multiplier
+	if initial <= 0 {
+		initial = 250 * time.Millisecond
+	}
+	if maxWait <= 0 {
+		maxWait = 30 * time.Second
+	}
+	if multiplier < 1 {
+		multiplier = 1.5
+	}
+	wait := float64(initial) * math.Pow(multiplier, float64(poll))
+	if wait > float64(maxWait) {
+		return maxWait
+	}
+	return time.Duration(wait)
+}
+
+// ExpectedDurationPollSchedule suits jobs whose typical run time is known,
+// for example from AgentJobsAPI.EstimateDuration. It polls sparsely while the
+// job should still be running, waiting half the remaining expected time, and
+// polls quickly around the expected finish, backing off again the more
+// overdue the job is. Waits stay between Min (250ms) and Max (30s).
+type ExpectedDurationPollSchedule struct {
+	Expected time.Duration
+	Min      time.Duration
+	Max      time.Duration
+}
+
+// Next implements PollSchedule.
+func (s ExpectedDurationPollSchedule) Next(_ int, elapsed time.Duration) time.Duration {
+	minWait, maxWait := s.Min, s.Max
+	if minWait <= 0 {
+		minWait = 250 * time.Millisecond
+	}
+	if maxWait <= 0 {
+		maxWait = 30 * time.Second
+	}
+	wait := (elapsed - s.Expected) / 4
+	if elapsed < s.Expected {
+		wait = (s.Expected - elapsed) / 2
+	}
+	return min(max(wait, minWait), maxWait)
+}
+
+// EstimateDuration returns the median run time of the agent's most recent
+// successful jobs (up to 50), for use with ExpectedDurationPollSchedule. It
+// returns zero when the agent has no finished jobs with a recorded duration.
+func (j *AgentJobsAPI) EstimateDuration(agentID string, opts ...RequestOption) (time.Duration, error) {
+	return j.EstimateDurationWithContext(context.Background(), agentID, opts...)
+}
+
+// EstimateDurationWithContext is EstimateDuration with a caller-supplied context.
+func (j *AgentJobsAPI) EstimateDurationWithContext(ctx context.Context, agentID string, opts ...RequestOption) (time.Duration, error) {
+	page, err := j.ListJobsWithContext(ctx, agentID, 1, 50, fmt.Sprint(int(JobSuccess)), "", "", "", "", "", "-created_at", opts...)
+	if err != nil {
+		return 0, fmt.Errorf("estimate duration for agent %s: %w", agentID, err)
+	}
+	var durations []int
+	for _, job := range page.Results {
+		if job.DurationMs != nil && *job.DurationMs > 0 {
+			durations = append(durations, *job.DurationMs)
+		}
+	}
+	if len(durations) == 0 {
+		return 0, nil
+	}
+	sort.Ints(durations)
+	return time.Duration(durations[len(durations)/2]) * time.Millisecond, nil
+}
+
+// resolvePollSchedule picks the schedule for a wait: a fixed interval when
+// one is given, else the job's schedule, the client's, or the default.
+func resolvePollSchedule(interval time.Duration, schedule PollSchedule, api *AgentsAPI) PollSchedule {
+	switch {
+	case interval > 0:
+		return FixedPollSchedule(interval)
+	case schedule != nil:
+		return schedule
+	case api != nil && api.cfg.PollSchedule != nil:
+		return api.cfg.PollSchedule
+	default:
+		return DefaultPollSchedule
+	}
+}
+
+// pollRateLimited reports whether a status poll failed only because it was
+// rate limited, in which case the wait pauses and polls again.
+func pollRateLimited(err error) (*RateLimitError, bool) {
+	var rateLimit *RateLimitError
+	if err == nil || !errors.As(err, &rateLimit) {
+		return nil, false
+	}
+	return rateLimit, true
+}
+
+// pausePolling sleeps for the schedule's next wait, or for the Retry-After of
+// a rate-limited poll when that is longer.
+func pausePolling(ctx context.Context, schedule PollSchedule, poll int, start time.Time, rateLimit *RateLimitError) error {
+	delay := schedule.Next(poll, time.Since(start))
+	if rateLimit != nil && rateLimit.RetryAfter != nil && *rateLimit.RetryAfter > delay {
+		delay = *rateLimit.RetryAfter
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
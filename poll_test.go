@@ -0,0 +1,109 @@
+package roe
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPollSchedules(t *testing.T) {
+	backoff := BackoffPollSchedule{}
+	for poll, want := range map[int]time.Duration{0: 250 * time.Millisecond, 1: 375 * time.Millisecond, 40: 30 * time.Second} {
+		if got := backoff.Next(poll, 0); got != want {
+			t.Errorf("backoff poll %d: expected %s, got %s", poll, want, got)
+		}
+	}
+
+	expected := ExpectedDurationPollSchedule{Expected: 10 * time.Second}
+	for elapsed, want := range map[time.Duration]time.Duration{
+		0:                       5 * time.Second,
+		9900 * time.Millisecond: 250 * time.Millisecond,
+		30 * time.Second:        5 * time.Second,
+		time.Hour:               30 * time.Second,
+	} {
+		if got := expected.Next(0, elapsed); got != want {
+			t.Errorf("expected-duration at %s: expected %s, got %s", elapsed, want, got)
+		}
+	}
+}
+
+func TestJobWaitUsesConfiguredScheduleAndSurvivesRateLimits(t *testing.T) {
+	var mu sync.Mutex
+	statusCalls := 0
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/result/") {
+			_, _ = w.Write([]byte(`{"agent_id":"a","agent_version_id":"v","inputs":[],"input_tokens":0,"output_tokens":0,"outputs":[{"key":"out","value":"done","description":"","data_type":"text/plain"}]}`))
+			return
+		}
+		mu.Lock()
+		statusCalls++
+		call := statusCalls
+		mu.Unlock()
+		switch call {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"detail":"slow down"}`))
+		case 2:
+			_ = json.NewEncoder(w).Encode(AgentJobStatus{Status: JobStarted})
+		default:
+			_ = json.NewEncoder(w).Encode(AgentJobStatus{Status: JobSuccess})
+		}
+	}))
+	defer server.Close()
+
+	var polls []int
+	cfg := Config{
+		APIKey:         "k",
+		OrganizationID: "org",
+		BaseURL:        server.URL,
+		Timeout:        time.Second,
+		PollSchedule: PollScheduleFunc(func(poll int, _ time.Duration) time.Duration {
+			polls = append(polls, poll)
+			return time.Millisecond
+		}),
+	}
+	client := newHTTPClient(cfg, newAuth(cfg))
+	defer client.close()
+
+	result, err := newJob(newAgentsAPI(cfg, client), "job-1", 0).Wait(0, time.Second)
+	if err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if !result.Succeeded() || result.Outputs[0].Value != "done" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(polls) != 2 || polls[0] != 0 || polls[1] != 1 {
+		t.Fatalf("expected the configured schedule after each non-terminal poll, got %v", polls)
+	}
+}
+
+func TestEstimateDurationUsesMedianOfSuccessfulJobs(t *testing.T) {
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("status_code") != "3" || query.Get("ordering") != "-created_at" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"count":4,"results":[{"duration_ms":100},{"duration_ms":300},{"duration_ms":null},{"duration_ms":200}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithConfig(Config{APIKey: "k", OrganizationID: "org", BaseURL: server.URL, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	got, err := client.Agents.Jobs.EstimateDuration("agent-1")
+	if err != nil {
+		t.Fatalf("estimate: %v", err)
+	}
+	if got != 200*time.Millisecond {
+		t.Fatalf("expected 200ms, got %s", got)
+	}
+}
@@ -0,0 +1,210 @@
+package roe
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// jobPoller is the shared poller behind Config.SharedJobPolling. Job waits
+// register with it, and one loop polls every registered job together through
+// the batch status and result endpoints, handing each result back to its
+// waiters. The loop runs only while jobs are waiting, on a context of its own
+// that is cancelled as soon as the last waiter leaves, so its in-flight
+// requests do not outlive the waits that needed them.
+type jobPoller struct {
+	api      *AgentsAPI
+	interval time.Duration
+
+	mu      sync.Mutex
+	waiters map[string][]*jobWaiter
+	running bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+type jobWaiter struct {
+	done chan jobOutcome
+}
+
+type jobOutcome struct {
+	result AgentJobResult
+	err    error
+}
+
+func newJobPoller(api *AgentsAPI, interval time.Duration) *jobPoller {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &jobPoller{api: api, interval: interval, waiters: map[string][]*jobWaiter{}}
+}
+
+// wait blocks until the shared loop reports jobID's result or ctx ends.
+func (p *jobPoller) wait(ctx context.Context, jobID string) (AgentJobResult, error) {
+	waiter := &jobWaiter{done: make(chan jobOutcome, 1)}
+	p.mu.Lock()
+	p.waiters[jobID] = append(p.waiters[jobID], waiter)
+	if p.ctx == nil || p.ctx.Err() != nil {
+		p.ctx, p.cancel = context.WithCancel(context.Background())
+	}
+	if !p.running {
+		p.running = true
+		go p.loop()
+	}
+	p.mu.Unlock()
+
+	select {
+	case outcome := <-waiter.done:
+		return outcome.result, outcome.err
+	case <-ctx.Done():
+		p.remove(jobID, waiter)
+		return AgentJobResult{}, fmt.Errorf("job %s wait cancelled: %w", jobID, ctx.Err())
+	}
+}
+
+func (p *jobPoller) remove(jobID string, waiter *jobWaiter) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	waiters := p.waiters[jobID]
+	for i, w := range waiters {
+		if w == waiter {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(p.waiters, jobID)
+	} else {
+		p.waiters[jobID] = waiters
+	}
+	if len(p.waiters) == 0 {
+		p.cancel()
+	}
+}
+
+// deliver hands outcome to every waiter on jobID and forgets the job.
+func (p *jobPoller) deliver(jobID string, outcome jobOutcome) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, waiter := range p.waiters[jobID] {
+		waiter.done <- outcome
+	}
+	delete(p.waiters, jobID)
+}
+
+func (p *jobPoller) loop() {
+	schedule := FixedPollSchedule(p.interval)
+	start := time.Now()
+	for poll := 0; ; poll++ {
+		p.mu.Lock()
+		if len(p.waiters) == 0 {
+			p.running = false
+			p.cancel()
+			p.mu.Unlock()
+			return
+		}
+		ctx := p.ctx
+		jobIDs := make([]string, 0, len(p.waiters))
+		for id := range p.waiters {
+			jobIDs = append(jobIDs, id)
+		}
+		p.mu.Unlock()
+
+		var rateLimit *RateLimitError
+		for _, chunk := range chunkStrings(jobIDs, maxBatchSize) {
+			if limited := p.pollChunk(ctx, chunk); limited != nil {
+				rateLimit = limited
+			}
+		}
+		// A cancelled ctx means every waiter left; the next round exits or
+		// picks up jobs registered since on a fresh context.
+		_ = pausePolling(ctx, schedule, poll, start, rateLimit)
+	}
+}
+
+// pollChunk fetches the statuses of up to maxBatchSize jobs and delivers the
+// results of those that finished. A failed request fails the waiters of
+// every job in the chunk, except when it was rate limited: then the chunk is
+// polled again next round and the RateLimitError is returned. Requests
+// aborted because ctx was cancelled fail no one, since their waiters left.
+func (p *jobPoller) pollChunk(ctx context.Context, jobIDs []string) *RateLimitError {
+	client := p.api.httpClient
+	var statuses []AgentJobStatusBatch
+	err := client.postJSONWithContext(ctx, "/v1/agents/jobs/statuses/", map[string]any{"job_ids": jobIDs}, nil, &statuses)
+	if rateLimit, limited := pollRateLimited(err); limited {
+		return rateLimit
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		for _, id := range jobIDs {
+			p.deliver(id, jobOutcome{err: fmt.Errorf("retrieve job statuses: %w", err)})
+		}
+		return nil
+	}
+
+	received := make(map[string]AgentJobStatus, len(statuses))
+	listed := make(map[string]bool, len(statuses))
+	var ready []string
+	for _, st := range statuses {
+		// A job listed without a status is not known yet and stays pending,
+		// as in JobBatch waits.
+		listed[st.ID] = true
+		if st.Status == nil {
+			continue
+		}
+		status := AgentJobStatus{Status: *st.Status, ErrorMessage: st.ErrorMessage}
+		if st.Timestamp != nil {
+			status.Timestamp = *st.Timestamp
+		}
+		received[st.ID] = status
+		if st.Status.IsTerminal() {
+			ready = append(ready, st.ID)
+		}
+	}
+	for _, id := range jobIDs {
+		if !listed[id] {
+			p.deliver(id, jobOutcome{err: fmt.Errorf("job %s not found in status response", id)})
+		}
+	}
+	if len(ready) == 0 {
+		return nil
+	}
+
+	var results []AgentJobResultBatch
+	err = client.postJSONWithContext(ctx, "/v1/agents/jobs/results/", map[string]any{"job_ids": ready}, nil, &results)
+	if rateLimit, limited := pollRateLimited(err); limited {
+		return rateLimit
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	fetched := make(map[string]AgentJobResultBatch, len(results))
+	for _, res := range results {
+		fetched[res.ID] = res
+	}
+	for _, id := range ready {
+		status := received[id]
+		var outcome jobOutcome
+		res, ok := fetched[id]
+		switch {
+		case ok:
+			outcome.result, outcome.err = convertBatchResult(res)
+		case status.Status == JobFailure || status.Status == JobCancelled:
+			// As in Job.Wait, a missing result is not fatal for failed or
+			// cancelled jobs.
+		case err != nil:
+			outcome.err = fmt.Errorf("retrieve job results: %w", err)
+		default:
+			outcome.err = fmt.Errorf("job %s result missing in batch response", id)
+		}
+		if outcome.err == nil {
+			outcome.result.Status = &status.Status
+			outcome.result.ErrorMessage = status.ErrorMessage
+		}
+		p.deliver(id, outcome)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package roe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSharedJobPollingMultiplexesWaits(t *testing.T) {
+	var statusRequests, singleRequests atomic.Int32
+	var mu sync.Mutex
+	rounds := map[string]int{}
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var payload struct {
+			JobIDs []string `json:"job_ids"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/statuses/"):
+			statusRequests.Add(1)
+			mu.Lock()
+			defer mu.Unlock()
+			statuses := make([]AgentJobStatusBatch, 0, len(payload.JobIDs))
+			for _, id := range payload.JobIDs {
+				if id == "missing" {
+					continue
+				}
+				rounds[id]++
+				status := JobStarted
+				if rounds[id] >= 2 {
+					status = JobSuccess
+				}
+				statuses = append(statuses, AgentJobStatusBatch{ID: id, Status: &status})
+			}
+			_ = json.NewEncoder(w).Encode(statuses)
+		case strings.HasSuffix(r.URL.Path, "/results/"):
+			agentID, versionID := "agent", "v1"
+			results := make([]AgentJobResultBatch, 0, len(payload.JobIDs))
+			for _, id := range payload.JobIDs {
+				results = append(results, AgentJobResultBatch{
+					ID:             id,
+					AgentID:        &agentID,
+					AgentVersionID: &versionID,
+					Result:         []any{map[string]any{"key": "out", "value": id, "description": "", "data_type": "text/plain"}},
+				})
+			}
+			_ = json.NewEncoder(w).Encode(results)
+		default:
+			singleRequests.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithConfig(Config{
+		APIKey:             "k",
+		OrganizationID:     "org",
+		BaseURL:            server.URL,
+		Timeout:            time.Second,
+		SharedJobPolling:   true,
+		SharedPollInterval: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	const waiters = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, waiters)
+	for i := range waiters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := fmt.Sprintf("job-%d", i)
+			result, err := newJob(client.Agents, id, 0).Wait(time.Millisecond, 5*time.Second)
+			if err != nil {
+				errs <- fmt.Errorf("%s: %w", id, err)
+				return
+			}
+			if !result.Succeeded() || result.Outputs[0].Value != id {
+				errs <- fmt.Errorf("%s: unexpected result %+v", id, result)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if singleRequests.Load() != 0 {
+		t.Fatalf("expected no per-job requests, got %d", singleRequests.Load())
+	}
+	// Each job needs two status rounds; registrations that land between
+	// rounds may add a few more, but far fewer than one request per job.
+	if n := statusRequests.Load(); n > 10 {
+		t.Fatalf("expected status polls to be shared, got %d requests for %d jobs", n, waiters)
+	}
+
+	if _, err := newJob(client.Agents, "missing", 0).Wait(0, time.Second); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected a not-found error for an unknown job, got %v", err)
+	}
+}
+
+func TestSharedJobPollingKeepsUnknownJobsPendingAndAbortsWhenWaitersLeave(t *testing.T) {
+	var statusRequests atomic.Int32
+	aborted := make(chan struct{})
+	block := make(chan struct{})
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var payload struct {
+			JobIDs []string `json:"job_ids"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		if strings.HasSuffix(r.URL.Path, "/results/") {
+			agentID, versionID := "agent", "v1"
+			_ = json.NewEncoder(w).Encode([]AgentJobResultBatch{{ID: payload.JobIDs[0], AgentID: &agentID, AgentVersionID: &versionID, Result: []any{}}})
+			return
+		}
+		if payload.JobIDs[0] == "slow" {
+			select {
+			case <-r.Context().Done():
+				close(aborted)
+			case <-block:
+			}
+			return
+		}
+		// The job is listed without a status until the third poll.
+		var status *JobStatus
+		if statusRequests.Add(1) >= 3 {
+			success := JobSuccess
+			status = &success
+		}
+		_ = json.NewEncoder(w).Encode([]AgentJobStatusBatch{{ID: payload.JobIDs[0], Status: status}})
+	}))
+	defer server.Close()
+	defer close(block)
+
+	client, err := NewClientWithConfig(Config{
+		APIKey:             "k",
+		OrganizationID:     "org",
+		BaseURL:            server.URL,
+		Timeout:            5 * time.Second,
+		SharedJobPolling:   true,
+		SharedPollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	result, err := newJob(client.Agents, "queued", 0).Wait(0, time.Second)
+	if err != nil || !result.Succeeded() {
+		t.Fatalf("expected the job without a status to keep being polled, got %+v (%v)", result, err)
+	}
+
+	if _, err := newJob(client.Agents, "slow", 0).Wait(0, 50*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to time out, got %v", err)
+	}
+	select {
+	case <-aborted:
+	case <-time.After(time.Second):
+		t.Fatal("expected the in-flight status request to be aborted once its only waiter left")
+	}
+}
@@ -0,0 +1,286 @@
+package roe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A config file holds named profiles:
+//
+//	default_profile: staging
+//	profiles:
+//	  staging:
+//	    base_url: https://staging.api.roe-ai.com
+//	    organization_id: 6f1c...
+//	    api_key_file: ~/.secrets/roe-staging   # or api_key_env / api_key
+//	    timeout: 30s
+//	    max_retries: 5
+//	    retry_initial_interval: 100ms
+//	    proxy: http://proxy.internal:3128
+//	    extra_headers:
+//	      X-Team: data
+type configFile struct {
+	DefaultProfile string                   `yaml:"default_profile"`
+	Profiles       map[string]profileConfig `yaml:"profiles"`
+}
+
+type profileConfig struct {
+	BaseURL        string `yaml:"base_url"`
+	OrganizationID string `yaml:"organization_id"`
+
+	// Key source: at most one of these.
+	APIKey     string `yaml:"api_key"`
+	APIKeyEnv  string `yaml:"api_key_env"`
+	APIKeyFile string `yaml:"api_key_file"`
+
+	Timeout              time.Duration     `yaml:"timeout"`
+	MaxRetries           *int              `yaml:"max_retries"`
+	RetryInitialInterval time.Duration     `yaml:"retry_initial_interval"`
+	RetryMaxInterval     time.Duration     `yaml:"retry_max_interval"`
+	RetryMultiplier      *float64          `yaml:"retry_multiplier"`
+	RetryJitter          *float64          `yaml:"retry_jitter"`
+	Debug                *bool             `yaml:"debug"`
+	Proxy                string            `yaml:"proxy"`
+	ExtraHeaders         map[string]string `yaml:"extra_headers"`
+}
+
+// loadedProfile is the profile selected for a LoadConfigWithParams call.
+type loadedProfile struct {
+	profileConfig
+	name   string
+	path   string
+	chosen string // where the profile name came from
+}
+
+// source labels values taken from the profile.
+func (p *loadedProfile) source() string {
+	return fmt.Sprintf("profile %q", p.name)
+}
+
+// credentials builds the provider for an api_key_env or api_key_file source
+// and checks that it yields a key now rather than on the first request.
+func (p *loadedProfile) credentials() (CredentialsProvider, string, error) {
+	var provider CredentialsProvider
+	var desc string
+	switch {
+	case p.APIKeyEnv != "":
+		provider, desc = EnvCredentials(p.APIKeyEnv), "api_key_env "+p.APIKeyEnv
+	case p.APIKeyFile != "":
+		path := expandHome(p.APIKeyFile)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(p.path), path)
+		}
+		provider, desc = FileCredentials(path), "api_key_file "+path
+	default:
+		return nil, "", nil
+	}
+	if _, err := provider.APIKey(context.Background()); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", p.source(), err)
+	}
+	return provider, fmt.Sprintf("%s, %s", p.source(), desc), nil
+}
+
+// loadProfile finds the config file and selects a profile. It returns nil
+// when params.NoProfile is set, or when no file exists and no profile was
+// asked for explicitly.
+func loadProfile(params ConfigParams) (*loadedProfile, error) {
+	if params.NoProfile {
+		return nil, nil
+	}
+	path := firstNonEmpty(params.ConfigFile, os.Getenv("ROE_CONFIG_FILE"))
+	explicitPath := path != ""
+	if path == "" {
+		path = defaultConfigFilePath()
+	}
+	path = expandHome(path)
+
+	name, chosen := params.Profile, sourceParam
+	if name == "" {
+		name, chosen = os.Getenv("ROE_PROFILE"), envSource("ROE_PROFILE")
+	}
+
+	if path == "" {
+		if name != "" {
+			return nil, fmt.Errorf("profile %q requested but no config file location is known; set ROE_CONFIG_FILE", name)
+		}
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && !explicitPath && name == "" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	var file configFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	if name == "" {
+		name, chosen = file.DefaultProfile, "default_profile"
+	}
+	implicit := name == ""
+	if implicit {
+		name, chosen = "default", sourceDefault
+	}
+	profile, ok := file.Profiles[name]
+	if !ok {
+		if implicit {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("profile %q not found in %s", name, path)
+	}
+	keySources := 0
+	for _, v := range []string{profile.APIKey, profile.APIKeyEnv, profile.APIKeyFile} {
+		if v != "" {
+			keySources++
+		}
+	}
+	if keySources > 1 {
+		return nil, fmt.Errorf("profile %q: set only one of api_key, api_key_env and api_key_file", name)
+	}
+	return &loadedProfile{profileConfig: profile, name: name, path: path, chosen: chosen}, nil
+}
+
+// defaultConfigFilePath is $XDG_CONFIG_HOME/roe/config.yaml, falling back to
+// ~/.config/roe/config.yaml.
+func defaultConfigFilePath() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "roe", "config.yaml")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "roe", "config.yaml")
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+const (
+	sourceParam   = "param"
+	sourceDefault = "default"
+)
+
+func envSource(name string) string { return "env " + name }
+
+// configSources records where LoadConfigWithParams found each setting.
+type configSources map[string]string
+
+// candidate is one layer's value for a setting; ok reports whether the layer
+// sets it.
+type candidate[T any] struct {
+	value  T
+	ok     bool
+	source string
+}
+
+// pick returns the first candidate that is set and records its source.
+func pick[T any](sources configSources, field string, candidates ...candidate[T]) T {
+	for _, c := range candidates {
+		if c.ok {
+			sources[field] = c.source
+			return c.value
+		}
+	}
+	var zero T
+	return zero
+}
+
+// Describe renders the resolved configuration, one setting per line, with
+// the source of each value: a param, an environment variable, a profile or
+// the default. Secrets are redacted. Sources are recorded by
+// LoadConfigWithParams; a Config built by hand reports "set on Config".
+func (c Config) Describe() string {
+	var b strings.Builder
+	if profile := c.sources["profile"]; profile != "" {
+		fmt.Fprintf(&b, "profile: %s\n", profile)
+	}
+	line := func(field string, value any) {
+		source, ok := c.sources[field]
+		switch {
+		case ok:
+		case c.sources == nil:
+			source = "set on Config"
+		default:
+			source = "unset"
+		}
+		fmt.Fprintf(&b, "%s: %v (%s)\n", field, value, source)
+	}
+	redacted := func(set bool) string {
+		if set {
+			return "[redacted]"
+		}
+		return "<unset>"
+	}
+
+	line("api_key", redacted(c.APIKey != ""))
+	if c.Credentials != nil {
+		line("credentials", "provider")
+	}
+	line("organization_id", c.OrganizationID)
+	line("base_url", c.BaseURL)
+	line("timeout", c.Timeout)
+	line("max_retries", c.MaxRetries)
+	line("retry_initial_interval", c.RetryInitialInterval)
+	line("retry_max_interval", c.RetryMaxInterval)
+	line("retry_multiplier", c.RetryMultiplier)
+	line("retry_jitter", c.RetryJitter)
+	line("debug", c.Debug)
+	proxy := "<unset>"
+	if c.ProxyURL != nil {
+		proxy = c.ProxyURL.Redacted()
+	}
+	line("proxy", proxy)
+	line("request_id_header", c.RequestIDHeader)
+	line("auto_request_id", c.AutoRequestID)
+	if c.DefaultRequestID != "" {
+		line("request_id", c.DefaultRequestID)
+	}
+	line("max_idle_conns", c.MaxIdleConns)
+	line("max_idle_conns_per_host", c.MaxIdleConnsPerHost)
+	line("idle_conn_timeout", c.IdleConnTimeout)
+
+	redact := map[string]struct{}{}
+	for _, h := range c.RedactHeaders {
+		redact[http.CanonicalHeaderKey(h)] = struct{}{}
+	}
+	names := make([]string, 0, len(c.ExtraHeaders))
+	for name := range c.ExtraHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(c.ExtraHeaders.Values(name), ", ")
+		if _, ok := redact[http.CanonicalHeaderKey(name)]; ok {
+			value = "[redacted]"
+		}
+		line("extra_headers."+name, value)
+	}
+	return b.String()
+}
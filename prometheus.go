@@ -0,0 +1,215 @@
+package roe
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	defaultRequestBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+	defaultJobWaitBuckets = []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600, 7200}
+)
+
+// PrometheusMetrics is a dependency-free Metrics implementation that serves
+// the Prometheus text exposition format. Mount it on any mux:
+//
+//	metrics := roe.NewPrometheusMetrics()
+//	client, _ := roe.NewClientWithParams(roe.ConfigParams{Metrics: metrics})
+//	http.Handle("/metrics", metrics)
+//
+// It exports roe_sdk_requests_total, roe_sdk_request_duration_seconds,
+// roe_sdk_retries_total and roe_sdk_job_wait_duration_seconds, labelled by
+// method, endpoint class and status code (or "error" for transport failures).
+type PrometheusMetrics struct {
+	mu       sync.Mutex
+	families []*promFamily
+
+	requests        *promFamily
+	requestDuration *promFamily
+	retries         *promFamily
+	jobWait         *promFamily
+}
+
+// NewPrometheusMetrics returns an empty registry.
+func NewPrometheusMetrics() *PrometheusMetrics {
+	m := &PrometheusMetrics{
+		requests: newPromFamily("roe_sdk_requests_total", "HTTP attempts made by the Roe SDK.",
+			"counter", nil, "method", "endpoint", "code"),
+		requestDuration: newPromFamily("roe_sdk_request_duration_seconds", "Duration of HTTP attempts made by the Roe SDK.",
+			"histogram", defaultRequestBuckets, "method", "endpoint"),
+		retries: newPromFamily("roe_sdk_retries_total", "Retries scheduled by the Roe SDK.",
+			"counter", nil, "method", "endpoint", "code"),
+		jobWait: newPromFamily("roe_sdk_job_wait_duration_seconds", "Duration of Job.Wait and JobBatch.Wait calls.",
+			"histogram", defaultJobWaitBuckets, "kind", "outcome"),
+	}
+	m.families = []*promFamily{m.requests, m.requestDuration, m.retries, m.jobWait}
+	return m
+}
+
+// ObserveRequest implements Metrics.
+func (m *PrometheusMetrics) ObserveRequest(r RequestMetric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests.series(r.Method, string(r.Endpoint), statusLabel(r.StatusCode)).value++
+	m.requestDuration.series(r.Method, string(r.Endpoint)).observe(r.Duration.Seconds())
+}
+
+// ObserveRetry implements Metrics.
+func (m *PrometheusMetrics) ObserveRetry(r RetryMetric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries.series(r.Method, string(r.Endpoint), statusLabel(r.StatusCode)).value++
+}
+
+// ObserveJobWait implements Metrics.
+func (m *PrometheusMetrics) ObserveJobWait(r JobWaitMetric) {
+	kind, outcome := "job", "completed"
+	if r.Batch {
+		kind = "batch"
+	}
+	if r.Err != nil {
+		outcome = "error"
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobWait.series(kind, outcome).observe(r.Duration.Seconds())
+}
+
+// ServeHTTP writes every metric in the Prometheus text format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var buf bytes.Buffer
+	m.mu.Lock()
+	for _, f := range m.families {
+		f.write(&buf)
+	}
+	m.mu.Unlock()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func statusLabel(code int) string {
+	if code == 0 {
+		return "error"
+	}
+	return strconv.Itoa(code)
+}
+
+type promFamily struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+	byKey   map[string]*promSeries
+}
+
+type promSeries struct {
+	labelValues []string
+	value       float64   // counters
+	bounds      []float64 // histograms: upper bounds, shared with the family
+	counts      []uint64  // histograms: per bucket, not cumulative
+	sum         float64
+	count       uint64
+}
+
+func newPromFamily(name, help, kind string, buckets []float64, labels ...string) *promFamily {
+	return &promFamily{name: name, help: help, kind: kind, labels: labels, buckets: buckets, byKey: map[string]*promSeries{}}
+}
+
+func (f *promFamily) series(labelValues ...string) *promSeries {
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.byKey[key]
+	if !ok {
+		s = &promSeries{labelValues: labelValues, bounds: f.buckets}
+		if f.buckets != nil {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.byKey[key] = s
+	}
+	return s
+}
+
+func (s *promSeries) observe(v float64) {
+	s.sum += v
+	s.count++
+	for i, bound := range s.bounds {
+		if v <= bound {
+			s.counts[i]++
+			return
+		}
+	}
+}
+
+func (f *promFamily) write(buf *bytes.Buffer) {
+	if len(f.byKey) == 0 {
+		return
+	}
+	buf.WriteString("# HELP " + f.name + " " + f.help + "\n")
+	buf.WriteString("# TYPE " + f.name + " " + f.kind + "\n")
+
+	keys := make([]string, 0, len(f.byKey))
+	for key := range f.byKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := f.byKey[key]
+		labels := f.formatLabels(s.labelValues)
+		if f.kind != "histogram" {
+			writeSample(buf, f.name, labels, "", s.value)
+			continue
+		}
+		var cumulative uint64
+		for i, bound := range f.buckets {
+			cumulative += s.counts[i]
+			writeSample(buf, f.name+"_bucket", labels, formatFloat(bound), float64(cumulative))
+		}
+		writeSample(buf, f.name+"_bucket", labels, "+Inf", float64(s.count))
+		writeSample(buf, f.name+"_sum", labels, "", s.sum)
+		writeSample(buf, f.name+"_count", labels, "", float64(s.count))
+	}
+}
+
+func (f *promFamily) formatLabels(values []string) string {
+	pairs := make([]string, len(values))
+	for i, v := range values {
+		pairs[i] = f.labels[i] + `="` + escapeLabelValue(v) + `"`
+	}
+	return strings.Join(pairs, ",")
+}
+
+func writeSample(buf *bytes.Buffer, name, labels, le string, value float64) {
+	buf.WriteString(name)
+	if labels != "" || le != "" {
+		buf.WriteByte('{')
+		buf.WriteString(labels)
+		if le != "" {
+			if labels != "" {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(`le="` + le + `"`)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(formatFloat(value))
+	buf.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelEscaper.Replace(v)
+}
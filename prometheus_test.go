@@ -0,0 +1,53 @@
+package roe
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusMetricsExposition(t *testing.T) {
+	metrics := NewPrometheusMetrics()
+	metrics.ObserveRequest(RequestMetric{Method: "GET", Endpoint: EndpointStatus, StatusCode: 503, Duration: 20 * time.Millisecond})
+	metrics.ObserveRequest(RequestMetric{Method: "GET", Endpoint: EndpointStatus, StatusCode: 200, Duration: 200 * time.Millisecond})
+	metrics.ObserveRequest(RequestMetric{Method: "POST", Endpoint: EndpointRuns, Err: errors.New("reset")})
+	metrics.ObserveRetry(RetryMetric{Method: "GET", Endpoint: EndpointStatus, StatusCode: 503})
+	metrics.ObserveJobWait(JobWaitMetric{Batch: true, Jobs: 3, Duration: 90 * time.Second})
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	out := string(body)
+
+	for _, want := range []string{
+		"# TYPE roe_sdk_requests_total counter\n",
+		`roe_sdk_requests_total{method="GET",endpoint="status",code="503"} 1` + "\n",
+		`roe_sdk_requests_total{method="POST",endpoint="runs",code="error"} 1` + "\n",
+		"# TYPE roe_sdk_request_duration_seconds histogram\n",
+		`roe_sdk_request_duration_seconds_bucket{method="GET",endpoint="status",le="0.025"} 1` + "\n",
+		`roe_sdk_request_duration_seconds_bucket{method="GET",endpoint="status",le="0.25"} 2` + "\n",
+		`roe_sdk_request_duration_seconds_bucket{method="GET",endpoint="status",le="+Inf"} 2` + "\n",
+		`roe_sdk_request_duration_seconds_count{method="GET",endpoint="status"} 2` + "\n",
+		`roe_sdk_retries_total{method="GET",endpoint="status",code="503"} 1` + "\n",
+		`roe_sdk_job_wait_duration_seconds_bucket{kind="batch",outcome="completed",le="60"} 0` + "\n",
+		`roe_sdk_job_wait_duration_seconds_bucket{kind="batch",outcome="completed",le="120"} 1` + "\n",
+		`roe_sdk_job_wait_duration_seconds_sum{kind="batch",outcome="completed"} 90` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in exposition:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrometheusLabelEscaping(t *testing.T) {
+	if got := escapeLabelValue("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Fatalf("unexpected escaping: %s", got)
+	}
+}
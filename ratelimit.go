@@ -0,0 +1,249 @@
+package roe
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EndpointClass groups API paths that share client-side limits.
+type EndpointClass string
+
+const (
+	// EndpointRuns covers agent run submissions (/v1/agents/run/...).
+	EndpointRuns EndpointClass = "runs"
+	// EndpointStatus covers job status and result polling.
+	EndpointStatus EndpointClass = "status"
+	// EndpointTables covers the tables API (/v1/tables/...).
+	EndpointTables EndpointClass = "tables"
+	// EndpointOther covers every other path.
+	EndpointOther EndpointClass = "other"
+)
+
+// endpointClassFor maps a request path to its EndpointClass.
+func endpointClassFor(path string) EndpointClass {
+	switch {
+	case strings.HasPrefix(path, "/v1/agents/run/"):
+		return EndpointRuns
+	case strings.HasPrefix(path, "/v1/agents/jobs/") &&
+		(strings.HasSuffix(path, "/status/") || strings.HasSuffix(path, "/statuses/") ||
+			strings.HasSuffix(path, "/result/") || strings.HasSuffix(path, "/results/")):
+		return EndpointStatus
+	case strings.HasPrefix(path, "/v1/tables/"):
+		return EndpointTables
+	default:
+		return EndpointOther
+	}
+}
+
+// RateLimit configures the token bucket for one EndpointClass.
+type RateLimit struct {
+	// RequestsPerSecond is the steady-state rate the bucket recovers to.
+	RequestsPerSecond float64
+	// Burst is the bucket capacity. Defaults to 1.
+	Burst int
+	// MinRequestsPerSecond is the floor the rate shrinks to after repeated
+	// 429s. Defaults to a tenth of RequestsPerSecond.
+	MinRequestsPerSecond float64
+	// RecoveryInterval is how long the bucket must go without a 429 before
+	// the rate grows by a tenth of RequestsPerSecond. Defaults to 10s.
+	RecoveryInterval time.Duration
+}
+
+// RateLimiterStats is a snapshot of one bucket, for tuning.
+type RateLimiterStats struct {
+	BaseRate    float64
+	CurrentRate float64
+	Tokens      float64
+	// Allowed counts requests let through; Delayed counts those that had to
+	// wait first, and TotalWait sums how long they waited.
+	Allowed   uint64
+	Delayed   uint64
+	TotalWait time.Duration
+	// RateLimited counts 429s (or Retry-After responses) that shrank the rate.
+	RateLimited uint64
+	PausedUntil time.Time
+}
+
+// RateLimiter is an optional client-side limiter shared by every request from
+// a RoeClient (or several clients, when the same *RateLimiter is set on each
+// Config). Each EndpointClass has its own token bucket. When the API answers
+// 429 or sends Retry-After, the bucket halves its rate and pauses every
+// waiter until the server's backoff has passed, then recovers gradually.
+// Classes without a configured limit are not limited.
+type RateLimiter struct {
+	buckets map[EndpointClass]*tokenBucket
+	now     func() time.Time
+}
+
+// NewRateLimiter builds a limiter from per-class limits. Entries with a
+// non-positive RequestsPerSecond are ignored.
+func NewRateLimiter(limits map[EndpointClass]RateLimit) *RateLimiter {
+	l := &RateLimiter{buckets: map[EndpointClass]*tokenBucket{}, now: time.Now}
+	for class, limit := range limits {
+		if limit.RequestsPerSecond <= 0 {
+			continue
+		}
+		l.buckets[class] = newTokenBucket(limit, l.now())
+	}
+	return l
+}
+
+// Wait blocks until a request of the given class may be sent, or ctx ends.
+func (l *RateLimiter) Wait(ctx context.Context, class EndpointClass) error {
+	if l == nil {
+		return nil
+	}
+	b, ok := l.buckets[class]
+	if !ok {
+		return nil
+	}
+	var waited time.Duration
+	for {
+		delay := b.take(l.now(), waited)
+		if delay <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		waited += delay
+	}
+}
+
+// Stats returns a snapshot of every configured bucket.
+func (l *RateLimiter) Stats() map[EndpointClass]RateLimiterStats {
+	if l == nil {
+		return nil
+	}
+	stats := make(map[EndpointClass]RateLimiterStats, len(l.buckets))
+	for class, b := range l.buckets {
+		stats[class] = b.snapshot(l.now())
+	}
+	return stats
+}
+
+// observeBackoff shrinks the class's rate after a 429 or Retry-After reply.
+func (l *RateLimiter) observeBackoff(class EndpointClass, retryAfter time.Duration) {
+	if l == nil {
+		return
+	}
+	if b, ok := l.buckets[class]; ok {
+		b.backoff(l.now(), retryAfter)
+	}
+}
+
+type tokenBucket struct {
+	mu sync.Mutex
+
+	limit       RateLimit
+	rate        float64
+	tokens      float64
+	last        time.Time
+	lastBackoff time.Time
+	pausedUntil time.Time
+
+	allowed     uint64
+	delayed     uint64
+	totalWait   time.Duration
+	rateLimited uint64
+}
+
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+	if limit.MinRequestsPerSecond <= 0 || limit.MinRequestsPerSecond > limit.RequestsPerSecond {
+		limit.MinRequestsPerSecond = limit.RequestsPerSecond / 10
+	}
+	if limit.RecoveryInterval <= 0 {
+		limit.RecoveryInterval = 10 * time.Second
+	}
+	return &tokenBucket{
+		limit:       limit,
+		rate:        limit.RequestsPerSecond,
+		tokens:      float64(limit.Burst),
+		last:        now,
+		lastBackoff: now,
+	}
+}
+
+// take consumes a token and returns zero, or returns how long to wait before
+// trying again. waited is the time this caller has already spent waiting.
+func (b *tokenBucket) take(now time.Time, waited time.Duration) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		b.allowed++
+		if waited > 0 {
+			b.delayed++
+			b.totalWait += waited
+		}
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if b.rate < b.limit.RequestsPerSecond {
+		// Additive recovery: one step per quiet RecoveryInterval.
+		steps := math.Floor(float64(now.Sub(b.lastBackoff)) / float64(b.limit.RecoveryInterval))
+		if steps > 0 {
+			b.rate = math.Min(b.limit.RequestsPerSecond, b.rate+steps*b.limit.RequestsPerSecond/10)
+			b.lastBackoff = b.lastBackoff.Add(time.Duration(steps) * b.limit.RecoveryInterval)
+		}
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+func (b *tokenBucket) backoff(now time.Time, retryAfter time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	b.rateLimited++
+	b.rate = math.Max(b.limit.MinRequestsPerSecond, b.rate/2)
+	b.tokens = 0
+	if until := now.Add(retryAfter); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+	// Neither tokens nor rate recover while the server has asked us to wait.
+	b.lastBackoff = maxTime(now, b.pausedUntil)
+	b.last = b.lastBackoff
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func (b *tokenBucket) snapshot(now time.Time) RateLimiterStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	return RateLimiterStats{
+		BaseRate:    b.limit.RequestsPerSecond,
+		CurrentRate: b.rate,
+		Tokens:      b.tokens,
+		Allowed:     b.allowed,
+		Delayed:     b.delayed,
+		TotalWait:   b.totalWait,
+		RateLimited: b.rateLimited,
+		PausedUntil: b.pausedUntil,
+	}
+}
@@ -0,0 +1,137 @@
+package roe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestEndpointClassFor(t *testing.T) {
+	tests := map[string]EndpointClass{
+		"/v1/agents/run/agent-1/async/":      EndpointRuns,
+		"/v1/agents/run/agent-1/async/many/": EndpointRuns,
+		"/v1/agents/jobs/job-1/status/":      EndpointStatus,
+		"/v1/agents/jobs/statuses/":          EndpointStatus,
+		"/v1/agents/jobs/job-1/result/":      EndpointStatus,
+		"/v1/agents/jobs/results/":           EndpointStatus,
+		"/v1/tables/abc/rows/":               EndpointTables,
+		"/v1/agents/jobs/job-1/cancel/":      EndpointOther,
+		"/v1/policies/":                      EndpointOther,
+	}
+	for path, want := range tests {
+		if got := endpointClassFor(path); got != want {
+			t.Errorf("endpointClassFor(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestRateLimiterBucketShrinksAndRecovers(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := NewRateLimiter(map[EndpointClass]RateLimit{
+		EndpointRuns: {RequestsPerSecond: 10, Burst: 2, RecoveryInterval: time.Second},
+	})
+	limiter.now = func() time.Time { return now }
+	b := limiter.buckets[EndpointRuns]
+	b.last, b.lastBackoff = now, now
+
+	if b.take(now, 0) != 0 || b.take(now, 0) != 0 {
+		t.Fatalf("expected burst of 2 to pass immediately")
+	}
+	if delay := b.take(now, 0); delay != 100*time.Millisecond {
+		t.Fatalf("expected to wait one token interval, got %s", delay)
+	}
+
+	limiter.observeBackoff(EndpointRuns, 3*time.Second)
+	stats := limiter.Stats()[EndpointRuns]
+	if stats.CurrentRate != 5 || stats.RateLimited != 1 {
+		t.Fatalf("expected rate halved after 429, got %+v", stats)
+	}
+	if delay := b.take(now.Add(time.Second), 0); delay != 2*time.Second {
+		t.Fatalf("expected waiters paused until Retry-After elapses, got %s", delay)
+	}
+
+	now = now.Add(5500 * time.Millisecond)
+	if got := limiter.Stats()[EndpointRuns].CurrentRate; got != 7 {
+		t.Fatalf("expected two recovery steps after the pause, got rate %v", got)
+	}
+	now = now.Add(time.Minute)
+	if got := limiter.Stats()[EndpointRuns].CurrentRate; got != 10 {
+		t.Fatalf("expected rate to recover to base, got %v", got)
+	}
+
+	for i := 0; i < 10; i++ {
+		limiter.observeBackoff(EndpointRuns, 0)
+	}
+	if got := limiter.Stats()[EndpointRuns].CurrentRate; got != 1 {
+		t.Fatalf("expected rate floored at a tenth of base, got %v", got)
+	}
+}
+
+func TestRateLimiterWaitHonorsContextAndUnlimitedClasses(t *testing.T) {
+	limiter := NewRateLimiter(map[EndpointClass]RateLimit{
+		EndpointStatus: {RequestsPerSecond: 0.01},
+	})
+	if err := limiter.Wait(context.Background(), EndpointTables); err != nil {
+		t.Fatalf("expected unlimited class to pass, got %v", err)
+	}
+	if err := limiter.Wait(context.Background(), EndpointStatus); err != nil {
+		t.Fatalf("expected first request to use the burst, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, EndpointStatus); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline, got %v", err)
+	}
+
+	var nilLimiter *RateLimiter
+	if err := nilLimiter.Wait(context.Background(), EndpointRuns); err != nil {
+		t.Fatalf("expected nil limiter to be a no-op, got %v", err)
+	}
+}
+
+func TestHTTPClientRateLimiterAdaptsTo429(t *testing.T) {
+	calls := 0
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter(map[EndpointClass]RateLimit{
+		EndpointStatus: {RequestsPerSecond: 100, Burst: 5},
+	})
+	cfg := Config{
+		APIKey:               "k",
+		OrganizationID:       "org",
+		BaseURL:              server.URL,
+		Timeout:              time.Second,
+		MaxRetries:           1,
+		RetryInitialInterval: time.Millisecond,
+		RateLimiter:          limiter,
+	}
+	client := newHTTPClient(cfg, newAuth(cfg))
+	defer client.close()
+
+	if err := client.get("/v1/agents/jobs/job-1/status/", nil, nil); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+
+	stats := limiter.Stats()[EndpointStatus]
+	if stats.Allowed != 2 || stats.RateLimited != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if stats.CurrentRate >= stats.BaseRate {
+		t.Fatalf("expected rate to shrink after 429, got %v", stats.CurrentRate)
+	}
+	if stats.Delayed != 1 {
+		t.Fatalf("expected the retry to wait for a token, got %+v", stats)
+	}
+}
@@ -65,3 +65,50 @@ func TestRawClientCallsGeneratedEndpoint(t *testing.T) {
 		t.Fatalf("unexpected auth header: %s", authHeader)
 	}
 }
+
+func TestRawClientSharesMiddlewareStack(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var wrapped int
+	client, err := NewClientWithParams(ConfigParams{
+		APIKey:         "test-key",
+		OrganizationID: "test-org",
+		BaseURL:        server.URL,
+		Middleware: []Middleware{func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				wrapped++
+				return next.RoundTrip(req)
+			})
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithParams returned error: %v", err)
+	}
+	defer client.Close()
+
+	raw, err := client.Raw()
+	if err != nil {
+		t.Fatalf("Raw returned error: %v", err)
+	}
+	gen, ok := raw.ClientInterface.(*generated.Client)
+	if !ok {
+		t.Fatalf("Raw().ClientInterface is %T, want *generated.Client", raw.ClientInterface)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, gen.Server+"v1/users/current_user/", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+	resp, err := gen.Client.Do(req)
+	if err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if wrapped != 1 {
+		t.Fatalf("expected raw request to pass through middleware once, got %d", wrapped)
+	}
+}
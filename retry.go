@@ -0,0 +1,148 @@
+package roe
+
+import (
+	"context"
+	"errors"
+	"math"
+	mrand "math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryAttempt describes a failed attempt handed to a RetryPolicy.
+type RetryAttempt struct {
+	Method string
+	Path   string
+	// Attempt is the zero-based index of the attempt that just failed.
+	Attempt    int
+	MaxRetries int
+	// Response is nil when the attempt failed at the transport level. Its body
+	// has already been consumed; the raw bytes are in Body.
+	Response *http.Response
+	Body     []byte
+	// Err is the transport error, or the typed API error (e.g.
+	// *InsufficientCreditsError) built from Response.
+	Err error
+	// PreviousDelay is the wait that preceded this attempt (zero for the
+	// first attempt), for policies such as decorrelated jitter.
+	PreviousDelay time.Duration
+}
+
+// RetryPolicy decides whether a failed attempt is retried and how long to
+// wait first. It is only consulted while Config.MaxRetries allows another
+// attempt, and the wait is cut short if the request context is cancelled.
+// Policies may block (e.g. to run a credit top-up hook) and must be safe for
+// concurrent use.
+type RetryPolicy interface {
+	Retry(ctx context.Context, attempt RetryAttempt) (delay time.Duration, retry bool)
+}
+
+// RetryPolicyFunc adapts a function to the RetryPolicy interface.
+type RetryPolicyFunc func(ctx context.Context, attempt RetryAttempt) (time.Duration, bool)
+
+// Retry calls f(ctx, attempt).
+func (f RetryPolicyFunc) Retry(ctx context.Context, attempt RetryAttempt) (time.Duration, bool) {
+	return f(ctx, attempt)
+}
+
+// DefaultRetryPolicy retries transport timeouts and connection errors, 5xx
+// (except CleanupFailedError), 408 and 429 responses for every method, using
+// exponential backoff with jitter and honouring Retry-After. It is used when
+// Config.RetryPolicy is nil, and its methods can be reused by custom
+// policies.
+type DefaultRetryPolicy struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	Jitter          float64
+}
+
+func newDefaultRetryPolicy(cfg Config) DefaultRetryPolicy {
+	return DefaultRetryPolicy{
+		InitialInterval: cfg.RetryInitialInterval,
+		MaxInterval:     cfg.RetryMaxInterval,
+		Multiplier:      cfg.RetryMultiplier,
+		Jitter:          cfg.RetryJitter,
+	}
+}
+
+// Retry implements RetryPolicy.
+func (p DefaultRetryPolicy) Retry(_ context.Context, attempt RetryAttempt) (time.Duration, bool) {
+	if !p.ShouldRetry(attempt) {
+		return 0, false
+	}
+	return p.Delay(attempt), true
+}
+
+// ShouldRetry reports whether the attempt failed in a retryable way.
+func (p DefaultRetryPolicy) ShouldRetry(attempt RetryAttempt) bool {
+	if attempt.Response == nil {
+		err := attempt.Err
+		if err == nil {
+			return false
+		}
+		// Don't retry on context cancellation/timeout
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		// Only retry on temporary network errors; others are permanent.
+		return isTransientNetError(err)
+	}
+	var cleanupErr *CleanupFailedError
+	if errors.As(attempt.Err, &cleanupErr) {
+		// The resource is already gone; a retry would only return 404.
+		return false
+	}
+	if attempt.Response.StatusCode >= 500 {
+		return true
+	}
+	switch attempt.Response.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	default:
+		return false
+	}
+}
+
+// Delay returns the backoff for the attempt, raised to the server's
+// Retry-After when that is longer.
+func (p DefaultRetryPolicy) Delay(attempt RetryAttempt) time.Duration {
+	delay := p.Backoff(attempt.Attempt)
+	if attempt.Response == nil {
+		return delay
+	}
+	retryAfter := parseRetryAfter(attempt.Response.Header)
+	if retryAfter == nil || *retryAfter <= 0 {
+		return delay
+	}
+	if *retryAfter > delay {
+		return *retryAfter
+	}
+	return delay
+}
+
+// Backoff returns the exponential backoff with jitter for a zero-based attempt.
+func (p DefaultRetryPolicy) Backoff(attempt int) time.Duration {
+	initial, maxInterval, multiplier := p.InitialInterval, p.MaxInterval, p.Multiplier
+	if initial <= 0 {
+		initial = defaultRetryInitial
+	}
+	if maxInterval <= 0 {
+		maxInterval = defaultRetryMax
+	}
+	if multiplier <= 0 {
+		multiplier = defaultRetryMultiplier
+	}
+	delay := maxInterval
+	if scaled := float64(initial) * math.Pow(multiplier, float64(attempt)); scaled < float64(maxInterval) {
+		delay = time.Duration(scaled)
+	}
+	if p.Jitter > 0 {
+		jitterFactor := 1 + (mrand.Float64()*2-1)*p.Jitter
+		delay = time.Duration(float64(delay) * jitterFactor)
+	}
+	if delay < time.Millisecond {
+		return time.Millisecond
+	}
+	return delay
+}
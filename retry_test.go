@@ -0,0 +1,142 @@
+package roe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDefaultRetryPolicyShouldRetry(t *testing.T) {
+	policy := DefaultRetryPolicy{}
+	tests := []struct {
+		name    string
+		attempt RetryAttempt
+		want    bool
+	}{
+		{"500", RetryAttempt{Response: &http.Response{StatusCode: 500}}, true},
+		{"503", RetryAttempt{Response: &http.Response{StatusCode: 503}}, true},
+		{"408", RetryAttempt{Response: &http.Response{StatusCode: 408}}, true},
+		{"429", RetryAttempt{Response: &http.Response{StatusCode: 429}}, true},
+		{"400", RetryAttempt{Response: &http.Response{StatusCode: 400}}, false},
+		{"402", RetryAttempt{Response: &http.Response{StatusCode: 402}}, false},
+		{"context canceled", RetryAttempt{Err: context.Canceled}, false},
+		{"permanent error", RetryAttempt{Err: errors.New("boom")}, false},
+		{"no error", RetryAttempt{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.ShouldRetry(tt.attempt); got != tt.want {
+				t.Fatalf("ShouldRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultRetryPolicyDelayHonorsRetryAfter(t *testing.T) {
+	policy := DefaultRetryPolicy{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond, Multiplier: 1}
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"2"}}}
+	if got := policy.Delay(RetryAttempt{Response: resp}); got != 2*time.Second {
+		t.Fatalf("expected Retry-After to win, got %s", got)
+	}
+	if got := policy.Backoff(50); got != time.Millisecond {
+		t.Fatalf("expected backoff capped at max interval, got %s", got)
+	}
+}
+
+func TestHTTPClientRetryPolicySkipsNonIdempotentPosts(t *testing.T) {
+	attempts := map[string]int{}
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts[r.Method]++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	var seen []RetryAttempt
+	cfg := Config{
+		APIKey:         "k",
+		OrganizationID: "org",
+		BaseURL:        server.URL,
+		Timeout:        time.Second,
+		MaxRetries:     2,
+		RetryPolicy: RetryPolicyFunc(func(ctx context.Context, attempt RetryAttempt) (time.Duration, bool) {
+			seen = append(seen, attempt)
+			if attempt.Method == http.MethodPost {
+				return 0, false
+			}
+			return time.Millisecond, true
+		}),
+	}
+
+	client := newHTTPClient(cfg, newAuth(cfg))
+	defer client.close()
+
+	if err := client.postJSONWithContext(context.Background(), "/submit", map[string]any{}, nil, nil); err == nil {
+		t.Fatalf("expected error")
+	}
+	if attempts[http.MethodPost] != 1 {
+		t.Fatalf("expected POST not to be retried, got %d attempts", attempts[http.MethodPost])
+	}
+
+	err := client.get("/poll", nil, nil)
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) {
+		t.Fatalf("expected ServerError, got %T: %v", err, err)
+	}
+	if attempts[http.MethodGet] != 3 {
+		t.Fatalf("expected GET to use the full retry budget, got %d attempts", attempts[http.MethodGet])
+	}
+
+	last := seen[len(seen)-1]
+	if last.Path != "/poll" || last.Attempt != 1 || last.MaxRetries != 2 {
+		t.Fatalf("unexpected attempt details: %+v", last)
+	}
+	if last.Response == nil || last.Response.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected the failed response to be passed to the policy")
+	}
+	if last.PreviousDelay != time.Millisecond {
+		t.Fatalf("expected previous delay to be tracked, got %s", last.PreviousDelay)
+	}
+}
+
+func TestHTTPClientRetryPolicyCanRetryAfterTopUp(t *testing.T) {
+	toppedUp := false
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !toppedUp {
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write([]byte(`{"detail":"insufficient credits"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		APIKey:         "k",
+		OrganizationID: "org",
+		BaseURL:        server.URL,
+		Timeout:        time.Second,
+		MaxRetries:     1,
+		RetryPolicy: RetryPolicyFunc(func(ctx context.Context, attempt RetryAttempt) (time.Duration, bool) {
+			var credits *InsufficientCreditsError
+			if errors.As(attempt.Err, &credits) {
+				toppedUp = true
+				return 0, true
+			}
+			return DefaultRetryPolicy{}.Retry(ctx, attempt)
+		}),
+	}
+
+	client := newHTTPClient(cfg, newAuth(cfg))
+	defer client.close()
+
+	var out map[string]bool
+	if err := client.get("/run", nil, &out); err != nil {
+		t.Fatalf("expected request to succeed after top-up, got %v", err)
+	}
+	if !out["ok"] {
+		t.Fatalf("unexpected response: %v", out)
+	}
+}
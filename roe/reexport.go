@@ -1,6 +1,12 @@
 package roe
 
-import root "github.com/roe-ai/roe-golang"
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	root "github.com/roe-ai/roe-golang"
+)
 
 type (
 	// Core client/config.
@@ -9,8 +15,48 @@ type (
 	ConfigParams = root.ConfigParams
 	Logger       = root.Logger
 
+	CredentialsProvider = root.CredentialsProvider
+	CredentialsFunc     = root.CredentialsFunc
+
 	RequestHook  = root.RequestHook
 	ResponseHook = root.ResponseHook
+	Middleware   = root.Middleware
+
+	RetryPolicy        = root.RetryPolicy
+	RetryPolicyFunc    = root.RetryPolicyFunc
+	RetryAttempt       = root.RetryAttempt
+	DefaultRetryPolicy = root.DefaultRetryPolicy
+
+	PollSchedule                 = root.PollSchedule
+	PollScheduleFunc             = root.PollScheduleFunc
+	BackoffPollSchedule          = root.BackoffPollSchedule
+	ExpectedDurationPollSchedule = root.ExpectedDurationPollSchedule
+
+	RateLimiter      = root.RateLimiter
+	RateLimit        = root.RateLimit
+	RateLimiterStats = root.RateLimiterStats
+	EndpointClass    = root.EndpointClass
+
+	CircuitBreaker         = root.CircuitBreaker
+	CircuitBreakerSettings = root.CircuitBreakerSettings
+	CircuitState           = root.CircuitState
+	CircuitStateChange     = root.CircuitStateChange
+	CircuitOpenError       = root.CircuitOpenError
+
+	Tracer        = root.Tracer
+	Span          = root.Span
+	SpanAttribute = root.SpanAttribute
+
+	Metrics           = root.Metrics
+	RequestMetric     = root.RequestMetric
+	RetryMetric       = root.RetryMetric
+	JobWaitMetric     = root.JobWaitMetric
+	PrometheusMetrics = root.PrometheusMetrics
+
+	// Record/replay testing.
+	Cassette        = root.Cassette
+	CassetteMode    = root.CassetteMode
+	CassetteOptions = root.CassetteOptions
 
 	// API surfaces.
 	Auth               = root.Auth
@@ -34,29 +80,43 @@ type (
 
 	AgentDatum            = root.AgentDatum
 	AgentJobStatus        = root.AgentJobStatus
+	JobEvent              = root.JobEvent
+	JobBatchResult        = root.JobBatchResult
+	BatchIncompleteError  = root.BatchIncompleteError
+	JobCheckpoint         = root.JobCheckpoint
+	JobBatchCheckpoint    = root.JobBatchCheckpoint
+	JobFilter             = root.JobFilter
 	Reference             = root.Reference
 	AgentJobResult        = root.AgentJobResult
 	AgentJobStatusBatch   = root.AgentJobStatusBatch
 	AgentJobResultBatch   = root.AgentJobResultBatch
 	JobDataDeleteResponse = root.JobDataDeleteResponse
 	Policy                = root.Policy
+	DependentAgent        = root.DependentAgent
 	PolicyVersion         = root.PolicyVersion
 
 	// File uploads.
 	FileUpload = root.FileUpload
 
-	// Per-run options.
-	RunOptions = root.RunOptions
+	// Per-call options.
+	RequestOption = root.RequestOption
+	RunOptions    = root.RunOptions
 
 	// Errors.
 	APIError                 = root.APIError
 	BadRequestError          = root.BadRequestError
+	FieldError               = root.FieldError
 	AuthenticationError      = root.AuthenticationError
 	InsufficientCreditsError = root.InsufficientCreditsError
 	ForbiddenError           = root.ForbiddenError
 	NotFoundError            = root.NotFoundError
 	RateLimitError           = root.RateLimitError
 	ServerError              = root.ServerError
+	ConflictError            = root.ConflictError
+	ResourceInUseError       = root.ResourceInUseError
+	CleanupFailedError       = root.CleanupFailedError
+	RequestError             = root.RequestError
+	RequestAttempt           = root.RequestAttempt
 )
 
 const (
@@ -67,11 +127,37 @@ const (
 	JobFailure   = root.JobFailure
 	JobCancelled = root.JobCancelled
 	JobCached    = root.JobCached
+
+	EndpointRuns   = root.EndpointRuns
+	EndpointStatus = root.EndpointStatus
+	EndpointTables = root.EndpointTables
+	EndpointOther  = root.EndpointOther
+
+	CircuitClosed   = root.CircuitClosed
+	CircuitOpen     = root.CircuitOpen
+	CircuitHalfOpen = root.CircuitHalfOpen
+
+	CassetteReplay = root.CassetteReplay
+	CassetteRecord = root.CassetteRecord
+
+	AttrAgentID        = root.AttrAgentID
+	AttrJobID          = root.AttrJobID
+	AttrJobCount       = root.AttrJobCount
+	AttrJobStatus      = root.AttrJobStatus
+	AttrAttempt        = root.AttrAttempt
+	AttrRequestID      = root.AttrRequestID
+	AttrHTTPMethod     = root.AttrHTTPMethod
+	AttrURLPath        = root.AttrURLPath
+	AttrHTTPStatusCode = root.AttrHTTPStatusCode
 )
 
 var (
 	ErrMissingAPIKey         = root.ErrMissingAPIKey
 	ErrMissingOrganizationID = root.ErrMissingOrganizationID
+	ErrCircuitOpen           = root.ErrCircuitOpen
+	ErrCassetteMiss          = root.ErrCassetteMiss
+
+	DefaultPollSchedule = root.DefaultPollSchedule
 )
 
 func NewClient(apiKey, organizationID, baseURL string, timeoutSeconds float64, maxRetries int) (*RoeClient, error) {
@@ -86,6 +172,90 @@ func NewClientWithConfig(cfg Config) (*RoeClient, error) {
 	return root.NewClientWithConfig(cfg)
 }
 
+func NewRateLimiter(limits map[EndpointClass]RateLimit) *RateLimiter {
+	return root.NewRateLimiter(limits)
+}
+
+func NewCircuitBreaker(settings CircuitBreakerSettings) *CircuitBreaker {
+	return root.NewCircuitBreaker(settings)
+}
+
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return root.NewPrometheusMetrics()
+}
+
+func WithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	return root.WithLogAttrs(ctx, attrs...)
+}
+
+func NewCassette(opts CassetteOptions) (*Cassette, error) {
+	return root.NewCassette(opts)
+}
+
+func StaticCredentials(key string) CredentialsProvider {
+	return root.StaticCredentials(key)
+}
+
+func EnvCredentials(name string) CredentialsProvider {
+	return root.EnvCredentials(name)
+}
+
+func FileCredentials(path string) CredentialsProvider {
+	return root.FileCredentials(path)
+}
+
+func NewCachedCredentials(provider CredentialsProvider, ttl time.Duration) CredentialsProvider {
+	return root.NewCachedCredentials(provider, ttl)
+}
+
+func WithTimeout(timeout time.Duration) RequestOption {
+	return root.WithTimeout(timeout)
+}
+
+func WithMaxRetries(maxRetries int) RequestOption {
+	return root.WithMaxRetries(maxRetries)
+}
+
+func WithHeader(key, value string) RequestOption {
+	return root.WithHeader(key, value)
+}
+
+func WithRequestID(requestID string) RequestOption {
+	return root.WithRequestID(requestID)
+}
+
+func IsNotFound(err error) bool {
+	return root.IsNotFound(err)
+}
+
+func IsTransient(err error) bool {
+	return root.IsTransient(err)
+}
+
+func IsRetryable(err error) bool {
+	return root.IsRetryable(err)
+}
+
+func FixedPollSchedule(interval time.Duration) PollSchedule {
+	return root.FixedPollSchedule(interval)
+}
+
+func ResumeJob(client *RoeClient, checkpoint JobCheckpoint) (*Job, error) {
+	return root.ResumeJob(client, checkpoint)
+}
+
+func ResumeJobBatch(client *RoeClient, checkpoint JobBatchCheckpoint) (*JobBatch, error) {
+	return root.ResumeJobBatch(client, checkpoint)
+}
+
+func ReadJobCheckpoint(path string) (JobCheckpoint, error) {
+	return root.ReadJobCheckpoint(path)
+}
+
+func ReadJobBatchCheckpoint(path string) (JobBatchCheckpoint, error) {
+	return root.ReadJobBatchCheckpoint(path)
+}
+
 func LoadConfig(apiKey, orgID, baseURL string, timeoutSeconds float64, maxRetries int) (Config, error) {
 	return root.LoadConfig(apiKey, orgID, baseURL, timeoutSeconds, maxRetries)
 }
@@ -0,0 +1,324 @@
+package roetest
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	roe "github.com/roe-ai/roe-golang"
+)
+
+const defaultEngineClassID = "RoeTestEngine"
+
+type fakeAgent struct {
+	agent    roe.BaseAgent
+	versions []*roe.AgentVersion
+}
+
+type agentPayload struct {
+	Name             *string                    `json:"name"`
+	EngineClassID    string                     `json:"engine_class_id"`
+	InputDefinitions []roe.AgentInputDefinition `json:"input_definitions"`
+	EngineConfig     map[string]any             `json:"engine_config"`
+	VersionName      *string                    `json:"version_name"`
+	Description      *string                    `json:"description"`
+	DisableCache     *bool                      `json:"disable_cache"`
+	CacheFailedJobs  *bool                      `json:"cache_failed_jobs"`
+}
+
+// AddAgent seeds an agent with one version and returns it.
+func (s *Server) AddAgent(name string, inputs ...roe.AgentInputDefinition) roe.BaseAgent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.createAgent(name, defaultEngineClassID, inputs, map[string]any{}, "", nil)
+	return s.renderAgent(a)
+}
+
+func (s *Server) registerAgentRoutes() {
+	s.handle("GET /v1/agents/", s.listAgents)
+	s.handle("POST /v1/agents/", s.postAgent)
+	s.handle("GET /v1/agents/*/", s.getAgent)
+	s.handle("PATCH /v1/agents/*/", s.updateAgent)
+	s.handle("PUT /v1/agents/*/", s.updateAgent)
+	s.handle("DELETE /v1/agents/*/", s.deleteAgent)
+	s.handle("POST /v1/agents/*/duplicate/", s.duplicateAgent)
+	s.handle("GET /v1/agents/*/versions/", s.listVersions)
+	s.handle("POST /v1/agents/*/versions/", s.postVersion)
+	s.handle("GET /v1/agents/*/versions/current/", s.getCurrentVersion)
+	s.handle("GET /v1/agents/*/versions/*/", s.getVersion)
+	s.handle("PATCH /v1/agents/*/versions/*/", s.updateVersion)
+	s.handle("PUT /v1/agents/*/versions/*/", s.updateVersion)
+	s.handle("DELETE /v1/agents/*/versions/*/", s.deleteVersion)
+}
+
+func (s *Server) createAgent(name, engineClassID string, inputs []roe.AgentInputDefinition, engineConfig map[string]any, versionName string, description *string) *fakeAgent {
+	a := &fakeAgent{agent: roe.BaseAgent{
+		ID:             s.newID(),
+		Name:           name,
+		CreatedAt:      time.Now().UTC(),
+		OrganizationID: s.OrganizationID,
+		EngineClassID:  engineClassID,
+		EngineName:     engineClassID,
+	}}
+	s.agents[a.agent.ID] = a
+	s.agentOrder = append(s.agentOrder, a.agent.ID)
+	v := s.addVersion(a, inputs, engineConfig, versionName, description)
+	a.agent.CurrentVersionID = &v.ID
+	return a
+}
+
+func (s *Server) addVersion(a *fakeAgent, inputs []roe.AgentInputDefinition, engineConfig map[string]any, versionName string, description *string) *roe.AgentVersion {
+	if versionName == "" {
+		versionName = fmt.Sprintf("v%d", len(a.versions)+1)
+	}
+	if inputs == nil {
+		inputs = []roe.AgentInputDefinition{}
+	}
+	if engineConfig == nil {
+		engineConfig = map[string]any{}
+	}
+	v := &roe.AgentVersion{
+		ID:             s.newID(),
+		Name:           a.agent.Name,
+		VersionName:    versionName,
+		CreatedAt:      time.Now().UTC(),
+		Description:    description,
+		EngineClassID:  a.agent.EngineClassID,
+		EngineName:     a.agent.EngineName,
+		InputDefs:      inputs,
+		EngineConfig:   engineConfig,
+		OrganizationID: s.OrganizationID,
+	}
+	a.versions = append(a.versions, v)
+	return v
+}
+
+func (s *Server) renderAgent(a *fakeAgent) roe.BaseAgent {
+	out := a.agent
+	for _, id := range s.jobOrder {
+		if j := s.jobs[id]; j.agentID == out.ID {
+			out.JobCount++
+			created := j.createdAt
+			out.MostRecentJob = &created
+		}
+	}
+	return out
+}
+
+func (s *Server) renderVersion(a *fakeAgent, v *roe.AgentVersion) roe.AgentVersion {
+	out := *v
+	out.BaseAgent = s.renderAgent(a)
+	return out
+}
+
+func (s *Server) findAgent(w http.ResponseWriter, id string) *fakeAgent {
+	a, ok := s.agents[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found.")
+	}
+	return a
+}
+
+func (s *Server) findVersion(w http.ResponseWriter, agentID, versionID string) (*fakeAgent, *roe.AgentVersion) {
+	a := s.findAgent(w, agentID)
+	if a == nil {
+		return nil, nil
+	}
+	for _, v := range a.versions {
+		if v.ID == versionID {
+			return a, v
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not found.")
+	return nil, nil
+}
+
+func (s *Server) listAgents(w http.ResponseWriter, r *http.Request, _ []string) {
+	agents := make([]roe.BaseAgent, 0, len(s.agentOrder))
+	for _, id := range s.agentOrder {
+		agents = append(agents, s.renderAgent(s.agents[id]))
+	}
+	writeJSON(w, http.StatusOK, paginate(r, agents))
+}
+
+func (s *Server) postAgent(w http.ResponseWriter, r *http.Request, _ []string) {
+	var p agentPayload
+	if !decodeJSON(w, r, &p) {
+		return
+	}
+	var missing []string
+	if p.Name == nil || *p.Name == "" {
+		missing = append(missing, "name")
+	}
+	if p.EngineClassID == "" {
+		missing = append(missing, "engine_class_id")
+	}
+	if len(missing) > 0 {
+		writeFieldErrors(w, missing...)
+		return
+	}
+	versionName := ""
+	if p.VersionName != nil {
+		versionName = *p.VersionName
+	}
+	a := s.createAgent(*p.Name, p.EngineClassID, p.InputDefinitions, p.EngineConfig, versionName, p.Description)
+	writeJSON(w, http.StatusCreated, s.renderAgent(a))
+}
+
+func (s *Server) getAgent(w http.ResponseWriter, _ *http.Request, params []string) {
+	if a := s.findAgent(w, params[0]); a != nil {
+		writeJSON(w, http.StatusOK, s.renderAgent(a))
+	}
+}
+
+func (s *Server) updateAgent(w http.ResponseWriter, r *http.Request, params []string) {
+	a := s.findAgent(w, params[0])
+	if a == nil {
+		return
+	}
+	var p agentPayload
+	if !decodeJSON(w, r, &p) {
+		return
+	}
+	if r.Method == http.MethodPut && (p.Name == nil || *p.Name == "") {
+		writeFieldErrors(w, "name")
+		return
+	}
+	if p.Name != nil {
+		a.agent.Name = *p.Name
+	}
+	if p.DisableCache != nil {
+		a.agent.DisableCache = *p.DisableCache
+	}
+	if p.CacheFailedJobs != nil {
+		a.agent.CacheFailedJobs = *p.CacheFailedJobs
+	}
+	writeJSON(w, http.StatusOK, s.renderAgent(a))
+}
+
+func (s *Server) deleteAgent(w http.ResponseWriter, _ *http.Request, params []string) {
+	if a := s.findAgent(w, params[0]); a != nil {
+		delete(s.agents, a.agent.ID)
+		s.agentOrder = removeID(s.agentOrder, a.agent.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) duplicateAgent(w http.ResponseWriter, _ *http.Request, params []string) {
+	a := s.findAgent(w, params[0])
+	if a == nil {
+		return
+	}
+	current := a.versions[len(a.versions)-1]
+	for _, v := range a.versions {
+		if a.agent.CurrentVersionID != nil && v.ID == *a.agent.CurrentVersionID {
+			current = v
+		}
+	}
+	dup := s.createAgent(a.agent.Name+" (copy)", a.agent.EngineClassID, current.InputDefs, current.EngineConfig, "", current.Description)
+	writeJSON(w, http.StatusCreated, map[string]any{"base_agent": s.renderAgent(dup)})
+}
+
+func (s *Server) listVersions(w http.ResponseWriter, r *http.Request, params []string) {
+	a := s.findAgent(w, params[0])
+	if a == nil {
+		return
+	}
+	versions := make([]roe.AgentVersion, 0, len(a.versions))
+	for _, v := range a.versions {
+		versions = append(versions, s.renderVersion(a, v))
+	}
+	// The endpoint returns a bare array unless pagination is requested.
+	if q := r.URL.Query(); q.Has("page") || q.Has("page_size") {
+		writeJSON(w, http.StatusOK, paginate(r, versions))
+		return
+	}
+	writeJSON(w, http.StatusOK, versions)
+}
+
+func (s *Server) postVersion(w http.ResponseWriter, r *http.Request, params []string) {
+	a := s.findAgent(w, params[0])
+	if a == nil {
+		return
+	}
+	var p agentPayload
+	if !decodeJSON(w, r, &p) {
+		return
+	}
+	versionName := ""
+	if p.VersionName != nil {
+		versionName = *p.VersionName
+	}
+	v := s.addVersion(a, p.InputDefinitions, p.EngineConfig, versionName, p.Description)
+	writeJSON(w, http.StatusCreated, map[string]any{"id": v.ID, "version_name": v.VersionName})
+}
+
+func (s *Server) getCurrentVersion(w http.ResponseWriter, _ *http.Request, params []string) {
+	a := s.findAgent(w, params[0])
+	if a == nil {
+		return
+	}
+	if a.agent.CurrentVersionID == nil {
+		writeError(w, http.StatusNotFound, "Agent has no current version.")
+		return
+	}
+	if _, v := s.findVersion(w, a.agent.ID, *a.agent.CurrentVersionID); v != nil {
+		writeJSON(w, http.StatusOK, s.renderVersion(a, v))
+	}
+}
+
+func (s *Server) getVersion(w http.ResponseWriter, _ *http.Request, params []string) {
+	if a, v := s.findVersion(w, params[0], params[1]); v != nil {
+		writeJSON(w, http.StatusOK, s.renderVersion(a, v))
+	}
+}
+
+func (s *Server) updateVersion(w http.ResponseWriter, r *http.Request, params []string) {
+	a, v := s.findVersion(w, params[0], params[1])
+	if v == nil {
+		return
+	}
+	var p agentPayload
+	if !decodeJSON(w, r, &p) {
+		return
+	}
+	if r.Method == http.MethodPut && p.VersionName == nil {
+		writeFieldErrors(w, "version_name")
+		return
+	}
+	if p.VersionName != nil {
+		v.VersionName = *p.VersionName
+	}
+	if p.Description != nil {
+		v.Description = p.Description
+	}
+	writeJSON(w, http.StatusOK, s.renderVersion(a, v))
+}
+
+func (s *Server) deleteVersion(w http.ResponseWriter, _ *http.Request, params []string) {
+	a, v := s.findVersion(w, params[0], params[1])
+	if v == nil {
+		return
+	}
+	if a.agent.CurrentVersionID != nil && *a.agent.CurrentVersionID == v.ID {
+		writeError(w, http.StatusBadRequest, "The current version cannot be deleted.")
+		return
+	}
+	for i, candidate := range a.versions {
+		if candidate == v {
+			a.versions = append(a.versions[:i], a.versions[i+1:]...)
+			break
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func removeID(ids []string, id string) []string {
+	out := ids[:0]
+	for _, candidate := range ids {
+		if candidate != id {
+			out = append(out, candidate)
+		}
+	}
+	return out
+}
@@ -0,0 +1,551 @@
+package roetest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	roe "github.com/roe-ai/roe-golang"
+)
+
+// Lifecycle scripts how a fake job progresses. Every status poll of the job,
+// single or batched, advances it by one step: it reports pending for
+// PendingPolls polls, then started for StartedPolls polls, then Final. The
+// zero Lifecycle finishes successfully on the first poll.
+type Lifecycle struct {
+	PendingPolls int
+	StartedPolls int
+	// Final is the terminal status. It defaults to roe.JobSuccess; any
+	// non-terminal value is treated the same way.
+	Final roe.JobStatus
+	// ErrorMessage is reported for failed and cancelled jobs.
+	ErrorMessage string
+	// Outputs are returned as the job result. When nil, the job echoes each
+	// input back as an output with the same key.
+	Outputs []roe.AgentDatum
+}
+
+func (l Lifecycle) final() roe.JobStatus {
+	if !l.Final.IsTerminal() {
+		return roe.JobSuccess
+	}
+	return l.Final
+}
+
+// JobState is a snapshot of a fake job.
+type JobState struct {
+	ID        string
+	AgentID   string
+	VersionID string
+	// Status is the status the next poll would report, before advancing.
+	Status roe.JobStatus
+	Polls  int
+	// Inputs holds the submitted form values; file inputs hold the file name.
+	Inputs   map[string]string
+	Metadata map[string]string
+}
+
+type fakeJob struct {
+	id        string
+	agentID   string
+	versionID string
+	createdAt time.Time
+	updatedAt time.Time
+	inputs    map[string]string
+	metadata  map[string]string
+	lifecycle Lifecycle
+	polls     int
+	cancelled bool
+	deleted   bool
+}
+
+// status reports the job's current status without advancing it.
+func (j *fakeJob) status() roe.JobStatus {
+	switch {
+	case j.cancelled:
+		return roe.JobCancelled
+	case j.polls < j.lifecycle.PendingPolls:
+		return roe.JobPending
+	case j.polls < j.lifecycle.PendingPolls+j.lifecycle.StartedPolls:
+		return roe.JobStarted
+	default:
+		return j.lifecycle.final()
+	}
+}
+
+// poll reports the current status and advances the lifecycle.
+func (j *fakeJob) poll() roe.JobStatus {
+	st := j.status()
+	if !st.IsTerminal() {
+		j.polls++
+		j.updatedAt = time.Now().UTC()
+	}
+	return st
+}
+
+func (j *fakeJob) errorMessage() *string {
+	st := j.status()
+	if st != roe.JobFailure && st != roe.JobCancelled {
+		return nil
+	}
+	msg := j.lifecycle.ErrorMessage
+	if msg == "" {
+		msg = "Job " + st.String()
+	}
+	return &msg
+}
+
+func (j *fakeJob) outputs() []roe.AgentDatum {
+	if j.deleted {
+		return []roe.AgentDatum{}
+	}
+	if st := j.status(); st != roe.JobSuccess && st != roe.JobCached {
+		return []roe.AgentDatum{}
+	}
+	if j.lifecycle.Outputs != nil {
+		return j.lifecycle.Outputs
+	}
+	keys := make([]string, 0, len(j.inputs))
+	for k := range j.inputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	outputs := make([]roe.AgentDatum, 0, len(keys))
+	for _, k := range keys {
+		outputs = append(outputs, roe.AgentDatum{Key: k, DataType: "text/plain", Value: j.inputs[k]})
+	}
+	return outputs
+}
+
+func (j *fakeJob) inputList() []any {
+	keys := make([]string, 0, len(j.inputs))
+	for k := range j.inputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]any, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, map[string]any{"key": k, "value": j.inputs[k]})
+	}
+	return out
+}
+
+// SetLifecycle sets the lifecycle of jobs created from now on, for agents
+// without their own lifecycle.
+func (s *Server) SetLifecycle(l Lifecycle) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.defaultLifecycle = l
+}
+
+// SetAgentLifecycle sets the lifecycle of jobs created from now on for one
+// agent.
+func (s *Server) SetAgentLifecycle(agentID string, l Lifecycle) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.agentLifecycles[agentID] = l
+}
+
+// Job returns a snapshot of a job.
+func (s *Server) Job(jobID string) (JobState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[jobID]
+	if !ok {
+		return JobState{}, false
+	}
+	return JobState{
+		ID:        j.id,
+		AgentID:   j.agentID,
+		VersionID: j.versionID,
+		Status:    j.status(),
+		Polls:     j.polls,
+		Inputs:    j.inputs,
+		Metadata:  j.metadata,
+	}, true
+}
+
+func (s *Server) registerJobRoutes() {
+	s.handle("POST /v1/agents/run/*/", s.runSync)
+	s.handle("POST /v1/agents/run/*/async/", s.runAsync)
+	s.handle("POST /v1/agents/run/*/async/many/", s.runMany)
+	s.handle("POST /v1/agents/run/*/versions/*/", s.runSync)
+	s.handle("POST /v1/agents/run/*/versions/*/async/", s.runAsync)
+	s.handle("POST /v1/agents/jobs/statuses/", s.jobStatuses)
+	s.handle("POST /v1/agents/jobs/results/", s.jobResults)
+	s.handle("GET /v1/agents/jobs/*/status/", s.jobStatus)
+	s.handle("GET /v1/agents/jobs/*/result/", s.jobResult)
+	s.handle("POST /v1/agents/jobs/*/cancel/", s.cancelJob)
+	s.handle("POST /v1/agents/jobs/*/delete-data/", s.deleteJobData)
+	s.handle("POST /v1/agents/jobs/*/webhook/resend/", s.resendWebhook)
+	s.handle("GET /v1/agents/*/jobs/", s.listJobs)
+	s.handle("POST /v1/agents/*/jobs/cancel-all/", s.cancelAllJobs)
+}
+
+func (s *Server) createJob(a *fakeAgent, versionID string, inputs, metadata map[string]string) *fakeJob {
+	lifecycle, ok := s.agentLifecycles[a.agent.ID]
+	if !ok {
+		lifecycle = s.defaultLifecycle
+	}
+	now := time.Now().UTC()
+	j := &fakeJob{
+		id:        s.newID(),
+		agentID:   a.agent.ID,
+		versionID: versionID,
+		createdAt: now,
+		updatedAt: now,
+		inputs:    inputs,
+		metadata:  metadata,
+		lifecycle: lifecycle,
+	}
+	s.jobs[j.id] = j
+	s.jobOrder = append(s.jobOrder, j.id)
+	return j
+}
+
+// runTarget resolves the agent and version of a run route.
+func (s *Server) runTarget(w http.ResponseWriter, params []string) (*fakeAgent, string, bool) {
+	if len(params) == 2 {
+		a, v := s.findVersion(w, params[0], params[1])
+		if v == nil {
+			return nil, "", false
+		}
+		return a, v.ID, true
+	}
+	a := s.findAgent(w, params[0])
+	if a == nil {
+		return nil, "", false
+	}
+	versionID := ""
+	if a.agent.CurrentVersionID != nil {
+		versionID = *a.agent.CurrentVersionID
+	}
+	return a, versionID, true
+}
+
+// parseRunInputs reads a form-encoded or multipart run request.
+func parseRunInputs(r *http.Request) (inputs, metadata map[string]string, err error) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return nil, nil, err
+	}
+	inputs = map[string]string{}
+	for key, values := range r.PostForm {
+		if key == "metadata" || len(values) == 0 {
+			continue
+		}
+		inputs[key] = values[0]
+	}
+	if r.MultipartForm != nil {
+		for key, files := range r.MultipartForm.File {
+			if len(files) > 0 {
+				inputs[key] = files[0].Filename
+			}
+		}
+	}
+	if raw := r.PostForm.Get("metadata"); raw != "" {
+		if metadata, err = stringMap(raw); err != nil {
+			return nil, nil, fmt.Errorf("metadata: %w", err)
+		}
+	}
+	return inputs, metadata, nil
+}
+
+func stringMap(raw string) (map[string]string, error) {
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(decoded))
+	for k, v := range decoded {
+		out[k] = stringify(v)
+	}
+	return out, nil
+}
+
+func stringify(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func fingerprint(inputs, metadata map[string]string) string {
+	b, _ := json.Marshal([]map[string]string{inputs, metadata})
+	return string(b)
+}
+
+func (s *Server) runAsync(w http.ResponseWriter, r *http.Request, params []string) {
+	a, versionID, ok := s.runTarget(w, params)
+	if !ok {
+		return
+	}
+	inputs, metadata, err := parseRunInputs(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	fp := fingerprint(inputs, metadata)
+	if s.replayIdempotent(w, r, fp) {
+		return
+	}
+	j := s.createJob(a, versionID, inputs, metadata)
+	s.storeIdempotent(r, fp, http.StatusOK, j.id)
+	writeJSON(w, http.StatusOK, j.id)
+}
+
+// runSync completes the job immediately, ignoring PendingPolls and
+// StartedPolls, and returns its outputs.
+func (s *Server) runSync(w http.ResponseWriter, r *http.Request, params []string) {
+	a, versionID, ok := s.runTarget(w, params)
+	if !ok {
+		return
+	}
+	inputs, metadata, err := parseRunInputs(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	j := s.createJob(a, versionID, inputs, metadata)
+	j.polls = j.lifecycle.PendingPolls + j.lifecycle.StartedPolls
+	if msg := j.errorMessage(); msg != nil {
+		writeError(w, http.StatusInternalServerError, *msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, j.outputs())
+}
+
+func (s *Server) runMany(w http.ResponseWriter, r *http.Request, params []string) {
+	a, versionID, ok := s.runTarget(w, params)
+	if !ok {
+		return
+	}
+	var p struct {
+		Inputs   []map[string]any `json:"inputs"`
+		Metadata map[string]any   `json:"metadata"`
+	}
+	if !decodeJSON(w, r, &p) {
+		return
+	}
+	if len(p.Inputs) == 0 {
+		writeFieldErrors(w, "inputs")
+		return
+	}
+	raw, _ := json.Marshal(p)
+	fp := string(raw)
+	if s.replayIdempotent(w, r, fp) {
+		return
+	}
+	var metadata map[string]string
+	if p.Metadata != nil {
+		metadata = map[string]string{}
+		for k, v := range p.Metadata {
+			metadata[k] = stringify(v)
+		}
+	}
+	ids := make([]string, 0, len(p.Inputs))
+	for _, in := range p.Inputs {
+		inputs := make(map[string]string, len(in))
+		for k, v := range in {
+			inputs[k] = stringify(v)
+		}
+		ids = append(ids, s.createJob(a, versionID, inputs, metadata).id)
+	}
+	s.storeIdempotent(r, fp, http.StatusOK, ids)
+	writeJSON(w, http.StatusOK, ids)
+}
+
+func (s *Server) findJob(w http.ResponseWriter, id string) *fakeJob {
+	j, ok := s.jobs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found.")
+	}
+	return j
+}
+
+func (s *Server) jobStatus(w http.ResponseWriter, _ *http.Request, params []string) {
+	j := s.findJob(w, params[0])
+	if j == nil {
+		return
+	}
+	st := j.poll()
+	writeJSON(w, http.StatusOK, roe.AgentJobStatus{Status: st, Timestamp: timestamp(j.updatedAt), ErrorMessage: j.errorMessage()})
+}
+
+func (s *Server) jobResult(w http.ResponseWriter, _ *http.Request, params []string) {
+	j := s.findJob(w, params[0])
+	if j == nil {
+		return
+	}
+	if !j.status().IsTerminal() {
+		writeError(w, http.StatusBadRequest, "Job has not finished yet.")
+		return
+	}
+	writeJSON(w, http.StatusOK, roe.AgentJobResult{
+		AgentID:        j.agentID,
+		AgentVersionID: j.versionID,
+		Inputs:         j.inputList(),
+		Outputs:        j.outputs(),
+	})
+}
+
+func (s *Server) jobStatuses(w http.ResponseWriter, r *http.Request, _ []string) {
+	var p struct {
+		JobIDs []string `json:"job_ids"`
+	}
+	if !decodeJSON(w, r, &p) {
+		return
+	}
+	out := []roe.AgentJobStatusBatch{}
+	for _, id := range p.JobIDs {
+		j, ok := s.jobs[id]
+		if !ok {
+			continue
+		}
+		st := j.poll()
+		ts := timestamp(j.updatedAt)
+		out = append(out, roe.AgentJobStatusBatch{
+			ID:            j.id,
+			Status:        &st,
+			CreatedAt:     j.createdAt,
+			LastUpdatedAt: j.updatedAt,
+			Timestamp:     &ts,
+			ErrorMessage:  j.errorMessage(),
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) jobResults(w http.ResponseWriter, r *http.Request, _ []string) {
+	var p struct {
+		JobIDs []string `json:"job_ids"`
+	}
+	if !decodeJSON(w, r, &p) {
+		return
+	}
+	out := []roe.AgentJobResultBatch{}
+	for _, id := range p.JobIDs {
+		j, ok := s.jobs[id]
+		if !ok {
+			continue
+		}
+		st := j.status()
+		agentID, versionID := j.agentID, j.versionID
+		res := roe.AgentJobResultBatch{
+			ID:             j.id,
+			Status:         &st,
+			AgentID:        &agentID,
+			AgentVersionID: &versionID,
+			Inputs:         j.inputList(),
+		}
+		if st.IsTerminal() {
+			res.Result = j.outputs()
+		}
+		out = append(out, res)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) cancelJob(w http.ResponseWriter, _ *http.Request, params []string) {
+	j := s.findJob(w, params[0])
+	if j == nil {
+		return
+	}
+	if j.status().IsTerminal() {
+		writeError(w, http.StatusBadRequest, "Job has already finished.")
+		return
+	}
+	j.cancelled = true
+	j.updatedAt = time.Now().UTC()
+	writeJSON(w, http.StatusOK, map[string]string{"status": "cancelled"})
+}
+
+func (s *Server) deleteJobData(w http.ResponseWriter, _ *http.Request, params []string) {
+	j := s.findJob(w, params[0])
+	if j == nil {
+		return
+	}
+	deleted := len(j.inputs)
+	j.deleted = true
+	j.inputs = map[string]string{}
+	writeJSON(w, http.StatusOK, roe.JobDataDeleteResponse{Status: "success", DeletedCount: deleted, OutputsSanitized: true, Errors: []string{}})
+}
+
+func (s *Server) resendWebhook(w http.ResponseWriter, _ *http.Request, params []string) {
+	if j := s.findJob(w, params[0]); j != nil {
+		writeJSON(w, http.StatusOK, roe.AgentJobWebhookResendResponse{Status: "queued"})
+	}
+}
+
+func (s *Server) listJobs(w http.ResponseWriter, r *http.Request, params []string) {
+	a := s.findAgent(w, params[0])
+	if a == nil {
+		return
+	}
+	statusFilter := r.URL.Query().Get("status_code")
+	jobs := []map[string]any{}
+	for _, id := range s.jobOrder {
+		j := s.jobs[id]
+		if j.agentID != a.agent.ID {
+			continue
+		}
+		st := j.status()
+		if statusFilter != "" && !containsStatus(statusFilter, st) {
+			continue
+		}
+		versionName := ""
+		for _, v := range a.versions {
+			if v.ID == j.versionID {
+				versionName = v.VersionName
+			}
+		}
+		jobs = append(jobs, map[string]any{
+			"id":                 j.id,
+			"agent_version_name": versionName,
+			"created_at":         j.createdAt,
+			"last_updated_at":    j.updatedAt,
+			"creator":            map[string]any{},
+			"evaluation":         map[string]any{},
+			"feedback_review":    map[string]any{},
+			"metadata":           j.metadata,
+			"status_code":        int(st),
+			"status_events":      []any{},
+		})
+	}
+	// Newest first, like the API's default ordering.
+	for i, k := 0, len(jobs)-1; i < k; i, k = i+1, k-1 {
+		jobs[i], jobs[k] = jobs[k], jobs[i]
+	}
+	writeJSON(w, http.StatusOK, paginate(r, jobs))
+}
+
+// containsStatus reports whether a comma-separated status_code filter
+// includes st.
+func containsStatus(filter string, st roe.JobStatus) bool {
+	for _, part := range strings.Split(filter, ",") {
+		if code, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && roe.JobStatus(code) == st {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) cancelAllJobs(w http.ResponseWriter, _ *http.Request, params []string) {
+	a := s.findAgent(w, params[0])
+	if a == nil {
+		return
+	}
+	targeted := 0
+	for _, id := range s.jobOrder {
+		if j := s.jobs[id]; j.agentID == a.agent.ID && !j.status().IsTerminal() {
+			j.cancelled = true
+			targeted++
+		}
+	}
+	writeJSON(w, http.StatusOK, roe.AgentJobCancelAllResponse{TargetedCount: targeted})
+}
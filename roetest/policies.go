@@ -0,0 +1,194 @@
+package roetest
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	roe "github.com/roe-ai/roe-golang"
+)
+
+type fakePolicy struct {
+	policy   roe.Policy
+	versions []*roe.PolicyVersion
+}
+
+type policyPayload struct {
+	Name          *string        `json:"name"`
+	Description   *string        `json:"description"`
+	Content       map[string]any `json:"content"`
+	VersionName   string         `json:"version_name"`
+	BaseVersionID *string        `json:"base_version_id"`
+}
+
+func (s *Server) registerPolicyRoutes() {
+	s.handle("GET /v1/policies/", s.listPolicies)
+	s.handle("POST /v1/policies/", s.postPolicy)
+	s.handle("GET /v1/policies/*/", s.getPolicy)
+	s.handle("PATCH /v1/policies/*/", s.updatePolicy)
+	s.handle("PUT /v1/policies/*/", s.updatePolicy)
+	s.handle("DELETE /v1/policies/*/", s.deletePolicy)
+	s.handle("GET /v1/policies/*/versions/", s.listPolicyVersions)
+	s.handle("POST /v1/policies/*/versions/", s.postPolicyVersion)
+	s.handle("GET /v1/policies/*/versions/*/", s.getPolicyVersion)
+}
+
+func nowString() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+// addPolicyVersion appends a version and makes it current.
+func (s *Server) addPolicyVersion(p *fakePolicy, content map[string]any, versionName string, baseVersionID *string) *roe.PolicyVersion {
+	if versionName == "" {
+		versionName = fmt.Sprintf("v%d", len(p.versions)+1)
+	}
+	now := nowString()
+	v := &roe.PolicyVersion{
+		ID:            s.newID(),
+		VersionName:   versionName,
+		Content:       content,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		BaseVersionID: baseVersionID,
+	}
+	p.versions = append(p.versions, v)
+	p.policy.CurrentVersionID = &v.ID
+	p.policy.UpdatedAt = now
+	return v
+}
+
+func (s *Server) renderPolicyVersion(p *fakePolicy, v *roe.PolicyVersion) roe.PolicyVersion {
+	out := *v
+	policy := p.policy
+	out.Policy = &policy
+	return out
+}
+
+func (s *Server) findPolicy(w http.ResponseWriter, id string) *fakePolicy {
+	p, ok := s.policies[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found.")
+	}
+	return p
+}
+
+func (s *Server) listPolicies(w http.ResponseWriter, r *http.Request, _ []string) {
+	policies := make([]roe.Policy, 0, len(s.policyOrder))
+	for _, id := range s.policyOrder {
+		policies = append(policies, s.policies[id].policy)
+	}
+	writeJSON(w, http.StatusOK, paginate(r, policies))
+}
+
+func (s *Server) postPolicy(w http.ResponseWriter, r *http.Request, _ []string) {
+	var body policyPayload
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	var missing []string
+	if body.Name == nil || *body.Name == "" {
+		missing = append(missing, "name")
+	}
+	if body.Content == nil {
+		missing = append(missing, "content")
+	}
+	if len(missing) > 0 {
+		writeFieldErrors(w, missing...)
+		return
+	}
+	now := nowString()
+	p := &fakePolicy{policy: roe.Policy{
+		ID:             s.newID(),
+		Name:           *body.Name,
+		OrganizationID: s.OrganizationID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}}
+	if body.Description != nil {
+		p.policy.Description = *body.Description
+	}
+	s.addPolicyVersion(p, body.Content, body.VersionName, nil)
+	s.policies[p.policy.ID] = p
+	s.policyOrder = append(s.policyOrder, p.policy.ID)
+	writeJSON(w, http.StatusCreated, p.policy)
+}
+
+func (s *Server) getPolicy(w http.ResponseWriter, _ *http.Request, params []string) {
+	if p := s.findPolicy(w, params[0]); p != nil {
+		writeJSON(w, http.StatusOK, p.policy)
+	}
+}
+
+func (s *Server) updatePolicy(w http.ResponseWriter, r *http.Request, params []string) {
+	p := s.findPolicy(w, params[0])
+	if p == nil {
+		return
+	}
+	var body policyPayload
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if r.Method == http.MethodPut && (body.Name == nil || *body.Name == "") {
+		writeFieldErrors(w, "name")
+		return
+	}
+	if body.Name != nil {
+		p.policy.Name = *body.Name
+	}
+	if body.Description != nil {
+		p.policy.Description = *body.Description
+	}
+	p.policy.UpdatedAt = nowString()
+	writeJSON(w, http.StatusOK, p.policy)
+}
+
+func (s *Server) deletePolicy(w http.ResponseWriter, _ *http.Request, params []string) {
+	if p := s.findPolicy(w, params[0]); p != nil {
+		delete(s.policies, p.policy.ID)
+		s.policyOrder = removeID(s.policyOrder, p.policy.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) listPolicyVersions(w http.ResponseWriter, r *http.Request, params []string) {
+	p := s.findPolicy(w, params[0])
+	if p == nil {
+		return
+	}
+	versions := make([]roe.PolicyVersion, 0, len(p.versions))
+	for _, v := range p.versions {
+		versions = append(versions, s.renderPolicyVersion(p, v))
+	}
+	writeJSON(w, http.StatusOK, paginate(r, versions))
+}
+
+func (s *Server) postPolicyVersion(w http.ResponseWriter, r *http.Request, params []string) {
+	p := s.findPolicy(w, params[0])
+	if p == nil {
+		return
+	}
+	var body policyPayload
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Content == nil {
+		writeFieldErrors(w, "content")
+		return
+	}
+	v := s.addPolicyVersion(p, body.Content, body.VersionName, body.BaseVersionID)
+	writeJSON(w, http.StatusCreated, map[string]any{"id": v.ID, "version_name": v.VersionName})
+}
+
+func (s *Server) getPolicyVersion(w http.ResponseWriter, _ *http.Request, params []string) {
+	p := s.findPolicy(w, params[0])
+	if p == nil {
+		return
+	}
+	for _, v := range p.versions {
+		if v.ID == params[1] {
+			writeJSON(w, http.StatusOK, s.renderPolicyVersion(p, v))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not found.")
+}
@@ -0,0 +1,429 @@
+// Package roetest provides an in-process fake of the Roe API for offline
+// tests. The fake is stateful: agents, versions, jobs, policies and tables
+// created through the SDK can be read back, and jobs move through scripted
+// lifecycles as they are polled, so Job.WaitContext and JobBatch.WaitContext
+// can be exercised end to end:
+//
+//	server := roetest.NewServer(t)
+//	server.SetLifecycle(roetest.Lifecycle{PendingPolls: 1, StartedPolls: 2})
+//	client := server.Client()
+//	agent := server.AddAgent("Extractor")
+//	job, _ := client.Agents.Run(agent.ID, 0, map[string]any{"text": "hi"}, nil)
+//	result, err := job.WaitContext(ctx, time.Millisecond, time.Second)
+//
+// Faults inject error responses and latency into matching requests.
+package roetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	roe "github.com/roe-ai/roe-golang"
+)
+
+const (
+	defaultAPIKey         = "roetest-api-key"
+	defaultOrganizationID = "00000000-0000-4000-8000-000000000000"
+	defaultPageSize       = 100
+)
+
+// Server is a fake Roe API served over HTTP on a loopback port.
+type Server struct {
+	// URL is the base URL of the fake, suitable for Config.BaseURL.
+	URL string
+	// APIKey is the only key the fake accepts.
+	APIKey string
+	// OrganizationID is reported on every resource the fake creates.
+	OrganizationID string
+
+	tb     testing.TB
+	srv    *httptest.Server
+	routes []route
+
+	mu               sync.Mutex
+	nextID           int
+	defaultLifecycle Lifecycle
+	agentLifecycles  map[string]Lifecycle
+	faults           []*faultState
+	requests         []RecordedRequest
+	idempotent       map[string]*idempotentResponse
+
+	agents      map[string]*fakeAgent
+	agentOrder  []string
+	jobs        map[string]*fakeJob
+	jobOrder    []string
+	policies    map[string]*fakePolicy
+	policyOrder []string
+	tables      map[string]*fakeTable
+	tableOrder  []string
+	queries     map[string]*fakeQuery
+}
+
+// RecordedRequest is a request received by the fake.
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+}
+
+// Fault makes matching requests slow or fail.
+type Fault struct {
+	// Method and Path select requests; empty values match everything. A Path
+	// ending in "*" matches by prefix.
+	Method string
+	Path   string
+	// Status, when non-zero, is returned instead of the real response, with
+	// Body (default {"detail": "<status text>"}) and Header.
+	Status int
+	Body   string
+	Header http.Header
+	// Latency delays the request before it is answered, or until the client
+	// gives up.
+	Latency time.Duration
+	// Times limits how many requests the fault affects; zero means all.
+	Times int
+}
+
+type faultState struct {
+	Fault
+	hits int
+}
+
+type idempotentResponse struct {
+	fingerprint string
+	status      int
+	body        any
+}
+
+// NewServer starts a fake Roe API and closes it when the test ends.
+func NewServer(tb testing.TB) *Server {
+	tb.Helper()
+	s := &Server{
+		APIKey:          defaultAPIKey,
+		OrganizationID:  defaultOrganizationID,
+		tb:              tb,
+		agentLifecycles: map[string]Lifecycle{},
+		idempotent:      map[string]*idempotentResponse{},
+		agents:          map[string]*fakeAgent{},
+		jobs:            map[string]*fakeJob{},
+		policies:        map[string]*fakePolicy{},
+		tables:          map[string]*fakeTable{},
+		queries:         map[string]*fakeQuery{},
+	}
+	s.registerAgentRoutes()
+	s.registerJobRoutes()
+	s.registerPolicyRoutes()
+	s.registerTableRoutes()
+
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// Close shuts the server down. It is called automatically at test cleanup.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a client for the fake with fast retries. It is closed at
+// test cleanup.
+func (s *Server) Client() *roe.RoeClient {
+	s.tb.Helper()
+	return s.ClientWithParams(roe.ConfigParams{})
+}
+
+// ClientWithParams is Client with extra configuration. APIKey,
+// OrganizationID and BaseURL default to the fake's; retry intervals default
+// to a few milliseconds. The user's config file profiles are never loaded.
+func (s *Server) ClientWithParams(params roe.ConfigParams) *roe.RoeClient {
+	s.tb.Helper()
+	params.NoProfile = true
+	if params.APIKey == "" {
+		params.APIKey = s.APIKey
+	}
+	if params.OrganizationID == "" {
+		params.OrganizationID = s.OrganizationID
+	}
+	if params.BaseURL == "" {
+		params.BaseURL = s.URL
+	}
+	cfg, err := roe.LoadConfigWithParams(params)
+	if err != nil {
+		s.tb.Fatalf("roetest: load config: %v", err)
+	}
+	cfg.RetryInitialInterval = firstDuration(params.RetryInitialInterval, time.Millisecond)
+	cfg.RetryMaxInterval = firstDuration(params.RetryMaxInterval, 10*time.Millisecond)
+	client, err := roe.NewClientWithConfig(cfg)
+	if err != nil {
+		s.tb.Fatalf("roetest: new client: %v", err)
+	}
+	s.tb.Cleanup(client.Close)
+	return client
+}
+
+// Inject adds a fault. Faults are checked in the order they were added and
+// the first match applies.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &faultState{Fault: f})
+}
+
+// ClearFaults removes every injected fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns every request received so far, in arrival order.
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+	})
+	fault := s.matchFault(r)
+	s.mu.Unlock()
+
+	if fault != nil {
+		if fault.Latency > 0 {
+			timer := time.NewTimer(fault.Latency)
+			select {
+			case <-r.Context().Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+		if fault.Status != 0 {
+			writeFault(w, fault.Fault)
+			return
+		}
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+s.APIKey {
+		writeError(w, http.StatusUnauthorized, "Invalid API key.")
+		return
+	}
+
+	// Handlers run under s.mu, so they can use the state directly.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	segments := splitPath(r.URL.Path)
+	for _, rt := range s.routes {
+		if params, ok := rt.match(r.Method, segments); ok {
+			rt.handle(w, r, params)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not found.")
+}
+
+func (s *Server) matchFault(r *http.Request) *faultState {
+	for _, f := range s.faults {
+		if f.Times > 0 && f.hits >= f.Times {
+			continue
+		}
+		if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(f.Path, "*"); ok {
+			if !strings.HasPrefix(r.URL.Path, prefix) {
+				continue
+			}
+		} else if f.Path != "" && f.Path != r.URL.Path {
+			continue
+		}
+		f.hits++
+		return f
+	}
+	return nil
+}
+
+func writeFault(w http.ResponseWriter, f Fault) {
+	for k, values := range f.Header {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+	if f.Body == "" {
+		writeError(w, f.Status, http.StatusText(f.Status))
+		return
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(f.Status)
+	_, _ = w.Write([]byte(f.Body))
+}
+
+// route matches a method and a path pattern where "*" is a single-segment
+// wildcard. Routes are tried in registration order, so literal segments must
+// be registered before wildcards that would shadow them.
+type route struct {
+	method   string
+	segments []string
+	handle   func(w http.ResponseWriter, r *http.Request, params []string)
+}
+
+func (s *Server) handle(pattern string, h func(w http.ResponseWriter, r *http.Request, params []string)) {
+	method, path, _ := strings.Cut(pattern, " ")
+	s.routes = append(s.routes, route{method: method, segments: splitPath(path), handle: h})
+}
+
+func (rt route) match(method string, segments []string) ([]string, bool) {
+	if rt.method != method || len(rt.segments) != len(segments) {
+		return nil, false
+	}
+	var params []string
+	for i, seg := range rt.segments {
+		switch {
+		case seg == "*":
+			params = append(params, segments[i])
+		case seg != segments[i]:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// splitPath returns the path segments; the API's trailing slash is required
+// and kept as a final empty segment.
+func splitPath(path string) []string {
+	return strings.Split(strings.TrimPrefix(path, "/"), "/")
+}
+
+// newID returns a deterministic UUID-shaped ID. Callers hold s.mu.
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextID)
+}
+
+// replayIdempotent answers a request whose Idempotency-Key was seen before.
+// It reports false when the request must be processed normally.
+func (s *Server) replayIdempotent(w http.ResponseWriter, r *http.Request, fingerprint string) bool {
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" {
+		return false
+	}
+	stored, ok := s.idempotent[r.URL.Path+"\x00"+key]
+	if !ok {
+		return false
+	}
+	if stored.fingerprint != fingerprint {
+		writeError(w, http.StatusConflict, "Idempotency-Key was already used with a different request.")
+		return true
+	}
+	writeJSON(w, stored.status, stored.body)
+	return true
+}
+
+func (s *Server) storeIdempotent(r *http.Request, fingerprint string, status int, body any) {
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		s.idempotent[r.URL.Path+"\x00"+key] = &idempotentResponse{fingerprint: fingerprint, status: status, body: body}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, detail string) {
+	writeJSON(w, status, map[string]string{"detail": detail})
+}
+
+// writeFieldErrors writes a DRF-style validation error body.
+func writeFieldErrors(w http.ResponseWriter, fields ...string) {
+	body := map[string][]string{}
+	for _, field := range fields {
+		body[field] = []string{"This field is required."}
+	}
+	writeJSON(w, http.StatusBadRequest, body)
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "JSON parse error - "+err.Error())
+		return false
+	}
+	return true
+}
+
+// paginate slices items DRF-style using the page and page_size query
+// parameters.
+func paginate[T any](r *http.Request, items []T) map[string]any {
+	page := queryInt(r, "page", 1)
+	size := queryInt(r, "page_size", defaultPageSize)
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultPageSize
+	}
+	start := min((page-1)*size, len(items))
+	end := min(start+size, len(items))
+
+	pageURL := func(n int) *string {
+		u := *r.URL
+		u.Scheme, u.Host = "http", r.Host
+		q := u.Query()
+		q.Set("page", strconv.Itoa(n))
+		u.RawQuery = q.Encode()
+		s := u.String()
+		return &s
+	}
+	var next, previous *string
+	if end < len(items) {
+		next = pageURL(page + 1)
+	}
+	if page > 1 {
+		previous = pageURL(page - 1)
+	}
+	results := items[start:end]
+	if results == nil {
+		results = []T{}
+	}
+	return map[string]any{"count": len(items), "next": next, "previous": previous, "results": results}
+}
+
+func queryInt(r *http.Request, key string, fallback int) int {
+	if v, err := strconv.Atoi(r.URL.Query().Get(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func firstDuration(values ...time.Duration) time.Duration {
+	for _, v := range values {
+		if v > 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+func timestamp(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
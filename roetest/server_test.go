@@ -0,0 +1,183 @@
+package roetest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	roe "github.com/roe-ai/roe-golang"
+)
+
+func TestJobWaitFollowsScriptedLifecycle(t *testing.T) {
+	server := NewServer(t)
+	server.SetLifecycle(Lifecycle{PendingPolls: 1, StartedPolls: 2})
+	client := server.Client()
+	agent := server.AddAgent("Echo")
+
+	job, err := client.Agents.Run(agent.ID, 0, map[string]any{"text": "hello"}, map[string]any{"source": "test"})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	result, err := job.WaitContext(context.Background(), time.Millisecond, 5*time.Second)
+	if err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if !result.Succeeded() || len(result.Outputs) != 1 || result.Outputs[0].Key != "text" || result.Outputs[0].Value != "hello" {
+		t.Fatalf("expected the echoed input as output, got %+v", result)
+	}
+	if result.AgentID != agent.ID || result.AgentVersionID != *agent.CurrentVersionID {
+		t.Fatalf("expected agent and version IDs on the result, got %+v", result)
+	}
+
+	state, ok := server.Job(job.ID())
+	if !ok || state.Polls != 3 || state.Metadata["source"] != "test" {
+		t.Fatalf("expected 3 advancing polls and the metadata, got %+v", state)
+	}
+}
+
+func TestJobBatchWaitWithFailuresAndInjectedErrors(t *testing.T) {
+	server := NewServer(t)
+	client := server.Client()
+	agent := server.AddAgent("Flaky")
+	server.SetAgentLifecycle(agent.ID, Lifecycle{StartedPolls: 1, Final: roe.JobFailure, ErrorMessage: "model exploded"})
+	server.Inject(Fault{Method: http.MethodPost, Path: "/v1/agents/jobs/statuses/", Status: http.StatusServiceUnavailable, Times: 1})
+
+	batch, err := client.Agents.RunMany(agent.ID, []map[string]any{{"n": 1}, {"n": 2}, {"n": 3}}, 0, nil)
+	if err != nil {
+		t.Fatalf("run many: %v", err)
+	}
+	results, err := batch.WaitContext(context.Background(), time.Millisecond, 5*time.Second)
+	if err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for _, res := range results {
+		if !res.Failed() || res.ErrorMessage == nil || *res.ErrorMessage != "model exploded" {
+			t.Fatalf("expected a scripted failure, got %+v", res)
+		}
+	}
+
+	var statusCalls int
+	for _, req := range server.Requests() {
+		if req.Path == "/v1/agents/jobs/statuses/" {
+			statusCalls++
+		}
+	}
+	if statusCalls != 3 {
+		t.Fatalf("expected the injected 503 to be retried (3 status calls), got %d", statusCalls)
+	}
+}
+
+func TestLatencyFaultHonoursContext(t *testing.T) {
+	server := NewServer(t)
+	client := server.Client()
+	agent := server.AddAgent("Slow")
+	server.Inject(Fault{Path: "/v1/agents/jobs/*", Latency: time.Minute})
+
+	job, err := client.Agents.Run(agent.ID, 0, map[string]any{"text": "hi"}, nil)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := job.WaitContext(ctx, time.Millisecond, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to time out, got %v", err)
+	}
+}
+
+func TestIdempotentRunReturnsTheSameJob(t *testing.T) {
+	server := NewServer(t)
+	client := server.Client()
+	agent := server.AddAgent("Once")
+	opts := roe.RunOptions{IdempotencyKey: "work-item-7"}
+
+	first, err := client.Agents.Run(agent.ID, 0, map[string]any{"text": "hi"}, nil, opts)
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	second, err := client.Agents.Run(agent.ID, 0, map[string]any{"text": "hi"}, nil, opts)
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if first.ID() != second.ID() {
+		t.Fatalf("expected the same job, got %s and %s", first.ID(), second.ID())
+	}
+
+	_, err = client.Agents.Run(agent.ID, 0, map[string]any{"text": "different"}, nil, opts)
+	var conflict *roe.ConflictError
+	if !errors.As(err, &conflict) || conflict.StatusCode != http.StatusConflict {
+		t.Fatalf("expected a 409 for reused key with new inputs, got %v", err)
+	}
+}
+
+func TestAgentVersionsAndPolicies(t *testing.T) {
+	server := NewServer(t)
+	client := server.Client()
+
+	agent, err := client.Agents.Create("Extractor", "TextExtractionEngine", []map[string]any{{"key": "text", "data_type": "text/plain"}}, map[string]any{"model": "m"}, "", "")
+	if err != nil {
+		t.Fatalf("create agent: %v", err)
+	}
+	version, err := client.Agents.Versions.Create(agent.ID, nil, map[string]any{"model": "m2"}, "v2", "second")
+	if err != nil {
+		t.Fatalf("create version: %v", err)
+	}
+	versions, err := client.Agents.Versions.List(agent.ID)
+	if err != nil || len(versions) != 2 || versions[1].ID != version.ID || versions[1].BaseAgent.ID != agent.ID {
+		t.Fatalf("expected both versions, got %+v (err %v)", versions, err)
+	}
+	if _, err := client.Agents.Versions.Retrieve(agent.ID, "missing", nil); !isNotFound(err) {
+		t.Fatalf("expected NotFoundError, got %v", err)
+	}
+
+	policy, err := client.Policies.Create("Refunds", map[string]any{"rules": []any{"a"}}, "refund policy", "")
+	if err != nil {
+		t.Fatalf("create policy: %v", err)
+	}
+	pv, err := client.Policies.Versions.Create(policy.ID, map[string]any{"rules": []any{"a", "b"}}, "", *policy.CurrentVersionID)
+	if err != nil {
+		t.Fatalf("create policy version: %v", err)
+	}
+	if pv.VersionName != "v2" || pv.Policy == nil || *pv.Policy.CurrentVersionID != pv.ID {
+		t.Fatalf("expected the new version to become current, got %+v", pv)
+	}
+	if _, err := client.Policies.Create("", nil, "", ""); err == nil {
+		t.Fatal("expected a validation error")
+	}
+}
+
+func TestTablesUploadAndQuery(t *testing.T) {
+	server := NewServer(t)
+	client := server.Client()
+
+	csv := "name,city\nada,london\ngrace,arlington\nlinus,portland\n"
+	if _, err := client.Tables.Upload("people", roe.FileUpload{Reader: strings.NewReader(csv), Filename: "people.csv", MimeType: "text/csv"}, true); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	described, err := client.Tables.Describe("people")
+	if err != nil || len(described.Columns) != 2 || described.RowCount == nil || *described.RowCount != 3 {
+		t.Fatalf("unexpected describe: %+v (err %v)", described, err)
+	}
+
+	submitted, err := client.Tables.Query("SELECT * FROM people LIMIT 2", 0)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	result, err := client.Tables.QueryResult(submitted.TableQueryId.String())
+	if err != nil {
+		t.Fatalf("query result: %v", err)
+	}
+	if result.Rows == nil || len(*result.Rows) != 2 || (*result.Rows)[0]["name"] != "ada" {
+		t.Fatalf("expected two rows, got %+v", result)
+	}
+}
+
+func isNotFound(err error) bool {
+	var notFound *roe.NotFoundError
+	return errors.As(err, &notFound)
+}
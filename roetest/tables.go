@@ -0,0 +1,242 @@
+package roetest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const defaultPreviewLimit = 10
+
+type tableColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type fakeTable struct {
+	name    string
+	columns []tableColumn
+	rows    []map[string]any
+}
+
+type fakeQuery struct {
+	id        string
+	createdAt time.Time
+	columns   []tableColumn
+	rows      []map[string]any
+	err       string
+}
+
+var (
+	fromPattern  = regexp.MustCompile("(?i)\\bfrom\\s+[`\"]?([A-Za-z0-9_]+)")
+	limitPattern = regexp.MustCompile(`(?i)\blimit\s+(\d+)`)
+)
+
+// AddTable seeds a table. Every column has ClickHouse type String.
+func (s *Server) AddTable(name string, columns []string, rows [][]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.putTable(name, columns, rows)
+}
+
+func (s *Server) registerTableRoutes() {
+	s.handle("GET /v1/tables/", s.listTables)
+	s.handle("POST /v1/tables/upload/", s.uploadTable)
+	s.handle("POST /v1/tables/query/", s.submitQuery)
+	s.handle("GET /v1/tables/query/*/result/", s.queryResult)
+	s.handle("GET /v1/tables/*/describe/", s.describeTable)
+	s.handle("GET /v1/tables/*/preview/", s.previewTable)
+	s.handle("DELETE /v1/tables/*/", s.deleteTable)
+}
+
+func (s *Server) putTable(name string, columns []string, rows [][]string) *fakeTable {
+	t := &fakeTable{name: name}
+	for _, c := range columns {
+		t.columns = append(t.columns, tableColumn{Name: c, Type: "String"})
+	}
+	for _, row := range rows {
+		record := make(map[string]any, len(columns))
+		for i, c := range columns {
+			if i < len(row) {
+				record[c] = row[i]
+			}
+		}
+		t.rows = append(t.rows, record)
+	}
+	if _, exists := s.tables[name]; !exists {
+		s.tableOrder = append(s.tableOrder, name)
+	}
+	s.tables[name] = t
+	return t
+}
+
+func (s *Server) findTable(w http.ResponseWriter, name string) *fakeTable {
+	t, ok := s.tables[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Table %q not found.", name))
+	}
+	return t
+}
+
+func (s *Server) listTables(w http.ResponseWriter, _ *http.Request, _ []string) {
+	results := make([]map[string]any, 0, len(s.tableOrder))
+	for _, name := range s.tableOrder {
+		results = append(results, map[string]any{"name": name, "columns": s.tables[name].columns})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"results": results, "total": len(results)})
+}
+
+func (s *Server) uploadTable(w http.ResponseWriter, r *http.Request, _ []string) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	name := r.FormValue("table_name")
+	file, _, err := r.FormFile("file")
+	if name == "" || err != nil {
+		var missing []string
+		if name == "" {
+			missing = append(missing, "table_name")
+		}
+		if err != nil {
+			missing = append(missing, "file")
+		}
+		writeFieldErrors(w, missing...)
+		return
+	}
+	defer file.Close()
+	if _, exists := s.tables[name]; exists {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Table %q already exists.", name))
+		return
+	}
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "Invalid CSV: "+err.Error())
+		return
+	}
+	var columns []string
+	if withHeaders, _ := strconv.ParseBool(r.FormValue("with_headers")); withHeaders && len(records) > 0 {
+		columns, records = records[0], records[1:]
+	} else if len(records) > 0 {
+		// ClickHouse names headerless CSV columns c1, c2, ...
+		for i := range records[0] {
+			columns = append(columns, fmt.Sprintf("c%d", i+1))
+		}
+	}
+	s.putTable(name, columns, records)
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"organization_id": s.OrganizationID,
+		"table_name":      name,
+		"summary":         map[string]any{"written_rows": len(records)},
+	})
+}
+
+func (s *Server) describeTable(w http.ResponseWriter, _ *http.Request, params []string) {
+	if t := s.findTable(w, params[0]); t != nil {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"table_name": t.name,
+			"columns":    t.columns,
+			"row_count":  len(t.rows),
+			"updated_at": nil,
+		})
+	}
+}
+
+func (s *Server) previewTable(w http.ResponseWriter, r *http.Request, params []string) {
+	t := s.findTable(w, params[0])
+	if t == nil {
+		return
+	}
+	rows := limitRows(t.rows, queryInt(r, "limit", defaultPreviewLimit))
+	writeJSON(w, http.StatusOK, map[string]any{
+		"table_name": t.name,
+		"columns":    t.columns,
+		"row_count":  len(rows),
+		"rows":       rows,
+	})
+}
+
+func (s *Server) deleteTable(w http.ResponseWriter, _ *http.Request, params []string) {
+	if t := s.findTable(w, params[0]); t != nil {
+		delete(s.tables, t.name)
+		s.tableOrder = removeID(s.tableOrder, t.name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// submitQuery understands just enough SQL for tests: the first table named
+// after FROM is returned in full, cut to the LIMIT clause or the limit field.
+func (s *Server) submitQuery(w http.ResponseWriter, r *http.Request, _ []string) {
+	var body struct {
+		SQL   string `json:"sql"`
+		Limit int    `json:"limit"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.SQL == "" {
+		writeFieldErrors(w, "sql")
+		return
+	}
+	q := &fakeQuery{id: s.newID(), createdAt: time.Now().UTC()}
+	match := fromPattern.FindStringSubmatch(body.SQL)
+	if match == nil {
+		q.err = "Query must select FROM a table."
+	} else if t, ok := s.tables[match[1]]; !ok {
+		q.err = fmt.Sprintf("Unknown table %s.", match[1])
+	} else {
+		limit := body.Limit
+		if m := limitPattern.FindStringSubmatch(body.SQL); m != nil {
+			if n, _ := strconv.Atoi(m[1]); limit <= 0 || n < limit {
+				limit = n
+			}
+		}
+		q.columns = t.columns
+		q.rows = limitRows(t.rows, limit)
+	}
+	s.queries[q.id] = q
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"table_query_id": q.id,
+		"status":         "PENDING",
+		"created_at":     q.createdAt,
+	})
+}
+
+func (s *Server) queryResult(w http.ResponseWriter, _ *http.Request, params []string) {
+	q, ok := s.queries[params[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	if q.err != "" {
+		writeJSON(w, http.StatusOK, map[string]any{"table_query_id": q.id, "status": "FAILURE", "error": q.err})
+		return
+	}
+	columns := make([]map[string]any, 0, len(q.columns))
+	for _, c := range q.columns {
+		columns = append(columns, map[string]any{"name": c.Name, "type": c.Type})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"table_query_id":    q.id,
+		"status":            "SUCCESS",
+		"columns":           columns,
+		"rows":              q.rows,
+		"row_count":         len(q.rows),
+		"truncated":         false,
+		"execution_time_ms": 1.0,
+	})
+}
+
+func limitRows(rows []map[string]any, limit int) []map[string]any {
+	if rows == nil {
+		rows = []map[string]any{}
+	}
+	if limit > 0 && limit < len(rows) {
+		return rows[:limit]
+	}
+	return rows
+}
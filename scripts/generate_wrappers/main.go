@@ -226,10 +226,7 @@ func writeAPI(root, modulePath, apiName string, api apiSpec) {
 
 func renderSimpleOperation(buf *bytes.Buffer, receiver string, op operation) {
 	params := goParams(op.Parameters)
-	callArgs := goParamNames(op.Parameters)
-	if callArgs != "" {
-		callArgs = ", " + callArgs
-	}
+	callArgs := ", " + goParamNames(op.Parameters)
 
 	fmt.Fprintf(buf, "// %s %s\n", op.MethodName, sentence(op.Docstring))
 	fmt.Fprintf(buf, "func (a *%s) %s(%s) (%s, error) {\n", receiver, op.MethodName, params, op.ReturnType)
@@ -237,14 +234,12 @@ func renderSimpleOperation(buf *bytes.Buffer, receiver string, op operation) {
 	buf.WriteString("}\n\n")
 
 	fmt.Fprintf(buf, "// %sWithContext %s\n", op.MethodName, sentence(op.Docstring))
-	fmt.Fprintf(buf, "func (a *%s) %sWithContext(ctx context.Context", receiver, op.MethodName)
-	for _, param := range op.Parameters {
-		fmt.Fprintf(buf, ", %s %s", param.Name, param.GoType)
-	}
-	fmt.Fprintf(buf, ") (%s, error) {\n", op.ReturnType)
+	fmt.Fprintf(buf, "func (a *%s) %sWithContext(ctx context.Context, %s", receiver, op.MethodName, params)
+	fmt.Fprintf(buf, ") (_ %s, err error) {\n", op.ReturnType)
+	writeSpan(buf, receiver, op)
 	writeQueryMap(buf, op.Parameters, false)
 	fmt.Fprintf(buf, "\tvar resp %s\n", op.ReturnType)
-	fmt.Fprintf(buf, "\tif err := a.httpClient.getWithContext(ctx, %q, query, &resp); err != nil {\n", op.Path)
+	fmt.Fprintf(buf, "\tif err := a.httpClient.getWithContext(ctx, %q, query, &resp, opts...); err != nil {\n", op.Path)
 	fmt.Fprintf(buf, "\t\treturn %s{}, err\n", op.ReturnType)
 	buf.WriteString("\t}\n")
 	buf.WriteString("\treturn resp, nil\n")
@@ -253,12 +248,13 @@ func renderSimpleOperation(buf *bytes.Buffer, receiver string, op operation) {
 
 func renderTableUploadOperation(buf *bytes.Buffer, receiver string, op operation) {
 	fmt.Fprintf(buf, "// %s %s\n", op.MethodName, sentence(op.Docstring))
-	fmt.Fprintf(buf, "func (a *%s) %s(tableName string, file FileUpload, withHeaders bool) (%s, error) {\n", receiver, op.MethodName, op.ReturnType)
-	fmt.Fprintf(buf, "\treturn a.%sWithContext(context.Background(), tableName, file, withHeaders)\n", op.MethodName)
+	fmt.Fprintf(buf, "func (a *%s) %s(tableName string, file FileUpload, withHeaders bool, opts ...RequestOption) (%s, error) {\n", receiver, op.MethodName, op.ReturnType)
+	fmt.Fprintf(buf, "\treturn a.%sWithContext(context.Background(), tableName, file, withHeaders, opts...)\n", op.MethodName)
 	buf.WriteString("}\n\n")
 
 	fmt.Fprintf(buf, "// %sWithContext %s\n", op.MethodName, sentence(op.Docstring))
-	fmt.Fprintf(buf, "func (a *%s) %sWithContext(ctx context.Context, tableName string, file FileUpload, withHeaders bool) (%s, error) {\n", receiver, op.MethodName, op.ReturnType)
+	fmt.Fprintf(buf, "func (a *%s) %sWithContext(ctx context.Context, tableName string, file FileUpload, withHeaders bool, opts ...RequestOption) (_ %s, err error) {\n", receiver, op.MethodName, op.ReturnType)
+	writeSpan(buf, receiver, op)
 	buf.WriteString("\tinputs := map[string]any{\n")
 	buf.WriteString("\t\t\"table_name\": tableName,\n")
 	buf.WriteString("\t\t\"file\": file,\n")
@@ -266,7 +262,7 @@ func renderTableUploadOperation(buf *bytes.Buffer, receiver string, op operation
 	buf.WriteString("\t\t\"organization_id\": a.cfg.OrganizationID,\n")
 	buf.WriteString("\t}\n")
 	fmt.Fprintf(buf, "\tvar resp %s\n", op.ReturnType)
-	fmt.Fprintf(buf, "\tif err := a.httpClient.postDynamicInputsWithContext(ctx, %q, inputs, nil, &resp, nil); err != nil {\n", op.Path)
+	fmt.Fprintf(buf, "\tif err := a.httpClient.postDynamicInputsWithContext(ctx, %q, inputs, nil, &resp, nil, opts...); err != nil {\n", op.Path)
 	fmt.Fprintf(buf, "\t\treturn %s{}, err\n", op.ReturnType)
 	buf.WriteString("\t}\n")
 	buf.WriteString("\treturn resp, nil\n")
@@ -275,13 +271,12 @@ func renderTableUploadOperation(buf *bytes.Buffer, receiver string, op operation
 
 func renderBodyOperation(buf *bytes.Buffer, receiver string, op operation) {
 	params := goParams(op.Parameters)
-	callArgs := goParamNames(op.Parameters)
-	if callArgs != "" {
-		callArgs = ", " + callArgs
-	}
+	callArgs := ", " + goParamNames(op.Parameters)
 	returns := "error"
+	namedReturns := "(err error)"
 	if op.ReturnType != "" {
 		returns = fmt.Sprintf("(%s, error)", op.ReturnType)
+		namedReturns = fmt.Sprintf("(_ %s, err error)", op.ReturnType)
 	}
 
 	fmt.Fprintf(buf, "// %s %s\n", op.MethodName, sentence(op.Docstring))
@@ -290,11 +285,9 @@ func renderBodyOperation(buf *bytes.Buffer, receiver string, op operation) {
 	buf.WriteString("}\n\n")
 
 	fmt.Fprintf(buf, "// %sWithContext %s\n", op.MethodName, sentence(op.Docstring))
-	fmt.Fprintf(buf, "func (a *%s) %sWithContext(ctx context.Context", receiver, op.MethodName)
-	for _, param := range op.Parameters {
-		fmt.Fprintf(buf, ", %s %s", param.Name, param.GoType)
-	}
-	fmt.Fprintf(buf, ") %s {\n", returns)
+	fmt.Fprintf(buf, "func (a *%s) %sWithContext(ctx context.Context, %s", receiver, op.MethodName, params)
+	fmt.Fprintf(buf, ") %s {\n", namedReturns)
+	writeSpan(buf, receiver, op)
 
 	pathExpr := operationPathExpression(op)
 	queryParams := paramsByLocation(op.Parameters, "query")
@@ -320,6 +313,13 @@ func renderBodyOperation(buf *bytes.Buffer, receiver string, op operation) {
 	buf.WriteString("}\n")
 }
 
+// writeSpan opens the operation span that HTTP attempts are nested under and
+// ends it with the operation's error.
+func writeSpan(buf *bytes.Buffer, receiver string, op operation) {
+	fmt.Fprintf(buf, "\tctx, span := a.httpClient.startSpan(ctx, %q)\n", receiver+"."+op.MethodName)
+	buf.WriteString("\tdefer endSpan(span, &err)\n")
+}
+
 func writeQueryMap(buf *bytes.Buffer, params []parameter, injectOrganizationID bool) {
 	buf.WriteString("\tquery := map[string]string{}\n")
 	if injectOrganizationID {
@@ -368,27 +368,27 @@ func httpCall(op operation, pathExpr string, hasBody bool) string {
 	}
 	switch strings.ToUpper(op.Method) {
 	case "GET":
-		return fmt.Sprintf("a.httpClient.getWithContext(ctx, %s, query, %s)", pathExpr, outArg)
+		return fmt.Sprintf("a.httpClient.getWithContext(ctx, %s, query, %s, opts...)", pathExpr, outArg)
 	case "POST":
 		bodyArg := "nil"
 		if hasBody {
 			bodyArg = "payload"
 		}
-		return fmt.Sprintf("a.httpClient.postJSONWithContext(ctx, %s, %s, query, %s)", pathExpr, bodyArg, outArg)
+		return fmt.Sprintf("a.httpClient.postJSONWithContext(ctx, %s, %s, query, %s, opts...)", pathExpr, bodyArg, outArg)
 	case "PATCH":
 		bodyArg := "nil"
 		if hasBody {
 			bodyArg = "payload"
 		}
-		return fmt.Sprintf("a.httpClient.patchJSONWithContext(ctx, %s, %s, query, %s)", pathExpr, bodyArg, outArg)
+		return fmt.Sprintf("a.httpClient.patchJSONWithContext(ctx, %s, %s, query, %s, opts...)", pathExpr, bodyArg, outArg)
 	case "PUT":
 		bodyArg := "nil"
 		if hasBody {
 			bodyArg = "payload"
 		}
-		return fmt.Sprintf("a.httpClient.putJSONWithContext(ctx, %s, %s, query, %s)", pathExpr, bodyArg, outArg)
+		return fmt.Sprintf("a.httpClient.putJSONWithContext(ctx, %s, %s, query, %s, opts...)", pathExpr, bodyArg, outArg)
 	case "DELETE":
-		return fmt.Sprintf("a.httpClient.deleteWithContext(ctx, %s, query)", pathExpr)
+		return fmt.Sprintf("a.httpClient.deleteWithContext(ctx, %s, query, opts...)", pathExpr)
 	default:
 		must(fmt.Errorf("%s has unsupported HTTP method %q", op.MethodName, op.Method))
 		return ""
@@ -503,19 +503,24 @@ func apiNeedsFmt(api apiSpec) bool {
 	return false
 }
 
+// goParams renders an operation's parameter list, ending with the variadic
+// per-call RequestOptions every wrapper accepts.
 func goParams(params []parameter) string {
-	parts := make([]string, 0, len(params))
+	parts := make([]string, 0, len(params)+1)
 	for _, param := range params {
 		parts = append(parts, fmt.Sprintf("%s %s", param.Name, param.GoType))
 	}
+	parts = append(parts, "opts ...RequestOption")
 	return strings.Join(parts, ", ")
 }
 
+// goParamNames renders the arguments that forward goParams to another call.
 func goParamNames(params []parameter) string {
-	names := make([]string, 0, len(params))
+	names := make([]string, 0, len(params)+1)
 	for _, param := range params {
 		names = append(names, param.Name)
 	}
+	names = append(names, "opts...")
 	return strings.Join(names, ", ")
 }
 
@@ -1,6 +1,10 @@
 package main
 
-import "testing"
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
 
 func TestOperationPathExpressionUsesPathTemplateOrder(t *testing.T) {
 	op := operation{
@@ -87,3 +91,40 @@ func TestOmitWhenEmptyConditionSupportsTypedMaps(t *testing.T) {
 		t.Fatal("expected an error for a genuinely unsupported go_type")
 	}
 }
+
+func TestRenderedOperationsOpenSpan(t *testing.T) {
+	cases := []struct {
+		name   string
+		render func(*bytes.Buffer)
+		want   string
+	}{
+		{
+			name: "simple",
+			render: func(buf *bytes.Buffer) {
+				renderSimpleOperation(buf, "TablesAPI", operation{Kind: "simple", MethodName: "List", Path: "/v1/tables/", ReturnType: "generated.TableListResponse"})
+			},
+			want: "(_ generated.TableListResponse, err error) {\n\tctx, span := a.httpClient.startSpan(ctx, \"TablesAPI.List\")\n\tdefer endSpan(span, &err)\n",
+		},
+		{
+			name: "body without result",
+			render: func(buf *bytes.Buffer) {
+				renderBodyOperation(buf, "TablesAPI", operation{Kind: "body", Method: "POST", MethodName: "Refresh", Path: "/v1/tables/refresh/"})
+			},
+			want: "(err error) {\n\tctx, span := a.httpClient.startSpan(ctx, \"TablesAPI.Refresh\")\n\tdefer endSpan(span, &err)\n",
+		},
+		{
+			name: "table upload",
+			render: func(buf *bytes.Buffer) {
+				renderTableUploadOperation(buf, "TablesAPI", operation{Kind: "table_upload", MethodName: "Upload", Path: "/v1/tables/upload/", ReturnType: "generated.TableUploadResponse"})
+			},
+			want: "(_ generated.TableUploadResponse, err error) {\n\tctx, span := a.httpClient.startSpan(ctx, \"TablesAPI.Upload\")\n\tdefer endSpan(span, &err)\n",
+		},
+	}
+	for _, tc := range cases {
+		var buf bytes.Buffer
+		tc.render(&buf)
+		if !strings.Contains(buf.String(), tc.want) {
+			t.Fatalf("%s: expected rendered wrapper to open a span that records its error, got:\n%s", tc.name, buf.String())
+		}
+	}
+}
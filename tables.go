@@ -19,27 +19,31 @@ func newTablesAPI(cfg Config, httpClient *httpClient) *TablesAPI {
 }
 
 // List List Roe tables.
-func (a *TablesAPI) List() (generated.TableListResponse, error) {
-	return a.ListWithContext(context.Background())
+func (a *TablesAPI) List(opts ...RequestOption) (generated.TableListResponse, error) {
+	return a.ListWithContext(context.Background(), opts...)
 }
 
 // ListWithContext List Roe tables.
-func (a *TablesAPI) ListWithContext(ctx context.Context) (generated.TableListResponse, error) {
+func (a *TablesAPI) ListWithContext(ctx context.Context, opts ...RequestOption) (_ generated.TableListResponse, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "TablesAPI.List")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	var resp generated.TableListResponse
-	if err := a.httpClient.getWithContext(ctx, "/v1/tables/", query, &resp); err != nil {
+	if err := a.httpClient.getWithContext(ctx, "/v1/tables/", query, &resp, opts...); err != nil {
 		return generated.TableListResponse{}, err
 	}
 	return resp, nil
 }
 
 // Upload Upload a CSV file and create a Roe table.
-func (a *TablesAPI) Upload(tableName string, file FileUpload, withHeaders bool) (generated.TableUploadResponse, error) {
-	return a.UploadWithContext(context.Background(), tableName, file, withHeaders)
+func (a *TablesAPI) Upload(tableName string, file FileUpload, withHeaders bool, opts ...RequestOption) (generated.TableUploadResponse, error) {
+	return a.UploadWithContext(context.Background(), tableName, file, withHeaders, opts...)
 }
 
 // UploadWithContext Upload a CSV file and create a Roe table.
-func (a *TablesAPI) UploadWithContext(ctx context.Context, tableName string, file FileUpload, withHeaders bool) (generated.TableUploadResponse, error) {
+func (a *TablesAPI) UploadWithContext(ctx context.Context, tableName string, file FileUpload, withHeaders bool, opts ...RequestOption) (_ generated.TableUploadResponse, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "TablesAPI.Upload")
+	defer endSpan(span, &err)
 	inputs := map[string]any{
 		"table_name":      tableName,
 		"file":            file,
@@ -47,19 +51,21 @@ func (a *TablesAPI) UploadWithContext(ctx context.Context, tableName string, fil
 		"organization_id": a.cfg.OrganizationID,
 	}
 	var resp generated.TableUploadResponse
-	if err := a.httpClient.postDynamicInputsWithContext(ctx, "/v1/tables/upload/", inputs, nil, &resp, nil); err != nil {
+	if err := a.httpClient.postDynamicInputsWithContext(ctx, "/v1/tables/upload/", inputs, nil, &resp, nil, opts...); err != nil {
 		return generated.TableUploadResponse{}, err
 	}
 	return resp, nil
 }
 
 // Query Run a read-only query against Roe tables.
-func (a *TablesAPI) Query(sql string, limit int) (generated.TableQuerySubmitResponse, error) {
-	return a.QueryWithContext(context.Background(), sql, limit)
+func (a *TablesAPI) Query(sql string, limit int, opts ...RequestOption) (generated.TableQuerySubmitResponse, error) {
+	return a.QueryWithContext(context.Background(), sql, limit, opts...)
 }
 
 // QueryWithContext Run a read-only query against Roe tables.
-func (a *TablesAPI) QueryWithContext(ctx context.Context, sql string, limit int) (generated.TableQuerySubmitResponse, error) {
+func (a *TablesAPI) QueryWithContext(ctx context.Context, sql string, limit int, opts ...RequestOption) (_ generated.TableQuerySubmitResponse, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "TablesAPI.Query")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	payload := map[string]any{}
 	payload["sql"] = sql
@@ -67,67 +73,75 @@ func (a *TablesAPI) QueryWithContext(ctx context.Context, sql string, limit int)
 		payload["limit"] = limit
 	}
 	var resp generated.TableQuerySubmitResponse
-	if err := a.httpClient.postJSONWithContext(ctx, "/v1/tables/query/", payload, query, &resp); err != nil {
+	if err := a.httpClient.postJSONWithContext(ctx, "/v1/tables/query/", payload, query, &resp, opts...); err != nil {
 		return generated.TableQuerySubmitResponse{}, err
 	}
 	return resp, nil
 }
 
 // QueryResult Get the result for a submitted table query.
-func (a *TablesAPI) QueryResult(tableQueryID string) (generated.TableQueryResultResponse, error) {
-	return a.QueryResultWithContext(context.Background(), tableQueryID)
+func (a *TablesAPI) QueryResult(tableQueryID string, opts ...RequestOption) (generated.TableQueryResultResponse, error) {
+	return a.QueryResultWithContext(context.Background(), tableQueryID, opts...)
 }
 
 // QueryResultWithContext Get the result for a submitted table query.
-func (a *TablesAPI) QueryResultWithContext(ctx context.Context, tableQueryID string) (generated.TableQueryResultResponse, error) {
+func (a *TablesAPI) QueryResultWithContext(ctx context.Context, tableQueryID string, opts ...RequestOption) (_ generated.TableQueryResultResponse, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "TablesAPI.QueryResult")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	var resp generated.TableQueryResultResponse
-	if err := a.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/tables/query/%s/result/", tableQueryID), query, &resp); err != nil {
+	if err := a.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/tables/query/%s/result/", tableQueryID), query, &resp, opts...); err != nil {
 		return generated.TableQueryResultResponse{}, err
 	}
 	return resp, nil
 }
 
 // Describe Describe a Roe table.
-func (a *TablesAPI) Describe(tableName string) (generated.TableDescribeResponse, error) {
-	return a.DescribeWithContext(context.Background(), tableName)
+func (a *TablesAPI) Describe(tableName string, opts ...RequestOption) (generated.TableDescribeResponse, error) {
+	return a.DescribeWithContext(context.Background(), tableName, opts...)
 }
 
 // DescribeWithContext Describe a Roe table.
-func (a *TablesAPI) DescribeWithContext(ctx context.Context, tableName string) (generated.TableDescribeResponse, error) {
+func (a *TablesAPI) DescribeWithContext(ctx context.Context, tableName string, opts ...RequestOption) (_ generated.TableDescribeResponse, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "TablesAPI.Describe")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	var resp generated.TableDescribeResponse
-	if err := a.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/tables/%s/describe/", tableName), query, &resp); err != nil {
+	if err := a.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/tables/%s/describe/", tableName), query, &resp, opts...); err != nil {
 		return generated.TableDescribeResponse{}, err
 	}
 	return resp, nil
 }
 
 // Preview Preview rows from a Roe table.
-func (a *TablesAPI) Preview(tableName string, limit int) (generated.TablePreviewResponse, error) {
-	return a.PreviewWithContext(context.Background(), tableName, limit)
+func (a *TablesAPI) Preview(tableName string, limit int, opts ...RequestOption) (generated.TablePreviewResponse, error) {
+	return a.PreviewWithContext(context.Background(), tableName, limit, opts...)
 }
 
 // PreviewWithContext Preview rows from a Roe table.
-func (a *TablesAPI) PreviewWithContext(ctx context.Context, tableName string, limit int) (generated.TablePreviewResponse, error) {
+func (a *TablesAPI) PreviewWithContext(ctx context.Context, tableName string, limit int, opts ...RequestOption) (_ generated.TablePreviewResponse, err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "TablesAPI.Preview")
+	defer endSpan(span, &err)
 	query := map[string]string{}
 	if limit != 0 {
 		query["limit"] = fmt.Sprint(limit)
 	}
 	var resp generated.TablePreviewResponse
-	if err := a.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/tables/%s/preview/", tableName), query, &resp); err != nil {
+	if err := a.httpClient.getWithContext(ctx, fmt.Sprintf("/v1/tables/%s/preview/", tableName), query, &resp, opts...); err != nil {
 		return generated.TablePreviewResponse{}, err
 	}
 	return resp, nil
 }
 
 // Delete Delete a Roe table.
-func (a *TablesAPI) Delete(tableName string) error {
-	return a.DeleteWithContext(context.Background(), tableName)
+func (a *TablesAPI) Delete(tableName string, opts ...RequestOption) error {
+	return a.DeleteWithContext(context.Background(), tableName, opts...)
 }
 
 // DeleteWithContext Delete a Roe table.
-func (a *TablesAPI) DeleteWithContext(ctx context.Context, tableName string) error {
+func (a *TablesAPI) DeleteWithContext(ctx context.Context, tableName string, opts ...RequestOption) (err error) {
+	ctx, span := a.httpClient.startSpan(ctx, "TablesAPI.Delete")
+	defer endSpan(span, &err)
 	query := map[string]string{}
-	return a.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/tables/%s/", tableName), query)
+	return a.httpClient.deleteWithContext(ctx, fmt.Sprintf("/v1/tables/%s/", tableName), query, opts...)
 }
@@ -0,0 +1,106 @@
+package roe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Span attribute keys set by the SDK.
+const (
+	AttrAgentID        = "roe.agent_id"
+	AttrJobID          = "roe.job_id"
+	AttrJobCount       = "roe.job_count"
+	AttrJobStatus      = "roe.job_status"
+	AttrAttempt        = "roe.attempt"
+	AttrRequestID      = "roe.request_id"
+	AttrHTTPMethod     = "http.request.method"
+	AttrURLPath        = "url.path"
+	AttrHTTPStatusCode = "http.response.status_code"
+)
+
+// SpanAttribute is a key/value pair recorded on a Span.
+type SpanAttribute struct {
+	Key   string
+	Value any
+}
+
+// Tracer starts spans for SDK operations. Every public API method opens a
+// span named after it (e.g. "AgentsAPI.Run"), and every HTTP attempt made on
+// its behalf is a child span named "HTTP <method>". The interface is small
+// enough to adapt to OpenTelemetry or any other tracing library without the
+// SDK depending on it.
+type Tracer interface {
+	// Start opens a span as a child of the span in ctx, if any, and returns a
+	// context carrying the new span.
+	Start(ctx context.Context, name string, attrs ...SpanAttribute) (context.Context, Span)
+	// Inject writes the trace context of the span in ctx into outgoing request
+	// headers, typically a W3C traceparent (and tracestate) header.
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is a single traced operation.
+type Span interface {
+	SetAttributes(attrs ...SpanAttribute)
+	RecordError(err error)
+	End()
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...SpanAttribute) {}
+func (noopSpan) RecordError(error)              {}
+func (noopSpan) End()                           {}
+
+// startSpan opens the span for a public operation. The operation ends it
+// with endSpan, which records the error it returns.
+func (c *httpClient) startSpan(ctx context.Context, name string, attrs ...SpanAttribute) (context.Context, Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if c == nil || c.cfg.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return c.cfg.Tracer.Start(ctx, name, attrs...)
+}
+
+// startAttemptSpan opens the child span for one HTTP attempt.
+func (c *httpClient) startAttemptSpan(ctx context.Context, method, path string, attempt int) (context.Context, Span) {
+	if c.cfg.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return c.cfg.Tracer.Start(ctx, "HTTP "+method,
+		SpanAttribute{Key: AttrHTTPMethod, Value: method},
+		SpanAttribute{Key: AttrURLPath, Value: path},
+		SpanAttribute{Key: AttrAttempt, Value: attempt},
+	)
+}
+
+// injectTraceContext propagates the attempt's trace context to the request.
+func (c *httpClient) injectTraceContext(req *http.Request) {
+	if c.cfg.Tracer != nil {
+		c.cfg.Tracer.Inject(req.Context(), req.Header)
+	}
+}
+
+// recordSpanError records err, and the request ID of an API error, on span.
+func recordSpanError(span Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	var apiErr interface{ apiError() *APIError }
+	if errors.As(err, &apiErr) {
+		if requestID := apiErr.apiError().RequestID; requestID != "" {
+			span.SetAttributes(SpanAttribute{Key: AttrRequestID, Value: requestID})
+		}
+	}
+}
+
+// endSpan records the error an operation returned, if any, and ends its
+// span. Operations defer it on their named error result so that every
+// failure, including argument validation, is recorded exactly once.
+func endSpan(span Span, err *error) {
+	recordSpanError(span, *err)
+	span.End()
+}
@@ -0,0 +1,270 @@
+package roe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordedSpan struct {
+	id     int
+	parent int
+	name   string
+	attrs  map[string]any
+	errs   []error
+	ended  bool
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...SpanAttribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) { s.errs = append(s.errs, err) }
+func (s *recordedSpan) End()                  { s.ended = true }
+
+type recordedSpanKey struct{}
+
+// recordingTracer keeps every span and propagates a fake W3C traceparent.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, attrs ...SpanAttribute) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &recordedSpan{id: len(t.spans) + 1, name: name, attrs: map[string]any{}}
+	if parent, ok := ctx.Value(recordedSpanKey{}).(*recordedSpan); ok {
+		span.parent = parent.id
+	}
+	span.SetAttributes(attrs...)
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, recordedSpanKey{}, span), span
+}
+
+func (t *recordingTracer) Inject(ctx context.Context, header http.Header) {
+	if span, ok := ctx.Value(recordedSpanKey{}).(*recordedSpan); ok {
+		header.Set("traceparent", fmt.Sprintf("00-%032x-%016x-01", 1, span.id))
+	}
+}
+
+func TestTracerSpansOperationAndAttempts(t *testing.T) {
+	var traceparents []string
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		if len(traceparents) == 1 {
+			w.Header().Set("X-Request-ID", "req-failed")
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`"job-1"`))
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client, err := NewClientWithConfig(Config{
+		APIKey:               "k",
+		OrganizationID:       "org",
+		BaseURL:              server.URL,
+		Timeout:              time.Second,
+		MaxRetries:           1,
+		RetryInitialInterval: time.Millisecond,
+		RetryMaxInterval:     time.Millisecond,
+		RetryMultiplier:      1,
+		Tracer:               tracer,
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Agents.Run("agent-1", 0, map[string]any{"text": "hi"}, nil); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if len(tracer.spans) != 3 {
+		t.Fatalf("expected 1 operation span and 2 attempt spans, got %d", len(tracer.spans))
+	}
+	op := tracer.spans[0]
+	if op.name != "AgentsAPI.Run" || op.parent != 0 || !op.ended {
+		t.Fatalf("unexpected operation span: %+v", op)
+	}
+	if op.attrs[AttrAgentID] != "agent-1" || op.attrs[AttrJobID] != "job-1" {
+		t.Fatalf("expected agent and job IDs on the operation span, got %v", op.attrs)
+	}
+	if len(op.errs) != 0 {
+		t.Fatalf("expected the retried failure not to fail the operation, got %v", op.errs)
+	}
+
+	for i, attempt := range tracer.spans[1:] {
+		if attempt.name != "HTTP POST" || attempt.parent != op.id || !attempt.ended {
+			t.Fatalf("unexpected attempt span: %+v", attempt)
+		}
+		if attempt.attrs[AttrAttempt] != i {
+			t.Fatalf("expected attempt %d, got %v", i, attempt.attrs[AttrAttempt])
+		}
+		if want := fmt.Sprintf("00-%032x-%016x-01", 1, attempt.id); traceparents[i] != want {
+			t.Fatalf("expected traceparent %q, got %q", want, traceparents[i])
+		}
+	}
+	failed := tracer.spans[1]
+	if failed.attrs[AttrHTTPStatusCode] != http.StatusBadGateway || failed.attrs[AttrRequestID] != "req-failed" || len(failed.errs) != 1 {
+		t.Fatalf("expected the failed attempt to record status, request ID and error, got %+v", failed)
+	}
+}
+
+func TestTracerRecordsFinalErrorOnOperationSpan(t *testing.T) {
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "req-404")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"detail":"missing"}`))
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client, err := NewClientWithConfig(Config{
+		APIKey:         "k",
+		OrganizationID: "org",
+		BaseURL:        server.URL,
+		Timeout:        time.Second,
+		Tracer:         tracer,
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.Agents.Jobs.RetrieveStatus("job-9")
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected NotFoundError, got %v", err)
+	}
+
+	op := tracer.spans[0]
+	if op.name != "AgentJobsAPI.RetrieveStatus" || op.attrs[AttrJobID] != "job-9" {
+		t.Fatalf("unexpected operation span: %+v", op)
+	}
+	if len(op.errs) != 1 || op.attrs[AttrRequestID] != "req-404" {
+		t.Fatalf("expected the final error and request ID on the operation span, got %+v", op)
+	}
+}
+
+func TestTracerRecordsEachOperationErrorOnce(t *testing.T) {
+	polls := 0
+	server := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"detail":"missing"}`))
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client, err := NewClientWithConfig(Config{
+		APIKey:         "k",
+		OrganizationID: "org",
+		BaseURL:        server.URL,
+		Timeout:        time.Second,
+		MaxRetries:     0,
+		Tracer:         tracer,
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Agents.Run("", 0, nil, nil); err == nil {
+		t.Fatalf("expected a validation error")
+	}
+	run := tracer.spans[0]
+	if run.name != "AgentsAPI.Run" || len(run.errs) != 1 || !run.ended {
+		t.Fatalf("expected the validation error on the ended run span, got %+v", run)
+	}
+
+	job := newJob(client.Agents, "job-1", 0)
+	if _, err := job.WaitContext(context.Background(), time.Millisecond, time.Second); err == nil {
+		t.Fatalf("expected the wait to fail")
+	}
+	for _, span := range tracer.spans[1:] {
+		switch span.name {
+		case "Job.Wait", "AgentJobsAPI.RetrieveStatus":
+			if len(span.errs) != 1 {
+				t.Fatalf("expected one error on %s, got %v", span.name, span.errs)
+			}
+		}
+	}
+	if wait := tracer.spans[1]; wait.name != "Job.Wait" {
+		t.Fatalf("expected the Job.Wait span, got %s", wait.name)
+	}
+}
+
+// TestPublicContextMethodsOpenSpans catches WithContext methods, hand-written
+// or generated, that neither open a span nor delegate to one that does.
+func TestPublicContextMethodsOpenSpans(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Body == nil || !fn.Name.IsExported() || !strings.HasSuffix(fn.Name.Name, "WithContext") {
+				continue
+			}
+			if delegatesToContextMethod(fn) {
+				continue
+			}
+			opensSpan := false
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == "startSpan" {
+					opensSpan = true
+				}
+				return !opensSpan
+			})
+			if !opensSpan {
+				t.Errorf("%s: %s opens no span", fset.Position(fn.Pos()), fn.Name.Name)
+			}
+		}
+	}
+}
+
+// delegatesToContextMethod reports whether fn calls another public
+// WithContext method, whose span then covers the operation.
+func delegatesToContextMethod(fn *ast.FuncDecl) bool {
+	delegates := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return !delegates
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.IsExported() && strings.HasSuffix(sel.Sel.Name, "WithContext") {
+			delegates = true
+		}
+		return !delegates
+	})
+	return delegates
+}
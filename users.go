@@ -17,14 +17,16 @@ func newUsersAPI(cfg Config, httpClient *httpClient) *UsersAPI {
 }
 
 // Me retrieves the currently authenticated user.
-func (u *UsersAPI) Me() (generated.User, error) {
-	return u.MeWithContext(context.Background())
+func (u *UsersAPI) Me(opts ...RequestOption) (generated.User, error) {
+	return u.MeWithContext(context.Background(), opts...)
 }
 
 // MeWithContext retrieves the currently authenticated user with a caller-supplied context.
-func (u *UsersAPI) MeWithContext(ctx context.Context) (generated.User, error) {
+func (u *UsersAPI) MeWithContext(ctx context.Context, opts ...RequestOption) (_ generated.User, err error) {
+	ctx, span := u.httpClient.startSpan(ctx, "UsersAPI.Me")
+	defer endSpan(span, &err)
 	var resp generated.User
-	if err := u.httpClient.getWithContext(ctx, "/v1/users/current_user/", nil, &resp); err != nil {
+	if err := u.httpClient.getWithContext(ctx, "/v1/users/current_user/", nil, &resp, opts...); err != nil {
 		return generated.User{}, err
 	}
 	return resp, nil